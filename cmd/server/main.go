@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/auth"
 	"github.com/Maxito7/hotel_backend/internal/config"
+	"github.com/Maxito7/hotel_backend/internal/domain"
 	"github.com/Maxito7/hotel_backend/internal/email"
 	"github.com/Maxito7/hotel_backend/internal/infrastructure/repository"
 	handlers "github.com/Maxito7/hotel_backend/internal/interfaces/http"
+	"github.com/Maxito7/hotel_backend/internal/jobs"
 	"github.com/Maxito7/hotel_backend/internal/openai"
 	services "github.com/Maxito7/hotel_backend/internal/service"
 	"github.com/Maxito7/hotel_backend/internal/tavily"
@@ -37,18 +42,33 @@ func main() {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000",
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Admin-Key,Idempotency-Key",
 		AllowCredentials: true,
-		ExposeHeaders:    "Content-Length",
+		ExposeHeaders:    "Content-Length,Idempotent-Replayed",
 		MaxAge:           86400,
 	}))
 
-	// Habitaciones
+	// Tarifas
 	habitacionRepo := repository.NewHabitacionRepository(db)
-	habitacionService := application.NewHabitacionService(habitacionRepo)
+	tarifaRepo := repository.NewTarifaRepository(db)
+	tarifaService := application.NewTarifaService(tarifaRepo, habitacionRepo)
+	tarifaHandler := handlers.NewTarifaHandler(tarifaService)
+
+	// Restricciones de estadía
+	restriccionRepo := repository.NewRestriccionEstadiaRepository(db)
+	restriccionService := application.NewRestriccionEstadiaService(restriccionRepo, habitacionRepo)
+	restriccionHandler := handlers.NewRestriccionEstadiaHandler(restriccionService)
+
+	// Habitaciones
+	habitacionService := application.NewHabitacionService(habitacionRepo, tarifaService, restriccionService)
 	habitacionHandler := handlers.NewHabitacionHandler(habitacionService)
 
+	// Bloqueos de habitaciones
+	bloqueoRepo := repository.NewBloqueoHabitacionRepository(db)
+	bloqueoService := application.NewBloqueoHabitacionService(bloqueoRepo, habitacionRepo)
+	bloqueoHandler := handlers.NewBloqueoHabitacionHandler(bloqueoService)
+
 	// Search
 	tavilyClient := tavily.NewClient(cfg.TavilyAPIKey)
 	searchService := application.NewSearchService(tavilyClient)
@@ -59,10 +79,15 @@ func main() {
 	servicioService := application.NewServicioService(servicioRepo)
 	servicioHandler := handlers.NewServicioHandler(servicioService)
 
+	// Clientes
+	clienteRepo := repository.NewClienteRepository(db)
+	clienteService := application.NewClienteService(clienteRepo)
+	clienteHandler := handlers.NewClienteHandler(clienteService)
+
 	// Chatbot - NUEVO
 	openaiClient := openai.NewClient(cfg.OpenAIAPIKey)
 	chatbotRepo := repository.NewChatbotRepository(db)
-	chatbotService := application.NewChatbotService(chatbotRepo, openaiClient, habitacionRepo, tavilyClient, cfg.HotelLocation, searchService)
+	chatbotService := application.NewChatbotService(chatbotRepo, clienteRepo, openaiClient, habitacionRepo, tarifaService, tavilyClient, cfg.HotelLocation, searchService)
 	chatbotHandler := handlers.NewChatbotHandler(chatbotService)
 
 	// Email Client
@@ -84,12 +109,63 @@ func main() {
 	contactService := application.NewContactService(contactRepo, emailClient)
 	contactHandler := handlers.NewContactHandler(contactService)
 
+	// Sesiones de clientes y del personal: sin JWT_SECRET no se emiten
+	var firmadorSesion *auth.Firmador
+	if cfg.JWTSecret != "" {
+		firmadorSesion = auth.NewFirmador(cfg.JWTSecret, time.Duration(cfg.SessionHours)*time.Hour)
+	}
+	accesoClienteRepo := repository.NewAccesoClienteRepository(db)
+	accesoClienteService := application.NewAccesoClienteService(accesoClienteRepo, clienteRepo, emailClient, firmadorSesion, time.Duration(cfg.LoginCodeMinutes)*time.Minute)
+	accesoClienteHandler := handlers.NewAccesoClienteHandler(accesoClienteService)
+
+	// Usuarios del personal
+	usuarioRepo := repository.NewUsuarioRepository(db)
+	usuarioService := application.NewUsuarioService(usuarioRepo, firmadorSesion)
+	usuarioHandler := handlers.NewUsuarioHandler(usuarioService)
+	autorizador := handlers.NewAutorizador(firmadorSesion, cfg.AdminAPIKey, usuarioService)
+
+	// Promociones
+	promocionRepo := repository.NewPromocionRepository(db)
+	promocionService := application.NewPromocionService(promocionRepo)
+	promocionHandler := handlers.NewPromocionHandler(promocionService)
+
+	// Políticas de cancelación
+	politicaRepo := repository.NewPoliticaCancelacionRepository(db)
+	politicaService := application.NewPoliticaCancelacionService(politicaRepo)
+	politicaHandler := handlers.NewPoliticaCancelacionHandler(politicaService)
+
 	// Reservas
 	reservaRepo := repository.NewReservaRepository(db)
 	reservaHabitacionRepo := repository.NewReservaHabitacionRepository(db)
-	reservaService := application.NewReservaService(reservaRepo, reservaHabitacionRepo, habitacionRepo, emailClient)
+	calculadoraImpuestos := application.NewCalculadoraImpuestos(cfg.ServiceChargePercent)
+	reservaService := application.NewReservaService(reservaRepo, reservaHabitacionRepo, habitacionRepo, tarifaService, restriccionService, promocionService, politicaService, calculadoraImpuestos, time.Duration(cfg.HoldMinutes)*time.Minute, emailClient)
 	reservaHandler := handlers.NewReservaHandler(reservaService)
 
+	// Idempotencia de las solicitudes que el frontend reintenta
+	idempotenciaRepo := repository.NewIdempotenciaRepository(db)
+	idempotenciaService := application.NewIdempotenciaService(idempotenciaRepo, time.Duration(cfg.IdempotencyTTLHours)*time.Hour)
+	idempotencia := handlers.Idempotencia(idempotenciaService)
+
+	// Grupos
+	grupoRepo := repository.NewGrupoRepository(db)
+	grupoService := application.NewGrupoService(grupoRepo, reservaService)
+	grupoHandler := handlers.NewGrupoHandler(grupoService)
+
+	// Lista de espera: se procesa cada vez que se liberan habitaciones
+	listaEsperaRepo := repository.NewListaEsperaRepository(db)
+	listaEsperaService := application.NewListaEsperaService(listaEsperaRepo, reservaService, habitacionRepo, emailClient, time.Duration(cfg.WaitlistOfferMinutes)*time.Minute, cfg.FrontendURL)
+	listaEsperaHandler := handlers.NewListaEsperaHandler(listaEsperaService)
+	reservaService.AlLiberarInventario(func() {
+		ofertas, err := listaEsperaService.ProcesarListaEspera()
+		if err != nil {
+			log.Printf("Error al procesar la lista de espera: %v", err)
+			return
+		}
+		if ofertas > 0 {
+			log.Printf("Ofertas enviadas a la lista de espera: %d", ofertas)
+		}
+	})
+
 	// S3
 	S3Service, err := services.NewS3Service()
 	S3Handler := handlers.NewS3Handler(S3Service)
@@ -101,15 +177,59 @@ func main() {
 	habitaciones.Get("/", habitacionHandler.GetAllRooms)
 	habitaciones.Get("/tipos", habitacionHandler.GetRoomTypes)
 	habitaciones.Get("/disponibles", habitacionHandler.GetAvailableRooms)
+	habitaciones.Get("/buscar", habitacionHandler.BuscarHabitaciones)
 	habitaciones.Get("/fechas-bloqueadas", habitacionHandler.GetFechasBloqueadas)
+	habitaciones.Get("/calendario", habitacionHandler.GetCalendario)
 	habitaciones.Get("/tipos", habitacionHandler.GetRoomTypes)
 
+	// Permisos del personal por ruta; admin tiene acceso a todas
+	recepcion := autorizador.RequireRol(domain.RolRecepcion)
+	revenue := autorizador.RequireRol(domain.RolRevenue)
+	marketing := autorizador.RequireRol(domain.RolMarketing)
+	inventario := autorizador.RequireRol(domain.RolRecepcion, domain.RolRevenue)
+	atencion := autorizador.RequireRol(domain.RolRecepcion, domain.RolMarketing)
+	comercial := autorizador.RequireRol(domain.RolRevenue, domain.RolMarketing)
+	sesionOpcional := autorizador.SesionOpcional()
+
+	// Rutas solo para administradores: habitaciones, tipos de habitación y usuarios del personal
+	admin := api.Group("/admin", autorizador.RequireRol())
+	admin.Post("/habitaciones", habitacionHandler.CreateRoom)
+	admin.Put("/habitaciones/:id", habitacionHandler.UpdateRoom)
+	admin.Delete("/habitaciones/:id", habitacionHandler.DeactivateRoom)
+	admin.Post("/tipos-habitacion", habitacionHandler.CreateRoomType)
+	admin.Put("/tipos-habitacion/:id", habitacionHandler.UpdateRoomType)
+	admin.Delete("/tipos-habitacion/:id", habitacionHandler.DeactivateRoomType)
+	admin.Get("/usuarios", usuarioHandler.GetUsuarios)
+	admin.Post("/usuarios", usuarioHandler.CreateUsuario)
+	admin.Put("/usuarios/:id", usuarioHandler.UpdateUsuario)
+	admin.Put("/usuarios/:id/password", usuarioHandler.CambiarPassword)
+
+	// Operación del hotel
+	habitaciones.Patch("/:id/limpieza", recepcion, habitacionHandler.UpdateEstadoLimpieza)
+
+	bloqueos := api.Group("/bloqueos", inventario)
+	bloqueos.Get("/", bloqueoHandler.GetBloqueos)
+	bloqueos.Post("/", bloqueoHandler.CreateBloqueo)
+	bloqueos.Put("/:id", bloqueoHandler.UpdateBloqueo)
+	bloqueos.Delete("/:id", bloqueoHandler.DeleteBloqueo)
+
+	restricciones := api.Group("/restricciones", revenue)
+	restricciones.Get("/", restriccionHandler.GetRestricciones)
+	restricciones.Post("/", restriccionHandler.GuardarRestricciones)
+	restricciones.Delete("/:id", restriccionHandler.DeleteRestriccion)
+
+	grupos := api.Group("/grupos", recepcion)
+	grupos.Get("/", grupoHandler.GetGrupos)
+	grupos.Post("/", grupoHandler.CreateGrupo)
+	grupos.Get("/:id", grupoHandler.GetGrupoByID)
+	grupos.Post("/:id/rooming-list", grupoHandler.ImportarRoomingList)
+
 	api.Post("/search", searchHandler.Search)
 
 	contacto := api.Group("/contact")
 	contacto.Post("/", contactHandler.Create)
-	contacto.Get("/", contactHandler.List)
-	contacto.Patch("/:id/estado", contactHandler.UpdateEstado)
+	contacto.Get("/", atencion, contactHandler.List)
+	contacto.Patch("/:id/estado", atencion, contactHandler.UpdateEstado)
 
 	// Rutas de servicios
 	servicios := api.Group("/servicios")
@@ -117,25 +237,120 @@ func main() {
 
 	// Rutas del chatbot - NUEVO
 	chatbot := api.Group("/chatbot")
-	chatbot.Post("/chat", chatbotHandler.Chat)
-	chatbot.Get("/conversation/:id", chatbotHandler.GetConversation)
-	chatbot.Get("/client/:clienteId/conversations", chatbotHandler.GetClientConversations)
+	chatbot.Post("/chat", sesionOpcional, chatbotHandler.Chat)
+	chatbot.Get("/conversation/:id", sesionOpcional, chatbotHandler.GetConversation)
+	chatbot.Get("/client/:clienteId/conversations", autorizador.RequireSesionCliente("clienteId", domain.RolRecepcion), chatbotHandler.GetClientConversations)
 
 	// Rutas de reservas
 	reservas := api.Group("/reservas")
-	reservas.Post("/", reservaHandler.CreateReserva)
-	reservas.Get("/:id", reservaHandler.GetReservaByID)
-	reservas.Get("/cliente/:clienteId", reservaHandler.GetReservasCliente)
-	reservas.Patch("/:id/estado", reservaHandler.UpdateReservaEstado)
-	reservas.Post("/:id/cancelar", reservaHandler.CancelarReserva)
-	reservas.Post("/:id/confirmar", reservaHandler.ConfirmarReserva)
-	reservas.Post("/:id/confirmar-pago", reservaHandler.ConfirmarPago) // NUEVO: Confirma pago y envía email
+	// Las rutas estáticas van antes de /:id, que de otro modo las captura
+	reservas.Post("/", sesionOpcional, idempotencia, reservaHandler.CreateReserva)
 	reservas.Post("/verificar-disponibilidad", reservaHandler.VerificarDisponibilidad)
-	reservas.Get("/rango", reservaHandler.GetReservasEnRango)
+	reservas.Get("/rango", inventario, reservaHandler.GetReservasEnRango)
+	reservas.Get("/cliente/:clienteId", autorizador.RequireSesionCliente("clienteId", domain.RolRecepcion), reservaHandler.GetReservasCliente)
+	reservas.Get("/codigo/:codigo", reservaHandler.GetReservaPorCodigo) // ?email= como segundo factor
+
+	// Cancelar o modificar: el personal de recepción o el cliente dueño de la reserva
+	propietarioReserva := autorizador.RequirePropietario(reservaHandler.EsPropietario, domain.RolRecepcion)
+	reservas.Get("/:id", propietarioReserva, reservaHandler.GetReservaByID)
+	reservas.Patch("/:id", propietarioReserva, reservaHandler.ModificarReserva)
+	reservas.Post("/:id/cancelar", propietarioReserva, reservaHandler.CancelarReserva)
+	reservas.Get("/:id/cancelacion", propietarioReserva, reservaHandler.PrevisualizarCancelacion)
+	reservas.Post("/:id/habitaciones/:habitacionId/cancelar", propietarioReserva, reservaHandler.CancelarHabitacion)
+
+	// Operación de recepción
+	reservas.Patch("/:id/estado", recepcion, reservaHandler.UpdateReservaEstado)
+	reservas.Get("/:id/historial", recepcion, reservaHandler.GetHistorialEstados)
+	reservas.Post("/:id/confirmar", recepcion, reservaHandler.ConfirmarReserva)
+	reservas.Post("/:id/confirmar-pago", recepcion, idempotencia, reservaHandler.ConfirmarPago) // NUEVO: Confirma pago y envía email
+	reservas.Post("/:id/checkin", recepcion, reservaHandler.CheckIn)
+	reservas.Post("/:id/checkout", recepcion, reservaHandler.CheckOut)
+	reservas.Post("/:id/asignar-habitaciones", recepcion, reservaHandler.AsignarHabitaciones)
+	reservas.Post("/:id/habitaciones/:habitacionId/reasignar", recepcion, reservaHandler.ReasignarHabitacion)
+
+	// Rutas de lista de espera
+	listaEspera := api.Group("/lista-espera")
+	listaEspera.Post("/", listaEsperaHandler.CreateSolicitud)
+	listaEspera.Get("/ofertas/:token", listaEsperaHandler.GetOferta)
+	listaEspera.Post("/ofertas/:token/confirmar", listaEsperaHandler.ConfirmarOferta)
+	listaEspera.Get("/", recepcion, listaEsperaHandler.GetSolicitudes)
+	listaEspera.Delete("/:id", recepcion, listaEsperaHandler.CancelarSolicitud)
+
+	// Rutas de inicio de sesión
+	api.Post("/auth/login", usuarioHandler.Login)
+	accesoCliente := api.Group("/auth/cliente")
+	accesoCliente.Post("/codigo", accesoClienteHandler.SolicitarCodigo)
+	accesoCliente.Post("/verificar", accesoClienteHandler.VerificarCodigo)
+
+	// Rutas de clientes (datos personales, solo para el personal del hotel)
+	clientes := api.Group("/clientes", recepcion)
+	clientes.Get("/", clienteHandler.GetClientes)
+	clientes.Post("/", clienteHandler.CreateCliente)
+	clientes.Get("/:id", clienteHandler.GetCliente)
+	clientes.Put("/:id", clienteHandler.UpdateCliente)
+
+	// Rutas de tarifas
+	tarifas := api.Group("/tarifas")
+	tarifas.Get("/cotizacion", tarifaHandler.Cotizar)
+	tarifas.Get("/planes", tarifaHandler.GetPlanes)
+	tarifas.Post("/planes", revenue, tarifaHandler.CreatePlan)
+	tarifas.Put("/planes/:id", revenue, tarifaHandler.UpdatePlan)
+
+	// Rutas de promociones
+	// Los códigos se canjean al crear la reserva; el listado es solo para el área comercial
+	promociones := api.Group("/promociones", comercial)
+	promociones.Get("/", promocionHandler.GetPromociones)
+	promociones.Get("/:id", promocionHandler.GetPromocionByID)
+	promociones.Post("/", promocionHandler.CreatePromocion)
+	promociones.Put("/:id", promocionHandler.UpdatePromocion)
+	promociones.Delete("/:id", promocionHandler.DesactivarPromocion)
+
+	// Rutas de políticas de cancelación
+	politicas := api.Group("/politicas-cancelacion")
+	politicas.Get("/", politicaHandler.GetPoliticas)
+	politicas.Post("/", revenue, politicaHandler.CreatePolitica)
+	politicas.Put("/:id", revenue, politicaHandler.UpdatePolitica)
 
 	// Rutas de S3
 	s3 := api.Group("/upload")
-	s3.Post("/imagenes", S3Handler.HandleUploadFile)
+	s3.Post("/imagenes", marketing, S3Handler.HandleUploadFile)
+
+	// Jobs en segundo plano
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go jobs.RunEvery(ctx, "expirar-reservas", time.Minute, func() error {
+		expiradas, err := reservaService.ExpirarReservasPendientes()
+		if expiradas > 0 {
+			log.Printf("Reservas pendientes expiradas: %d", expiradas)
+		}
+		return err
+	})
+	go jobs.RunEvery(ctx, "limpiar-idempotencia", time.Hour, func() error {
+		eliminadas, err := idempotenciaService.EliminarVencidas()
+		if eliminadas > 0 {
+			log.Printf("Claves de idempotencia vencidas eliminadas: %d", eliminadas)
+		}
+		return err
+	})
+	go jobs.RunEvery(ctx, "liberar-grupos", time.Hour, func() error {
+		liberados, err := grupoService.LiberarCuposVencidos()
+		if liberados > 0 {
+			log.Printf("Cupos de grupo liberados: %d", liberados)
+		}
+		return err
+	})
+	go jobs.RunEvery(ctx, "no-shows", 15*time.Minute, func() error {
+		resumen, err := reservaService.ProcesarNoShows(cfg.NoShowCutoffHour)
+		if err != nil {
+			return err
+		}
+		if len(resumen.Reservas) > 0 {
+			log.Printf("Reservas no presentadas: %d %v, penalidades S/ %.2f, emails fallidos: %d",
+				len(resumen.Reservas), resumen.Reservas, resumen.TotalPenalidad, resumen.EmailsFallidos)
+		}
+		return nil
+	})
 
 	log.Printf("Server starting on port %s", cfg.ServerPort)
 	if err := app.Listen(":" + cfg.ServerPort); err != nil {
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/auth"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/email"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+const (
+	// maxIntentosCodigo es la cantidad de verificaciones tras la cual un código se descarta
+	maxIntentosCodigo = 5
+	// ventanaSolicitudesCodigo es el periodo en que se cuentan los códigos solicitados
+	ventanaSolicitudesCodigo = 15 * time.Minute
+	// maxCodigosPorEmail y maxCodigosPorIP limitan los códigos emitidos en la ventana
+	maxCodigosPorEmail = 3
+	maxCodigosPorIP    = 10
+)
+
+// ErrAccesoDeshabilitado indica que no hay clave configurada para firmar las sesiones
+var ErrAccesoDeshabilitado = errors.New("el inicio de sesión no está habilitado")
+
+// SesionCliente es la sesión emitida al cliente que verificó su código de acceso
+type SesionCliente struct {
+	Token    string          `json:"token"`
+	ExpiraEn time.Time       `json:"expiraEn"`
+	Cliente  *domain.Cliente `json:"cliente"`
+}
+
+// AccesoClienteService inicia la sesión de los clientes sin contraseña: envía un código de un
+// solo uso a su email y, al verificarlo, emite un token de sesión
+type AccesoClienteService struct {
+	repo           domain.AccesoClienteRepository
+	clienteRepo    domain.ClienteRepository
+	emailClient    *email.Client
+	firmador       *auth.Firmador
+	duracionCodigo time.Duration
+}
+
+// NewAccesoClienteService crea una nueva instancia del servicio de acceso de clientes
+func NewAccesoClienteService(
+	repo domain.AccesoClienteRepository,
+	clienteRepo domain.ClienteRepository,
+	emailClient *email.Client,
+	firmador *auth.Firmador,
+	duracionCodigo time.Duration,
+) *AccesoClienteService {
+	return &AccesoClienteService{
+		repo:           repo,
+		clienteRepo:    clienteRepo,
+		emailClient:    emailClient,
+		firmador:       firmador,
+		duracionCodigo: duracionCodigo,
+	}
+}
+
+// SolicitarCodigo envía un código de acceso al email. Cada código nuevo invalida los anteriores
+// y se limita la cantidad de códigos por email y por IP. El perfil del cliente se crea recién
+// al verificar el código. Retorna domain.ErrDemasiadasSolicitudesCodigo si se superó el límite.
+func (s *AccesoClienteService) SolicitarCodigo(emailCliente, ip string) error {
+	emailCliente = normalizarEmailAcceso(emailCliente)
+	if !strings.Contains(emailCliente, "@") {
+		return fmt.Errorf("el email es requerido")
+	}
+
+	if s.firmador == nil {
+		return ErrAccesoDeshabilitado
+	}
+	if s.emailClient == nil {
+		return fmt.Errorf("el envío de correos no está disponible")
+	}
+
+	codigo, err := generarCodigoAcceso()
+	if err != nil {
+		return err
+	}
+
+	ahora := time.Now()
+	acceso := &domain.CodigoAcceso{
+		Email:       emailCliente,
+		HashCodigo:  hashCodigoAcceso(codigo),
+		ExpiraEn:    ahora.Add(s.duracionCodigo),
+		IPSolicitud: ip,
+	}
+	limite := domain.LimiteSolicitudesCodigo{
+		Desde:    ahora.Add(-ventanaSolicitudesCodigo),
+		PorEmail: maxCodigosPorEmail,
+		PorIP:    maxCodigosPorIP,
+	}
+	if err := s.repo.CrearCodigo(acceso, limite); err != nil {
+		return err
+	}
+
+	return s.emailClient.SendCodigoAcceso(emailCliente, codigo, acceso.ExpiraEn.In(horario.Peru))
+}
+
+// VerificarCodigo consume el código de acceso del email y emite el token de sesión del cliente,
+// creando su perfil si es su primer ingreso. Retorna domain.ErrCodigoAccesoInvalido si el código
+// no es el vigente o agotó sus intentos.
+func (s *AccesoClienteService) VerificarCodigo(emailCliente, codigo string) (*SesionCliente, error) {
+	if s.firmador == nil {
+		return nil, ErrAccesoDeshabilitado
+	}
+
+	ahora := time.Now()
+	acceso, err := s.repo.GetCodigoVigente(normalizarEmailAcceso(emailCliente), ahora)
+	if err != nil {
+		return nil, err
+	}
+
+	// El intento se cuenta antes de comparar, así las verificaciones en paralelo no superan el límite
+	if err := s.repo.RegistrarIntento(acceso.ID, maxIntentosCodigo); err != nil {
+		return nil, err
+	}
+
+	hash := hashCodigoAcceso(strings.TrimSpace(codigo))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(acceso.HashCodigo)) != 1 {
+		return nil, domain.ErrCodigoAccesoInvalido
+	}
+
+	if err := s.repo.MarcarUsado(acceso.ID, ahora); err != nil {
+		return nil, err
+	}
+
+	clienteID, err := s.clienteRepo.VincularCliente(domain.Cliente{Email: acceso.Email})
+	if err != nil {
+		return nil, err
+	}
+	cliente, err := s.clienteRepo.GetClienteByID(clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiraEn, err := s.firmador.Firmar(auth.Claims{
+		Sujeto: strconv.Itoa(cliente.ID),
+		Email:  cliente.Email,
+		Rol:    domain.RolCliente,
+	}, ahora)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SesionCliente{
+		Token:    token,
+		ExpiraEn: expiraEn,
+		Cliente:  cliente,
+	}, nil
+}
+
+// normalizarEmailAcceso normaliza el email con que se piden y verifican los códigos
+func normalizarEmailAcceso(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// generarCodigoAcceso genera un código aleatorio de 6 dígitos
+func generarCodigoAcceso() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("error al generar código de acceso: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashCodigoAcceso retorna el SHA-256 del código en hexadecimal
+func hashCodigoAcceso(codigo string) string {
+	suma := sha256.Sum256([]byte(codigo))
+	return hex.EncodeToString(suma[:])
+}
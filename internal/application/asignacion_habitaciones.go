@@ -0,0 +1,189 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// horizonteHuecos es el hueco en días que se asume cuando la habitación no tiene ocupación
+// cercana antes o después de la estadía
+const horizonteHuecos = 60
+
+// penalidadHuecoNoche penaliza dejar libre una sola noche entre dos ocupaciones, que casi
+// nunca se llega a vender, por encima de cualquier otro hueco
+const penalidadHuecoNoche = 1000
+
+// AsignarHabitaciones asigna habitaciones concretas a las habitaciones reservadas por tipo de la
+// reserva. Las asignaciones manuales se respetan y el resto se elige automáticamente entre las
+// habitaciones libres del tipo, prefiriendo las que dejan menos huecos en el calendario.
+func (s *ReservaService) AsignarHabitaciones(reservaID int, manuales []domain.AsignacionHabitacion) (*domain.Reserva, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(reservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reserva.Estado != domain.ReservaPendiente && reserva.Estado != domain.ReservaConfirmada {
+		return nil, fmt.Errorf("no se pueden asignar habitaciones a una reserva en estado %s", reserva.Estado)
+	}
+
+	if reserva.Expirada(time.Now()) {
+		return nil, domain.ErrReservaExpirada
+	}
+
+	if len(reserva.TiposHabitacion) == 0 {
+		return nil, fmt.Errorf("la reserva %d no tiene habitaciones por asignar", reservaID)
+	}
+
+	asignaciones, err := s.planificarAsignacion(reserva, manuales)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reservaRepo.AsignarHabitaciones(reservaID, asignaciones); err != nil {
+		return nil, fmt.Errorf("error al asignar habitaciones: %w", err)
+	}
+
+	return s.reservaRepo.GetReservaByID(reservaID)
+}
+
+// planificarAsignacion completa las asignaciones manuales con una habitación por cada unidad
+// reservada por tipo que falte asignar
+func (s *ReservaService) planificarAsignacion(reserva *domain.Reserva, manuales []domain.AsignacionHabitacion) ([]domain.AsignacionHabitacion, error) {
+	tipos := make(map[int]domain.ReservaTipoHabitacion, len(reserva.TiposHabitacion))
+	for _, tipo := range reserva.TiposHabitacion {
+		tipos[tipo.ID] = tipo
+	}
+
+	asignadas := make(map[int]int)
+	usadas := make(map[int]bool)
+	for _, manual := range manuales {
+		tipo, ok := tipos[manual.TipoReservaID]
+		if !ok {
+			return nil, fmt.Errorf("la línea %d no tiene habitaciones por asignar en la reserva %d", manual.TipoReservaID, reserva.ID)
+		}
+		if usadas[manual.HabitacionID] {
+			return nil, fmt.Errorf("la habitación %d está repetida en las asignaciones", manual.HabitacionID)
+		}
+		if asignadas[tipo.ID] == tipo.Cantidad {
+			return nil, fmt.Errorf("la línea %d solo reserva %d habitación(es)", tipo.ID, tipo.Cantidad)
+		}
+		asignadas[tipo.ID]++
+		usadas[manual.HabitacionID] = true
+	}
+
+	asignaciones := append([]domain.AsignacionHabitacion{}, manuales...)
+	for _, tipo := range reserva.TiposHabitacion {
+		faltan := tipo.Cantidad - asignadas[tipo.ID]
+		if faltan == 0 {
+			continue
+		}
+
+		candidatas, err := s.reservaHabitacionRepo.GetHabitacionesCandidatas(tipo.TipoHabitacionID, tipo.FechaEntrada, tipo.FechaSalida)
+		if err != nil {
+			return nil, err
+		}
+
+		elegidas := elegirHabitaciones(candidatas, tipo.FechaEntrada, tipo.FechaSalida, faltan, usadas)
+		if len(elegidas) < faltan {
+			return nil, domain.ErrSinHabitacionAsignable
+		}
+
+		for _, habitacionID := range elegidas {
+			usadas[habitacionID] = true
+			asignaciones = append(asignaciones, domain.AsignacionHabitacion{
+				TipoReservaID: tipo.ID,
+				HabitacionID:  habitacionID,
+			})
+		}
+	}
+
+	return asignaciones, nil
+}
+
+// elegirHabitaciones elige hasta cantidad habitaciones candidatas no usadas, las que dejan menos
+// huecos antes y después de la estadía primero y a igual puntaje la de menor ID
+func elegirHabitaciones(candidatas []domain.HabitacionCandidata, fechaEntrada, fechaSalida time.Time, cantidad int, usadas map[int]bool) []int {
+	type puntuada struct {
+		habitacionID int
+		puntaje      int
+	}
+
+	var opciones []puntuada
+	for _, candidata := range candidatas {
+		if usadas[candidata.HabitacionID] {
+			continue
+		}
+
+		puntaje := horizonteHuecos
+		if candidata.FinAnterior != nil {
+			puntaje = puntajeHueco(*candidata.FinAnterior, fechaEntrada)
+		}
+		if candidata.InicioSiguiente != nil {
+			puntaje += puntajeHueco(fechaSalida, *candidata.InicioSiguiente)
+		} else {
+			puntaje += horizonteHuecos
+		}
+
+		opciones = append(opciones, puntuada{habitacionID: candidata.HabitacionID, puntaje: puntaje})
+	}
+
+	sort.Slice(opciones, func(i, j int) bool {
+		if opciones[i].puntaje != opciones[j].puntaje {
+			return opciones[i].puntaje < opciones[j].puntaje
+		}
+		return opciones[i].habitacionID < opciones[j].habitacionID
+	})
+
+	elegidas := make([]int, 0, cantidad)
+	for _, opcion := range opciones[:min(cantidad, len(opciones))] {
+		elegidas = append(elegidas, opcion.habitacionID)
+	}
+	return elegidas
+}
+
+// puntajeHueco mide el hueco entre dos ocupaciones: 0 si quedan pegadas, penalidadHuecoNoche si
+// queda una sola noche libre y los días libres en otro caso
+func puntajeHueco(desde, hasta time.Time) int {
+	dias := int(math.Round(hasta.Sub(desde).Hours() / 24))
+	switch {
+	case dias <= 0:
+		return 0
+	case dias == 1:
+		return penalidadHuecoNoche
+	default:
+		return min(dias, horizonteHuecos)
+	}
+}
+
+// ReasignarHabitacion mueve una habitación de la reserva a otra para las mismas fechas, por ejemplo
+// cuando la original sale de servicio. El precio de la estadía se conserva.
+func (s *ReservaService) ReasignarHabitacion(reservaID, habitacionActualID, habitacionNuevaID int) (*domain.Reserva, error) {
+	if habitacionActualID == habitacionNuevaID {
+		return nil, fmt.Errorf("la nueva habitación debe ser distinta de la actual")
+	}
+
+	reserva, err := s.reservaRepo.GetReservaByID(reservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reserva.Estado {
+	case domain.ReservaPendiente, domain.ReservaConfirmada, domain.ReservaCheckIn:
+	default:
+		return nil, fmt.Errorf("no se pueden reasignar habitaciones de una reserva en estado %s", reserva.Estado)
+	}
+
+	if reserva.Expirada(time.Now()) {
+		return nil, domain.ErrReservaExpirada
+	}
+
+	if err := s.reservaRepo.ReasignarHabitacion(reservaID, habitacionActualID, habitacionNuevaID); err != nil {
+		return nil, fmt.Errorf("error al reasignar habitación: %w", err)
+	}
+
+	return s.reservaRepo.GetReservaByID(reservaID)
+}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// BloqueoHabitacionService administra los bloqueos de habitaciones por mantenimiento
+type BloqueoHabitacionService struct {
+	repo           domain.BloqueoHabitacionRepository
+	habitacionRepo domain.HabitacionRepository
+}
+
+// NewBloqueoHabitacionService crea una nueva instancia del servicio de bloqueos de habitaciones
+func NewBloqueoHabitacionService(repo domain.BloqueoHabitacionRepository, habitacionRepo domain.HabitacionRepository) *BloqueoHabitacionService {
+	return &BloqueoHabitacionService{
+		repo:           repo,
+		habitacionRepo: habitacionRepo,
+	}
+}
+
+// GetBloqueos obtiene los bloqueos que se solapan con el rango dado. habitacionID 0 incluye
+// todas las habitaciones.
+func (s *BloqueoHabitacionService) GetBloqueos(habitacionID int, desde, hasta time.Time) ([]domain.BloqueoHabitacion, error) {
+	if hasta.Before(desde) {
+		return nil, fmt.Errorf("la fecha hasta debe ser posterior o igual a la fecha desde")
+	}
+	return s.repo.GetBloqueos(habitacionID, desde, hasta)
+}
+
+// CreateBloqueo valida y crea un bloqueo. Se rechaza si la habitación tiene reservas activas
+// en el rango.
+func (s *BloqueoHabitacionService) CreateBloqueo(bloqueo *domain.BloqueoHabitacion) error {
+	if err := s.validarBloqueo(bloqueo); err != nil {
+		return err
+	}
+	return s.repo.CreateBloqueo(bloqueo)
+}
+
+// UpdateBloqueo valida y actualiza un bloqueo
+func (s *BloqueoHabitacionService) UpdateBloqueo(bloqueo *domain.BloqueoHabitacion) error {
+	if _, err := s.repo.GetBloqueoByID(bloqueo.ID); err != nil {
+		return err
+	}
+
+	if err := s.validarBloqueo(bloqueo); err != nil {
+		return err
+	}
+	return s.repo.UpdateBloqueo(bloqueo)
+}
+
+// DeleteBloqueo elimina un bloqueo y devuelve la habitación al inventario en esas fechas
+func (s *BloqueoHabitacionService) DeleteBloqueo(id int) error {
+	return s.repo.DeleteBloqueo(id)
+}
+
+// validarBloqueo verifica el rango de fechas, el motivo y que la habitación exista
+func (s *BloqueoHabitacionService) validarBloqueo(bloqueo *domain.BloqueoHabitacion) error {
+	bloqueo.Motivo = strings.TrimSpace(bloqueo.Motivo)
+	if bloqueo.Motivo == "" {
+		return fmt.Errorf("el motivo del bloqueo es requerido")
+	}
+
+	if !bloqueo.FechaFin.After(bloqueo.FechaInicio) {
+		return fmt.Errorf("la fecha fin del bloqueo debe ser posterior a la fecha inicio")
+	}
+
+	if _, err := s.habitacionRepo.GetRoomByID(bloqueo.HabitacionID); err != nil {
+		return err
+	}
+
+	return nil
+}
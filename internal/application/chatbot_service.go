@@ -14,8 +14,10 @@ import (
 
 type ChatbotService struct {
 	repo           domain.ChatbotRepository
+	clienteRepo    domain.ClienteRepository
 	openaiClient   *openai.Client
 	habitacionRepo domain.HabitacionRepository
+	tarifaService  *TarifaService
 	tavilyClient   *tavily.Client
 	searchService  *SearchService
 	location       string
@@ -23,16 +25,20 @@ type ChatbotService struct {
 
 func NewChatbotService(
 	repo domain.ChatbotRepository,
+	clienteRepo domain.ClienteRepository,
 	openaiClient *openai.Client,
 	habitacionRepo domain.HabitacionRepository,
+	tarifaService *TarifaService,
 	tavilyClient *tavily.Client,
 	location string,
 	searchService *SearchService,
 ) *ChatbotService {
 	return &ChatbotService{
 		repo:           repo,
+		clienteRepo:    clienteRepo,
 		openaiClient:   openaiClient,
 		habitacionRepo: habitacionRepo,
+		tarifaService:  tarifaService,
 		tavilyClient:   tavilyClient,
 		searchService:  searchService,
 		location:       location,
@@ -44,6 +50,13 @@ func (s *ChatbotService) ProcessMessage(req domain.ChatRequest) (*domain.ChatRes
 	var conversation *domain.ConversationHistory
 	var err error
 
+	// El clienteId es el ID del perfil del cliente
+	if req.ClienteID != nil {
+		if _, err := s.clienteRepo.GetClienteByID(*req.ClienteID); err != nil {
+			return nil, err
+		}
+	}
+
 	if req.ConversationID != nil && *req.ConversationID != "" {
 		conversation, err = s.repo.GetConversation(*req.ConversationID)
 		if err != nil {
@@ -51,6 +64,16 @@ func (s *ChatbotService) ProcessMessage(req domain.ChatRequest) (*domain.ChatRes
 		}
 	}
 
+	if conversation != nil && conversation.ClienteID != nil &&
+		(req.ClienteID == nil || *conversation.ClienteID != *req.ClienteID) {
+		return nil, domain.ErrConversacionAjena
+	}
+
+	if conversation != nil && conversation.ClienteID == nil {
+		// El cliente se identificó en medio de una conversación anónima
+		conversation.ClienteID = req.ClienteID
+	}
+
 	if conversation == nil {
 		conversation = &domain.ConversationHistory{
 			ID:        uuid.New().String(),
@@ -296,7 +319,13 @@ func (s *ChatbotService) getHotelInfo(req domain.ChatRequest) (string, error) {
 
 	for titulo, tipo := range tiposMap {
 		info.WriteString(fmt.Sprintf("\n• %s:\n", titulo))
-		info.WriteString(fmt.Sprintf("  - Precio: S/%.2f por noche\n", tipo.Precio))
+		// El precio varía con la temporada y la ocupación; se informa el de esta noche
+		precio, err := s.tarifaService.PrecioHoy(tipo)
+		if err != nil {
+			log.Printf("Warning: no se pudo calcular la tarifa de %s: %v", titulo, err)
+			precio = tipo.Precio
+		}
+		info.WriteString(fmt.Sprintf("  - Precio: S/%.2f por noche (tarifa de hoy, varía según fecha y ocupación)\n", precio))
 		info.WriteString(fmt.Sprintf("  - Capacidad: %d adultos, %d niños\n",
 			tipo.CapacidadAdultos, tipo.CapacidadNinhos))
 		info.WriteString(fmt.Sprintf("  - Camas: %d\n", tipo.CantidadCamas))
@@ -329,7 +358,7 @@ func (s *ChatbotService) getHotelInfo(req domain.ChatRequest) (string, error) {
 
 	// 4. Políticas
 	info.WriteString("\n=== POLÍTICAS ===\n")
-	info.WriteString("• Cancelación gratuita hasta 48 horas antes\n")
+	info.WriteString("• Cancelación: depende de la tarifa y el tipo de habitación; el plazo de cancelación gratuita se indica al reservar y en el email de confirmación\n")
 	info.WriteString("• Mascotas: No permitidas\n")
 	info.WriteString("• Métodos de pago: Efectivo, tarjeta, transferencia\n")
 
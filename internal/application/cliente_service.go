@@ -0,0 +1,71 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// ClienteService administra los perfiles de los clientes y su historial de estadías
+type ClienteService struct {
+	repo domain.ClienteRepository
+}
+
+// NewClienteService crea una nueva instancia del servicio de clientes
+func NewClienteService(repo domain.ClienteRepository) *ClienteService {
+	return &ClienteService{
+		repo: repo,
+	}
+}
+
+// GetClientes lista los clientes cuyo nombre o email contiene busqueda
+func (s *ClienteService) GetClientes(busqueda string) ([]domain.Cliente, error) {
+	return s.repo.GetClientes(busqueda)
+}
+
+// GetHistorial obtiene el perfil del cliente con sus reservas, estadías, noches y gasto total
+func (s *ClienteService) GetHistorial(id int) (*domain.HistorialCliente, error) {
+	return s.repo.GetHistorial(id)
+}
+
+// CreateCliente registra el perfil de un cliente
+func (s *ClienteService) CreateCliente(cliente *domain.Cliente) error {
+	if err := validarCliente(cliente); err != nil {
+		return err
+	}
+	return s.repo.CreateCliente(cliente)
+}
+
+// UpdateCliente reemplaza los datos del perfil de un cliente
+func (s *ClienteService) UpdateCliente(cliente *domain.Cliente) error {
+	if err := validarCliente(cliente); err != nil {
+		return err
+	}
+	return s.repo.UpdateCliente(cliente)
+}
+
+// validarCliente normaliza y verifica los datos del perfil
+func validarCliente(cliente *domain.Cliente) error {
+	cliente.Email = strings.ToLower(strings.TrimSpace(cliente.Email))
+	if !strings.Contains(cliente.Email, "@") {
+		return fmt.Errorf("el email es requerido")
+	}
+
+	cliente.Nombre = strings.TrimSpace(cliente.Nombre)
+	cliente.Telefono = strings.TrimSpace(cliente.Telefono)
+	cliente.NumeroDocumento = strings.TrimSpace(cliente.NumeroDocumento)
+	if err := validarTipoDocumento(cliente.TipoDocumento, cliente.NumeroDocumento); err != nil {
+		return err
+	}
+	if cliente.TipoDocumento == "" {
+		cliente.NumeroDocumento = ""
+	}
+
+	cliente.Nacionalidad = strings.ToUpper(strings.TrimSpace(cliente.Nacionalidad))
+	if cliente.Nacionalidad != "" && len(cliente.Nacionalidad) != 2 {
+		return fmt.Errorf("la nacionalidad debe ser un código de país de 2 letras")
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	prefijoCodigoReserva  = "HIN-"
+	longitudCodigoReserva = 6
+	// intentosCodigoReserva es la cantidad de códigos que se prueban antes de desistir por colisiones
+	intentosCodigoReserva = 5
+)
+
+// alfabetoCodigoReserva excluye caracteres que se confunden al dictarlos o leerlos (0/O, 1/I/L).
+// La migración 007 usa el mismo alfabeto y longitud para los códigos de las reservas existentes.
+const alfabetoCodigoReserva = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// generarCodigoReserva genera un código de confirmación aleatorio y no secuencial, ej. HIN-7K3Q9P
+func generarCodigoReserva() (string, error) {
+	limite := big.NewInt(int64(len(alfabetoCodigoReserva)))
+
+	var codigo strings.Builder
+	codigo.WriteString(prefijoCodigoReserva)
+	for i := 0; i < longitudCodigoReserva; i++ {
+		n, err := rand.Int(rand.Reader, limite)
+		if err != nil {
+			return "", fmt.Errorf("error al generar código de reserva: %w", err)
+		}
+		codigo.WriteByte(alfabetoCodigoReserva[n.Int64()])
+	}
+
+	return codigo.String(), nil
+}
+
+// normalizarCodigoReserva permite buscar el código sin importar mayúsculas ni espacios
+func normalizarCodigoReserva(codigo string) string {
+	return strings.ToUpper(strings.TrimSpace(codigo))
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// maxHuespedesCombinacion es el tamaño máximo de grupo para el que se buscan combinaciones
+const maxHuespedesCombinacion = 60
+
+// opcionCombinacion es el mejor conjunto de habitaciones conocido para alcanzar una capacidad
+type opcionCombinacion struct {
+	valida       bool
+	costo        float64
+	habitaciones int
+}
+
+// mejorQue prefiere el menor costo y, a igual costo, menos habitaciones
+func (o opcionCombinacion) mejorQue(otra opcionCombinacion) bool {
+	if !otra.valida {
+		return true
+	}
+	if math.Abs(o.costo-otra.costo) > 0.005 {
+		return o.costo < otra.costo
+	}
+	return o.habitaciones < otra.habitaciones
+}
+
+// CombinarHabitaciones sugiere el conjunto de habitaciones disponibles más económico para la
+// estadía que en conjunto aloja a todo el grupo. Los filtros de tipo, precio, camas y amenidades
+// se aplican a cada habitación; la capacidad se evalúa sobre el conjunto. Retorna
+// domain.ErrSinCombinacionHabitaciones si las habitaciones disponibles no alcanzan.
+func (s *HabitacionService) CombinarHabitaciones(filtro domain.FiltroBusquedaHabitaciones) (*domain.CombinacionHabitaciones, error) {
+	if err := validarFiltroBusqueda(&filtro); err != nil {
+		return nil, err
+	}
+	if filtro.Adultos < 1 {
+		return nil, fmt.Errorf("se requiere al menos un adulto")
+	}
+	if filtro.Adultos+filtro.Ninhos > maxHuespedesCombinacion {
+		return nil, fmt.Errorf("el grupo no puede superar %d huéspedes", maxHuespedesCombinacion)
+	}
+
+	adultos, huespedes := filtro.Adultos, filtro.Adultos+filtro.Ninhos
+
+	// Todas las habitaciones disponibles son candidatas, sin importar si alojan solas al grupo
+	candidatas := filtro
+	candidatas.Adultos, candidatas.Ninhos = 0, 0
+	candidatas.PorPagina = 0
+	habitaciones, _, err := s.repo.BuscarHabitaciones(candidatas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Precio de la estadía por tipo de habitación
+	cotizaciones := make(map[int]*domain.Cotizacion)
+	for _, hab := range habitaciones {
+		if _, ok := cotizaciones[hab.TipoHabitacion.ID]; ok {
+			continue
+		}
+		cotizacion, err := s.tarifaService.CotizarTipo(hab.TipoHabitacion, filtro.FechaEntrada, filtro.FechaSalida)
+		if err != nil {
+			return nil, err
+		}
+		cotizaciones[hab.TipoHabitacion.ID] = cotizacion
+	}
+
+	// Mochila 0/1 sobre (plazas de adulto, plazas totales), ambas topadas en lo que necesita el grupo.
+	// previo y elegida permiten reconstruir qué habitaciones forman la mejor opción.
+	indice := func(a, t int) int { return a*(huespedes+1) + t }
+	opciones := make([]opcionCombinacion, (adultos+1)*(huespedes+1))
+	opciones[indice(0, 0)] = opcionCombinacion{valida: true}
+	elegida := make([][]bool, len(habitaciones))
+	previo := make([][]int, len(habitaciones))
+
+	for i, hab := range habitaciones {
+		plazasAdultos := hab.TipoHabitacion.CapacidadAdultos
+		plazasTotales := hab.TipoHabitacion.CapacidadAdultos + hab.TipoHabitacion.CapacidadNinhos
+		precio := cotizaciones[hab.TipoHabitacion.ID].Total
+
+		siguientes := make([]opcionCombinacion, len(opciones))
+		copy(siguientes, opciones)
+		elegida[i] = make([]bool, len(opciones))
+		previo[i] = make([]int, len(opciones))
+
+		for a := 0; a <= adultos; a++ {
+			for t := 0; t <= huespedes; t++ {
+				actual := opciones[indice(a, t)]
+				if !actual.valida {
+					continue
+				}
+
+				destino := indice(min(adultos, a+plazasAdultos), min(huespedes, t+plazasTotales))
+				candidata := opcionCombinacion{
+					valida:       true,
+					costo:        actual.costo + precio,
+					habitaciones: actual.habitaciones + 1,
+				}
+				if candidata.mejorQue(siguientes[destino]) {
+					siguientes[destino] = candidata
+					elegida[i][destino] = true
+					previo[i][destino] = indice(a, t)
+				}
+			}
+		}
+		opciones = siguientes
+	}
+
+	final := indice(adultos, huespedes)
+	if !opciones[final].valida {
+		return nil, domain.ErrSinCombinacionHabitaciones
+	}
+
+	combinacion := &domain.CombinacionHabitaciones{
+		Habitaciones: make([]domain.Habitacion, 0, opciones[final].habitaciones),
+		Noches:       int(filtro.FechaSalida.Sub(filtro.FechaEntrada).Hours() / 24),
+	}
+	for i, estado := len(habitaciones)-1, final; i >= 0; i-- {
+		if !elegida[i][estado] {
+			continue
+		}
+		hab := habitaciones[i]
+		combinacion.Habitaciones = append(combinacion.Habitaciones, hab)
+		combinacion.CapacidadAdultos += hab.TipoHabitacion.CapacidadAdultos
+		combinacion.CapacidadTotal += hab.TipoHabitacion.CapacidadAdultos + hab.TipoHabitacion.CapacidadNinhos
+		combinacion.Total += cotizaciones[hab.TipoHabitacion.ID].Total
+		estado = previo[i][estado]
+	}
+	combinacion.Total = redondear(combinacion.Total)
+
+	return combinacion, nil
+}
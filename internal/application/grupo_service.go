@@ -0,0 +1,196 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+// maxHabitacionesGrupo limita el cupo total que puede retener un grupo
+const maxHabitacionesGrupo = 100
+
+// GrupoService administra las reservas de grupo: su cupo por tipo de habitación, la rooming list
+// y la liberación del cupo no asignado en la fecha de corte
+type GrupoService struct {
+	repo           domain.GrupoRepository
+	reservaService *ReservaService
+}
+
+// NewGrupoService crea una nueva instancia del servicio de grupos
+func NewGrupoService(repo domain.GrupoRepository, reservaService *ReservaService) *GrupoService {
+	return &GrupoService{
+		repo:           repo,
+		reservaService: reservaService,
+	}
+}
+
+// NuevoGrupo son los datos para retener el cupo de un grupo
+type NuevoGrupo struct {
+	Nombre          string
+	EmailContacto   string
+	FechaEntrada    time.Time
+	FechaSalida     time.Time
+	FechaLiberacion time.Time
+	CantidadAdultos int // 0 asume un adulto por habitación
+	CantidadNinhos  int
+	Cupos           []domain.CupoGrupo
+}
+
+// CreateGrupo retiene el cupo del grupo como una reserva confirmada con una reserva por tipo por
+// cada tipo de habitación. El precio se calcula como en cualquier reserva.
+func (s *GrupoService) CreateGrupo(datos NuevoGrupo, actor string) (*domain.Grupo, error) {
+	datos.Nombre = strings.TrimSpace(datos.Nombre)
+	if datos.Nombre == "" {
+		return nil, fmt.Errorf("el nombre del grupo es requerido")
+	}
+
+	datos.EmailContacto = strings.ToLower(strings.TrimSpace(datos.EmailContacto))
+	if !strings.Contains(datos.EmailContacto, "@") {
+		return nil, fmt.Errorf("el email de contacto es requerido")
+	}
+
+	if len(datos.Cupos) == 0 {
+		return nil, fmt.Errorf("el grupo debe retener al menos un tipo de habitación")
+	}
+
+	if !datos.FechaSalida.After(datos.FechaEntrada) {
+		return nil, fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada")
+	}
+	if horario.FechaPeru(datos.FechaLiberacion).Before(horario.HoyPeru()) {
+		return nil, fmt.Errorf("la fecha de liberación no puede ser anterior a hoy")
+	}
+	if !datos.FechaLiberacion.Before(datos.FechaEntrada) {
+		return nil, fmt.Errorf("la fecha de liberación debe ser anterior a la fecha de entrada")
+	}
+
+	tipos := make(map[int]bool)
+	habitaciones := 0
+	lineas := make([]domain.ReservaTipoHabitacion, 0, len(datos.Cupos))
+	for _, cupo := range datos.Cupos {
+		if cupo.Cantidad < 1 {
+			return nil, fmt.Errorf("la cantidad de habitaciones del tipo %d debe ser al menos 1", cupo.TipoHabitacionID)
+		}
+		if tipos[cupo.TipoHabitacionID] {
+			return nil, fmt.Errorf("el tipo de habitación %d está repetido en el cupo", cupo.TipoHabitacionID)
+		}
+		tipos[cupo.TipoHabitacionID] = true
+		habitaciones += cupo.Cantidad
+
+		lineas = append(lineas, domain.ReservaTipoHabitacion{
+			TipoHabitacionID: cupo.TipoHabitacionID,
+			Cantidad:         cupo.Cantidad,
+			FechaEntrada:     datos.FechaEntrada,
+			FechaSalida:      datos.FechaSalida,
+		})
+	}
+	if habitaciones > maxHabitacionesGrupo {
+		return nil, fmt.Errorf("el cupo del grupo no puede superar %d habitaciones", maxHabitacionesGrupo)
+	}
+
+	if datos.CantidadAdultos == 0 {
+		datos.CantidadAdultos = habitaciones
+	}
+	if datos.CantidadAdultos < 1 || datos.CantidadNinhos < 0 {
+		return nil, fmt.Errorf("el grupo debe tener al menos un adulto")
+	}
+
+	reserva := &domain.Reserva{
+		ClienteID:       datos.EmailContacto,
+		CantidadAdultos: datos.CantidadAdultos,
+		CantidadNinhos:  datos.CantidadNinhos,
+		Estado:          domain.ReservaConfirmada,
+		TiposHabitacion: lineas,
+	}
+	if err := s.reservaService.CreateReserva(reserva, actor); err != nil {
+		return nil, err
+	}
+
+	grupo := &domain.Grupo{
+		Nombre:          datos.Nombre,
+		EmailContacto:   datos.EmailContacto,
+		ReservaID:       reserva.ID,
+		FechaLiberacion: datos.FechaLiberacion,
+	}
+	if err := s.repo.CreateGrupo(grupo); err != nil {
+		// Sin grupo el cupo nunca se liberaría; se devuelve de inmediato
+		if _, errLiberar := s.reservaService.LiberarHabitacionesPorAsignar(reserva.ID, domain.ActorSistema); errLiberar != nil {
+			fmt.Printf("Error al liberar el cupo de la reserva %d: %v\n", reserva.ID, errLiberar)
+		}
+		return nil, err
+	}
+
+	grupo.Reserva = reserva
+	return grupo, nil
+}
+
+// GetGrupos lista los grupos por fecha de liberación
+func (s *GrupoService) GetGrupos() ([]domain.Grupo, error) {
+	return s.repo.GetGrupos()
+}
+
+// GetGrupoByID obtiene un grupo con su reserva
+func (s *GrupoService) GetGrupoByID(id int) (*domain.Grupo, error) {
+	grupo, err := s.repo.GetGrupoByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	grupo.Reserva, err = s.reservaService.GetReservaByID(grupo.ReservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return grupo, nil
+}
+
+// ImportarRoomingList asigna habitaciones del cupo a los huéspedes de la rooming list. Puede
+// importarse en partes hasta la fecha de liberación.
+func (s *GrupoService) ImportarRoomingList(id int, habitaciones []domain.HabitacionRooming) (*domain.Grupo, error) {
+	grupo, err := s.repo.GetGrupoByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if grupo.FechaLiberado != nil {
+		return nil, domain.ErrCupoGrupoLiberado
+	}
+
+	grupo.Reserva, err = s.reservaService.AsignarRoomingList(grupo.ReservaID, habitaciones)
+	if err != nil {
+		return nil, err
+	}
+
+	return grupo, nil
+}
+
+// LiberarCuposVencidos devuelve al inventario las habitaciones sin asignar de los grupos cuya
+// fecha de liberación ya llegó. Retorna cuántos grupos liberó.
+func (s *GrupoService) LiberarCuposVencidos() (int, error) {
+	grupos, err := s.repo.GetGruposPorLiberar(horario.HoyPeru())
+	if err != nil {
+		return 0, err
+	}
+
+	liberados := 0
+	for _, grupo := range grupos {
+		if _, err := s.reservaService.LiberarHabitacionesPorAsignar(grupo.ReservaID, domain.ActorSistema); err != nil {
+			fmt.Printf("Error al liberar el cupo del grupo %d: %v\n", grupo.ID, err)
+			continue
+		}
+
+		if err := s.repo.MarcarLiberado(grupo.ID, time.Now()); err != nil {
+			fmt.Printf("Error al marcar el grupo %d como liberado: %v\n", grupo.ID, err)
+			continue
+		}
+		liberados++
+	}
+
+	if liberados > 0 {
+		s.reservaService.inventarioLiberado()
+	}
+
+	return liberados, nil
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// CreateRoom valida y crea una nueva habitación. Si no se indica estado queda disponible.
+func (s *HabitacionService) CreateRoom(habitacion *domain.Habitacion) error {
+	if habitacion.Estado == "" {
+		habitacion.Estado = domain.HabitacionDisponible
+	}
+
+	if err := s.validarHabitacion(habitacion); err != nil {
+		return err
+	}
+	return s.repo.CreateRoom(habitacion)
+}
+
+// UpdateRoom valida y actualiza una habitación
+func (s *HabitacionService) UpdateRoom(habitacion *domain.Habitacion) error {
+	if habitacion.Estado == "" {
+		return fmt.Errorf("el estado de la habitación es requerido")
+	}
+
+	if err := s.validarHabitacion(habitacion); err != nil {
+		return err
+	}
+	return s.repo.UpdateRoom(habitacion)
+}
+
+// DeactivateRoom retira una habitación de servicio si no tiene reservas activas futuras
+func (s *HabitacionService) DeactivateRoom(id int) error {
+	return s.repo.DeactivateRoom(id)
+}
+
+// UpdateEstadoLimpieza cambia el estado de limpieza de una habitación, por ejemplo cuando
+// limpieza termina de preparar una habitación que quedó sucia tras el check-out
+func (s *HabitacionService) UpdateEstadoLimpieza(id int, estado string) error {
+	if !domain.EstadoLimpiezaValido(estado) {
+		return fmt.Errorf("estado de limpieza inválido: %s", estado)
+	}
+	return s.repo.UpdateEstadoLimpieza(id, estado)
+}
+
+// CreateRoomType valida y crea un nuevo tipo de habitación
+func (s *HabitacionService) CreateRoomType(tipo *domain.TipoHabitacion) error {
+	if err := validarTipoHabitacion(tipo); err != nil {
+		return err
+	}
+	return s.repo.CreateRoomType(tipo)
+}
+
+// UpdateRoomType valida y actualiza un tipo de habitación
+func (s *HabitacionService) UpdateRoomType(tipo *domain.TipoHabitacion) error {
+	if err := validarTipoHabitacion(tipo); err != nil {
+		return err
+	}
+	return s.repo.UpdateRoomType(tipo)
+}
+
+// DeactivateRoomType desactiva un tipo de habitación si ya no tiene habitaciones en servicio
+func (s *HabitacionService) DeactivateRoomType(id int) error {
+	return s.repo.DeactivateRoomType(id)
+}
+
+// validarHabitacion verifica los datos de la habitación y que su capacidad sea coherente con su tipo.
+// Completa habitacion.TipoHabitacion con los datos del tipo.
+func (s *HabitacionService) validarHabitacion(habitacion *domain.Habitacion) error {
+	habitacion.Nombre = strings.TrimSpace(habitacion.Nombre)
+	habitacion.Numero = strings.TrimSpace(habitacion.Numero)
+
+	if habitacion.Nombre == "" {
+		return fmt.Errorf("el nombre de la habitación es requerido")
+	}
+	if habitacion.Numero == "" {
+		return fmt.Errorf("el número de la habitación es requerido")
+	}
+
+	if habitacion.Estado != domain.HabitacionDisponible && habitacion.Estado != domain.HabitacionFueraDeServicio {
+		return fmt.Errorf("estado de habitación inválido: %s", habitacion.Estado)
+	}
+
+	tipo, err := s.repo.GetRoomTypeByID(habitacion.TipoHabitacion.ID)
+	if err != nil {
+		return err
+	}
+	habitacion.TipoHabitacion = *tipo
+
+	capacidadTipo := tipo.CapacidadAdultos + tipo.CapacidadNinhos
+	if habitacion.Capacidad < 1 || habitacion.Capacidad > capacidadTipo {
+		return fmt.Errorf("la capacidad de la habitación debe estar entre 1 y %d según su tipo", capacidadTipo)
+	}
+
+	return nil
+}
+
+// validarTipoHabitacion verifica los datos de un tipo de habitación
+func validarTipoHabitacion(tipo *domain.TipoHabitacion) error {
+	tipo.Titulo = strings.TrimSpace(tipo.Titulo)
+
+	if tipo.Titulo == "" {
+		return fmt.Errorf("el título del tipo de habitación es requerido")
+	}
+	if tipo.CapacidadAdultos < 1 {
+		return fmt.Errorf("el tipo de habitación debe admitir al menos un adulto")
+	}
+	if tipo.CapacidadNinhos < 0 {
+		return fmt.Errorf("la capacidad de niños no puede ser negativa")
+	}
+	if tipo.CantidadCamas < 1 {
+		return fmt.Errorf("el tipo de habitación debe tener al menos una cama")
+	}
+	if tipo.Precio <= 0 {
+		return fmt.Errorf("el precio debe ser mayor a 0")
+	}
+	if tipo.PrecioMinimo != nil && *tipo.PrecioMinimo <= 0 {
+		return fmt.Errorf("el precio mínimo debe ser mayor a 0")
+	}
+	if tipo.PrecioMaximo != nil && *tipo.PrecioMaximo <= 0 {
+		return fmt.Errorf("el precio máximo debe ser mayor a 0")
+	}
+	if tipo.PrecioMinimo != nil && tipo.PrecioMaximo != nil && *tipo.PrecioMaximo < *tipo.PrecioMinimo {
+		return fmt.Errorf("el precio máximo no puede ser menor al precio mínimo")
+	}
+
+	amenidades := make([]string, 0, len(tipo.Amenidades))
+	for _, amenidad := range tipo.Amenidades {
+		if amenidad = strings.ToLower(strings.TrimSpace(amenidad)); amenidad != "" {
+			amenidades = append(amenidades, amenidad)
+		}
+	}
+	tipo.Amenidades = amenidades
+
+	return nil
+}
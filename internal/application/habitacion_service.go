@@ -1,18 +1,31 @@
 package application
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/domain"
 )
 
+const (
+	// maxDiasCalendario es la cantidad máxima de días que se pueden consultar en el calendario
+	maxDiasCalendario = 366
+	// porPaginaBusqueda y maxPorPaginaBusqueda limitan el tamaño de página de la búsqueda
+	porPaginaBusqueda    = 20
+	maxPorPaginaBusqueda = 100
+)
+
 type HabitacionService struct {
-	repo domain.HabitacionRepository
+	repo               domain.HabitacionRepository
+	tarifaService      *TarifaService
+	restriccionService *RestriccionEstadiaService
 }
 
-func NewHabitacionService(repo domain.HabitacionRepository) *HabitacionService {
+func NewHabitacionService(repo domain.HabitacionRepository, tarifaService *TarifaService, restriccionService *RestriccionEstadiaService) *HabitacionService {
 	return &HabitacionService{
-		repo: repo,
+		repo:               repo,
+		tarifaService:      tarifaService,
+		restriccionService: restriccionService,
 	}
 }
 
@@ -24,10 +37,206 @@ func (s *HabitacionService) GetAvailableRooms(fechaEntrada, fechaSalida time.Tim
 	return s.repo.GetAvailableRooms(fechaEntrada, fechaSalida)
 }
 
+// GetFechasBloqueadas retorna las fechas sin habitaciones libres y, según las restricciones de
+// estadía, las fechas en que no se puede llegar o salir y la estadía mínima y máxima de cada
+// llegada. Como el selector de fechas no distingue tipos, se toma el tipo más flexible.
 func (s *HabitacionService) GetFechasBloqueadas(desde, hasta time.Time) (*domain.FechasBloqueadas, error) {
-	return s.repo.GetFechasBloqueadas(desde, hasta)
+	fechas, err := s.repo.GetFechasBloqueadas(desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	tipos, err := s.repo.GetRoomTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	restricciones, err := s.restriccionService.GetRestriccionesPorFecha(desde, hasta, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fechas.FechasSinLlegada = make([]time.Time, 0)
+	fechas.FechasSinSalida = make([]time.Time, 0)
+	if len(tipos) == 0 || len(restricciones) == 0 {
+		return fechas, nil
+	}
+
+	fechas.EstanciasMinimas = make(map[string]int)
+	fechas.EstanciasMaximas = make(map[string]int)
+	for fecha := desde; !fecha.After(hasta); fecha = fecha.AddDate(0, 0, 1) {
+		llegada, salida := false, false
+		minima, maxima := -1, 0
+		sinMaxima := false
+		for _, tipo := range tipos {
+			r := restricciones.Para(tipo.ID, fecha)
+			if r == nil {
+				r = &domain.RestriccionEstadia{}
+			}
+			llegada = llegada || !r.CerradoLlegada
+			salida = salida || !r.CerradoSalida
+			if !r.CerradoLlegada && (minima < 0 || r.EstanciaMinima < minima) {
+				minima = r.EstanciaMinima
+			}
+			if !r.CerradoLlegada {
+				sinMaxima = sinMaxima || r.EstanciaMaxima == 0
+				maxima = max(maxima, r.EstanciaMaxima)
+			}
+		}
+
+		clave := fecha.Format("2006-01-02")
+		if !llegada {
+			fechas.FechasSinLlegada = append(fechas.FechasSinLlegada, fecha)
+		}
+		if !salida {
+			fechas.FechasSinSalida = append(fechas.FechasSinSalida, fecha)
+		}
+		if minima > 1 {
+			fechas.EstanciasMinimas[clave] = minima
+		}
+		if llegada && !sinMaxima {
+			fechas.EstanciasMaximas[clave] = maxima
+		}
+	}
+
+	return fechas, nil
+}
+
+// BuscarHabitaciones retorna las habitaciones disponibles que cumplen el filtro junto con la
+// información de paginación
+func (s *HabitacionService) BuscarHabitaciones(filtro domain.FiltroBusquedaHabitaciones) ([]domain.Habitacion, *domain.Paginacion, error) {
+	if err := validarFiltroBusqueda(&filtro); err != nil {
+		return nil, nil, err
+	}
+
+	habitaciones, total, err := s.repo.BuscarHabitaciones(filtro)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paginacion := &domain.Paginacion{
+		Pagina:       filtro.Pagina,
+		PorPagina:    filtro.PorPagina,
+		Total:        total,
+		TotalPaginas: (total + filtro.PorPagina - 1) / filtro.PorPagina,
+	}
+	return habitaciones, paginacion, nil
+}
+
+// validarFiltroBusqueda verifica el filtro y completa la paginación por defecto
+func validarFiltroBusqueda(filtro *domain.FiltroBusquedaHabitaciones) error {
+	if !filtro.FechaSalida.After(filtro.FechaEntrada) {
+		return fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada")
+	}
+	if filtro.Adultos < 0 || filtro.Ninhos < 0 || filtro.MinCamas < 0 {
+		return fmt.Errorf("la cantidad de huéspedes y de camas no puede ser negativa")
+	}
+	if filtro.PrecioMin < 0 || filtro.PrecioMax < 0 {
+		return fmt.Errorf("el rango de precios no puede ser negativo")
+	}
+	if filtro.PrecioMax > 0 && filtro.PrecioMin > filtro.PrecioMax {
+		return fmt.Errorf("el precio mínimo no puede ser mayor al precio máximo")
+	}
+
+	switch filtro.Orden {
+	case "", domain.OrdenPrecioAsc, domain.OrdenPrecioDesc, domain.OrdenCapacidad, domain.OrdenNumero:
+	default:
+		return fmt.Errorf("orden inválido: %s", filtro.Orden)
+	}
+
+	if filtro.Pagina < 1 {
+		filtro.Pagina = 1
+	}
+	if filtro.PorPagina <= 0 {
+		filtro.PorPagina = porPaginaBusqueda
+	}
+	if filtro.PorPagina > maxPorPaginaBusqueda {
+		filtro.PorPagina = maxPorPaginaBusqueda
+	}
+	return nil
 }
 
 func (s *HabitacionService) GetRoomTypes() ([]domain.TipoHabitacion, error) {
 	return s.repo.GetRoomTypes()
 }
+
+// GetCalendario retorna, para cada fecha del rango (ambas inclusive), las habitaciones libres,
+// las reservadas, el precio por noche y las restricciones de estadía de cada tipo de habitación.
+// tipoHabitacionID 0 incluye todos los tipos.
+func (s *HabitacionService) GetCalendario(desde, hasta time.Time, tipoHabitacionID int) ([]domain.DiaCalendario, error) {
+	if hasta.Before(desde) {
+		return nil, fmt.Errorf("la fecha hasta debe ser posterior o igual a la fecha desde")
+	}
+	if hasta.Sub(desde) >= maxDiasCalendario*24*time.Hour {
+		return nil, fmt.Errorf("el rango del calendario no puede superar %d días", maxDiasCalendario)
+	}
+
+	tipos, err := s.repo.GetRoomTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Precio de cada noche por tipo de habitación, indexado por fecha
+	precios := make(map[int]map[string]float64)
+	titulos := make(map[int]string)
+	for _, tipo := range tipos {
+		if tipoHabitacionID != 0 && tipo.ID != tipoHabitacionID {
+			continue
+		}
+
+		noches, err := s.tarifaService.PreciosNoche(tipo, desde, hasta.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+
+		precios[tipo.ID] = make(map[string]float64, len(noches))
+		for _, noche := range noches {
+			precios[tipo.ID][noche.Fecha.Format("2006-01-02")] = noche.Precio
+		}
+		titulos[tipo.ID] = tipo.Titulo
+	}
+
+	if tipoHabitacionID != 0 && len(titulos) == 0 {
+		return nil, fmt.Errorf("tipo de habitación %d no encontrado", tipoHabitacionID)
+	}
+
+	disponibilidades, err := s.repo.GetDisponibilidadFechas(desde, hasta, tipoHabitacionID)
+	if err != nil {
+		return nil, err
+	}
+
+	restricciones, err := s.restriccionService.GetRestriccionesPorFecha(desde, hasta, tipoHabitacionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agrupar por fecha (las filas vienen ordenadas por fecha y tipo)
+	calendario := make([]domain.DiaCalendario, 0)
+	for _, d := range disponibilidades {
+		fecha := d.Fecha.Format("2006-01-02")
+		if len(calendario) == 0 || calendario[len(calendario)-1].Fecha.Format("2006-01-02") != fecha {
+			calendario = append(calendario, domain.DiaCalendario{Fecha: d.Fecha})
+		}
+
+		tipo := domain.DisponibilidadTipo{
+			TipoHabitacionID: d.TipoHabitacionID,
+			Titulo:           titulos[d.TipoHabitacionID],
+			Libres:           d.Habitaciones,
+			Reservadas:       d.Reservadas,
+			Bloqueadas:       d.Bloqueadas,
+			Total:            d.Total,
+			Precio:           precios[d.TipoHabitacionID][fecha],
+		}
+		if r := restricciones.Para(d.TipoHabitacionID, d.Fecha); r != nil {
+			tipo.EstanciaMinima = r.EstanciaMinima
+			tipo.EstanciaMaxima = r.EstanciaMaxima
+			tipo.CerradoLlegada = r.CerradoLlegada
+			tipo.CerradoSalida = r.CerradoSalida
+		}
+
+		dia := &calendario[len(calendario)-1]
+		dia.Tipos = append(dia.Tipos, tipo)
+	}
+
+	return calendario, nil
+}
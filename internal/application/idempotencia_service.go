@@ -0,0 +1,82 @@
+package application
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// plazoProcesoIdempotencia es lo que puede tardar la solicitud original antes de que un reintento
+// con la misma clave la reemplace, si se cayó o venció su timeout sin liberar la clave
+const plazoProcesoIdempotencia = 2 * time.Minute
+
+// IdempotenciaService guarda la respuesta de las solicitudes enviadas con una clave de
+// idempotencia para repetirla cuando el cliente reintenta la misma solicitud
+type IdempotenciaService struct {
+	repo domain.IdempotenciaRepository
+	// vigencia es el tiempo durante el que se guarda cada respuesta
+	vigencia time.Duration
+}
+
+// NewIdempotenciaService crea una nueva instancia del servicio de idempotencia
+func NewIdempotenciaService(repo domain.IdempotenciaRepository, vigencia time.Duration) *IdempotenciaService {
+	return &IdempotenciaService{
+		repo:     repo,
+		vigencia: vigencia,
+	}
+}
+
+// Iniciar registra la solicitud con la clave antes de procesarla. Si la clave ya se usó con el
+// mismo cuerpo retorna la respuesta guardada para repetirla; si retorna nil la solicitud debe
+// procesarse y terminarse con Completar o Cancelar. Retorna domain.ErrIdempotenciaConflicto si
+// la clave se usó con otro cuerpo y domain.ErrIdempotenciaEnProceso si la original no terminó
+// y sigue dentro de su plazo de procesamiento.
+func (s *IdempotenciaService) Iniciar(clave, ruta string, cuerpo []byte) (*domain.RegistroIdempotencia, error) {
+	suma := sha256.Sum256(cuerpo)
+	ahora := time.Now()
+	registro := &domain.RegistroIdempotencia{
+		Clave:         clave,
+		Ruta:          ruta,
+		HashSolicitud: hex.EncodeToString(suma[:]),
+		ExpiraEn:      ahora.Add(s.vigencia),
+		// El plazo nunca supera la vigencia de la clave
+		BloqueadaHasta: ahora.Add(min(plazoProcesoIdempotencia, s.vigencia)),
+	}
+
+	existente, err := s.repo.Reservar(registro, ahora)
+	if err != nil || existente == nil {
+		return nil, err
+	}
+
+	if existente.HashSolicitud != registro.HashSolicitud {
+		return nil, domain.ErrIdempotenciaConflicto
+	}
+	if existente.EnProceso() {
+		return nil, domain.ErrIdempotenciaEnProceso
+	}
+
+	return existente, nil
+}
+
+// Completar guarda la respuesta de la solicitud iniciada con la clave
+func (s *IdempotenciaService) Completar(clave, ruta string, status int, contentType string, cuerpo []byte) error {
+	return s.repo.GuardarRespuesta(&domain.RegistroIdempotencia{
+		Clave:       clave,
+		Ruta:        ruta,
+		Status:      status,
+		ContentType: contentType,
+		Cuerpo:      cuerpo,
+	})
+}
+
+// Cancelar descarta la clave de una solicitud que falló para que un reintento la procese de nuevo
+func (s *IdempotenciaService) Cancelar(clave, ruta string) error {
+	return s.repo.Liberar(clave, ruta)
+}
+
+// EliminarVencidas elimina las respuestas guardadas cuya vigencia terminó
+func (s *IdempotenciaService) EliminarVencidas() (int64, error) {
+	return s.repo.EliminarVencidas(time.Now())
+}
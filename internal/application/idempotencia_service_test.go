@@ -0,0 +1,142 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// idempotenciaRepoFalso guarda las claves en memoria. Como el repositorio real, una clave vigente
+// no se modifica al reservarla otra vez.
+type idempotenciaRepoFalso struct {
+	registros map[string]*domain.RegistroIdempotencia
+}
+
+func nuevoIdempotenciaRepoFalso() *idempotenciaRepoFalso {
+	return &idempotenciaRepoFalso{registros: make(map[string]*domain.RegistroIdempotencia)}
+}
+
+func (r *idempotenciaRepoFalso) Reservar(registro *domain.RegistroIdempotencia, ahora time.Time) (*domain.RegistroIdempotencia, error) {
+	if existente, ok := r.registros[registro.Clave+registro.Ruta]; ok && existente.ExpiraEn.After(ahora) {
+		copia := *existente
+		return &copia, nil
+	}
+	copia := *registro
+	r.registros[registro.Clave+registro.Ruta] = &copia
+	return nil, nil
+}
+
+func (r *idempotenciaRepoFalso) GuardarRespuesta(registro *domain.RegistroIdempotencia) error {
+	existente, ok := r.registros[registro.Clave+registro.Ruta]
+	if !ok {
+		return errors.New("clave no registrada")
+	}
+	existente.Status = registro.Status
+	existente.ContentType = registro.ContentType
+	existente.Cuerpo = registro.Cuerpo
+	return nil
+}
+
+func (r *idempotenciaRepoFalso) Liberar(clave, ruta string) error {
+	delete(r.registros, clave+ruta)
+	return nil
+}
+
+func (r *idempotenciaRepoFalso) EliminarVencidas(time.Time) (int64, error) {
+	return 0, nil
+}
+
+const rutaReservas = "POST /api/reservas"
+
+func TestIdempotenciaRepiteLaRespuesta(t *testing.T) {
+	service := NewIdempotenciaService(nuevoIdempotenciaRepoFalso(), 24*time.Hour)
+	cuerpo := []byte(`{"clienteId":"ana@example.com"}`)
+
+	guardado, err := service.Iniciar("clave-1", rutaReservas, cuerpo)
+	if err != nil || guardado != nil {
+		t.Fatalf("la primera solicitud debería procesarse, se obtuvo %v, %v", guardado, err)
+	}
+
+	if _, err := service.Iniciar("clave-1", rutaReservas, cuerpo); !errors.Is(err, domain.ErrIdempotenciaEnProceso) {
+		t.Fatalf("un reintento en proceso debería retornar ErrIdempotenciaEnProceso, se obtuvo %v", err)
+	}
+
+	if err := service.Completar("clave-1", rutaReservas, 201, "application/json", []byte(`{"data":1}`)); err != nil {
+		t.Fatalf("error al completar: %v", err)
+	}
+
+	guardado, err = service.Iniciar("clave-1", rutaReservas, cuerpo)
+	if err != nil {
+		t.Fatalf("error inesperado en el reintento: %v", err)
+	}
+	if guardado == nil || guardado.Status != 201 || string(guardado.Cuerpo) != `{"data":1}` {
+		t.Fatalf("el reintento debería repetir la respuesta guardada, se obtuvo %+v", guardado)
+	}
+}
+
+func TestIdempotenciaConflicto(t *testing.T) {
+	service := NewIdempotenciaService(nuevoIdempotenciaRepoFalso(), 24*time.Hour)
+
+	if _, err := service.Iniciar("clave-1", rutaReservas, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if _, err := service.Iniciar("clave-1", rutaReservas, []byte(`{"a":2}`)); !errors.Is(err, domain.ErrIdempotenciaConflicto) {
+		t.Fatalf("la misma clave con otro cuerpo debería retornar ErrIdempotenciaConflicto, se obtuvo %v", err)
+	}
+
+	// La misma clave en otra ruta es independiente
+	if guardado, err := service.Iniciar("clave-1", "POST /api/reservas/1/confirmar-pago", []byte(`{"a":2}`)); err != nil || guardado != nil {
+		t.Fatalf("la clave en otra ruta debería procesarse, se obtuvo %v, %v", guardado, err)
+	}
+}
+
+func TestIdempotenciaCancelarPermiteReintentar(t *testing.T) {
+	service := NewIdempotenciaService(nuevoIdempotenciaRepoFalso(), 24*time.Hour)
+	cuerpo := []byte(`{}`)
+
+	if _, err := service.Iniciar("clave-1", rutaReservas, cuerpo); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if err := service.Cancelar("clave-1", rutaReservas); err != nil {
+		t.Fatalf("error al cancelar: %v", err)
+	}
+
+	if guardado, err := service.Iniciar("clave-1", rutaReservas, cuerpo); err != nil || guardado != nil {
+		t.Fatalf("tras cancelar el reintento debería procesarse, se obtuvo %v, %v", guardado, err)
+	}
+}
+
+func TestIdempotenciaPlazoDeProceso(t *testing.T) {
+	tests := []struct {
+		nombre   string
+		vigencia time.Duration
+		plazo    time.Duration
+	}{
+		{nombre: "plazo de proceso", vigencia: 24 * time.Hour, plazo: plazoProcesoIdempotencia},
+		{nombre: "no supera la vigencia", vigencia: time.Minute, plazo: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			repo := nuevoIdempotenciaRepoFalso()
+			service := NewIdempotenciaService(repo, tt.vigencia)
+
+			antes := time.Now()
+			if _, err := service.Iniciar("clave-1", rutaReservas, []byte(`{}`)); err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			registro := repo.registros["clave-1"+rutaReservas]
+			plazo := registro.BloqueadaHasta.Sub(antes)
+			if plazo < tt.plazo || plazo > tt.plazo+time.Second {
+				t.Errorf("plazo de proceso %v, se esperaba %v", plazo, tt.plazo)
+			}
+			if registro.BloqueadaHasta.After(registro.ExpiraEn) {
+				t.Errorf("el plazo de proceso %v supera la vigencia %v", registro.BloqueadaHasta, registro.ExpiraEn)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// tasaIGV es la tasa del Impuesto General a las Ventas
+const tasaIGV = 0.18
+
+// CalculadoraImpuestos calcula el cargo por servicio, el IGV y el total de una reserva
+type CalculadoraImpuestos struct {
+	porcentajeCargoServicio float64
+}
+
+// NewCalculadoraImpuestos crea una calculadora con el porcentaje de cargo por servicio dado (0 lo desactiva)
+func NewCalculadoraImpuestos(porcentajeCargoServicio float64) *CalculadoraImpuestos {
+	return &CalculadoraImpuestos{
+		porcentajeCargoServicio: porcentajeCargoServicio,
+	}
+}
+
+// Aplicar calcula las líneas de cargos e impuestos a partir del subtotal y el descuento de la reserva.
+// El cargo por servicio se calcula sobre el monto con descuento y el IGV sobre ese monto más el cargo.
+func (c *CalculadoraImpuestos) Aplicar(reserva *domain.Reserva) {
+	base := redondear(reserva.Subtotal - reserva.Descuento)
+
+	reserva.CargoServicio = redondear(base * c.porcentajeCargoServicio / 100)
+	reserva.ExoneradoIGV = exoneradoIGV(reserva)
+
+	reserva.IGV = 0
+	if !reserva.ExoneradoIGV {
+		reserva.IGV = redondear((base + reserva.CargoServicio) * tasaIGV)
+	}
+
+	reserva.Total = redondear(base + reserva.CargoServicio + reserva.IGV)
+}
+
+// exoneradoIGV indica si la reserva califica para la exoneración de IGV por servicios de
+// hospedaje a turistas extranjeros no domiciliados que se identifican con pasaporte. Solo un
+// código de país válido distinto de PE exonera.
+func exoneradoIGV(reserva *domain.Reserva) bool {
+	nacionalidad, err := normalizarNacionalidad(reserva.Nacionalidad)
+	return reserva.TipoDocumento == domain.DocumentoPasaporte && err == nil && nacionalidad != "" && nacionalidad != "PE"
+}
+
+// validarDocumento verifica el tipo de documento del huésped si fue proporcionado y normaliza su
+// nacionalidad
+func validarDocumento(reserva *domain.Reserva) error {
+	if err := validarTipoDocumento(reserva.TipoDocumento, reserva.NumeroDocumento); err != nil {
+		return err
+	}
+
+	nacionalidad, err := normalizarNacionalidad(reserva.Nacionalidad)
+	if err != nil {
+		return err
+	}
+	reserva.Nacionalidad = nacionalidad
+	return nil
+}
+
+// normalizarNacionalidad pasa la nacionalidad a mayúsculas y verifica que sea un código de país
+// ISO de 2 letras; una nacionalidad vacía indica que no se proporcionó
+func normalizarNacionalidad(nacionalidad string) (string, error) {
+	nacionalidad = strings.ToUpper(strings.TrimSpace(nacionalidad))
+	if nacionalidad == "" {
+		return "", nil
+	}
+
+	if len(nacionalidad) != 2 || strings.Trim(nacionalidad, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("la nacionalidad debe ser un código de país de 2 letras")
+	}
+	return nacionalidad, nil
+}
+
+// validarTipoDocumento verifica un tipo de documento de identidad y su número; un tipo vacío
+// indica que no se proporcionó documento
+func validarTipoDocumento(tipoDocumento, numeroDocumento string) error {
+	switch tipoDocumento {
+	case "":
+		return nil
+	case domain.DocumentoDNI, domain.DocumentoCE, domain.DocumentoPasaporte:
+		if strings.TrimSpace(numeroDocumento) == "" {
+			return fmt.Errorf("el número de documento es requerido")
+		}
+		return nil
+	default:
+		return fmt.Errorf("tipo de documento inválido: %s", tipoDocumento)
+	}
+}
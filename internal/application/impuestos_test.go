@@ -0,0 +1,87 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+func TestValidarDocumentoNacionalidad(t *testing.T) {
+	tests := []struct {
+		nombre       string
+		nacionalidad string
+		esperada     string
+		invalida     bool
+	}{
+		{nombre: "sin nacionalidad", nacionalidad: "", esperada: ""},
+		{nombre: "código en mayúsculas", nacionalidad: "US", esperada: "US"},
+		{nombre: "código en minúsculas", nacionalidad: " pe ", esperada: "PE"},
+		{nombre: "código de 3 letras", nacionalidad: "PER", invalida: true},
+		{nombre: "nombre del país", nacionalidad: "Peru", invalida: true},
+		{nombre: "código con dígitos", nacionalidad: "P1", invalida: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			reserva := &domain.Reserva{
+				TipoDocumento:   domain.DocumentoPasaporte,
+				NumeroDocumento: "X1234567",
+				Nacionalidad:    tt.nacionalidad,
+			}
+
+			err := validarDocumento(reserva)
+			if tt.invalida {
+				if err == nil {
+					t.Fatalf("se esperaba un error para la nacionalidad %q", tt.nacionalidad)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if reserva.Nacionalidad != tt.esperada {
+				t.Errorf("nacionalidad %q, se esperaba %q", reserva.Nacionalidad, tt.esperada)
+			}
+		})
+	}
+}
+
+func TestAplicarExoneracionIGV(t *testing.T) {
+	tests := []struct {
+		nombre        string
+		tipoDocumento string
+		nacionalidad  string
+		exonerado     bool
+	}{
+		{nombre: "pasaporte extranjero", tipoDocumento: domain.DocumentoPasaporte, nacionalidad: "US", exonerado: true},
+		{nombre: "pasaporte peruano", tipoDocumento: domain.DocumentoPasaporte, nacionalidad: "PE"},
+		{nombre: "pasaporte peruano en minúsculas", tipoDocumento: domain.DocumentoPasaporte, nacionalidad: "pe"},
+		{nombre: "pasaporte con código de 3 letras", tipoDocumento: domain.DocumentoPasaporte, nacionalidad: "PER"},
+		{nombre: "pasaporte sin nacionalidad", tipoDocumento: domain.DocumentoPasaporte},
+		{nombre: "DNI", tipoDocumento: domain.DocumentoDNI, nacionalidad: "US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			reserva := &domain.Reserva{
+				Subtotal:      100,
+				TipoDocumento: tt.tipoDocumento,
+				Nacionalidad:  tt.nacionalidad,
+			}
+			NewCalculadoraImpuestos(10).Aplicar(reserva)
+
+			if reserva.ExoneradoIGV != tt.exonerado {
+				t.Fatalf("exonerado %v, se esperaba %v", reserva.ExoneradoIGV, tt.exonerado)
+			}
+
+			igv, total := 19.8, 129.8
+			if tt.exonerado {
+				igv, total = 0, 110
+			}
+			if reserva.CargoServicio != 10 || reserva.IGV != igv || reserva.Total != total {
+				t.Errorf("cargo %.2f, IGV %.2f, total %.2f, se esperaba 10.00, %.2f, %.2f",
+					reserva.CargoServicio, reserva.IGV, reserva.Total, igv, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/email"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+// ListaEsperaService administra la lista de espera de fechas agotadas y ofrece las habitaciones
+// que se liberan a las solicitudes en orden de registro
+type ListaEsperaService struct {
+	repo           domain.ListaEsperaRepository
+	reservaService *ReservaService
+	habitacionRepo domain.HabitacionRepository
+	emailClient    *email.Client
+	duracionOferta time.Duration
+	// urlSitio es la dirección del sitio del hotel, donde el huésped confirma la oferta
+	urlSitio string
+	// procesando evita que dos liberaciones simultáneas ofrezcan a la vez a la misma solicitud
+	procesando sync.Mutex
+}
+
+// NewListaEsperaService crea una nueva instancia del servicio de lista de espera
+func NewListaEsperaService(
+	repo domain.ListaEsperaRepository,
+	reservaService *ReservaService,
+	habitacionRepo domain.HabitacionRepository,
+	emailClient *email.Client,
+	duracionOferta time.Duration,
+	urlSitio string,
+) *ListaEsperaService {
+	return &ListaEsperaService{
+		repo:           repo,
+		reservaService: reservaService,
+		habitacionRepo: habitacionRepo,
+		emailClient:    emailClient,
+		duracionOferta: duracionOferta,
+		urlSitio:       urlSitio,
+	}
+}
+
+// RegistrarSolicitud agrega una solicitud al final de la lista de espera
+func (s *ListaEsperaService) RegistrarSolicitud(solicitud *domain.SolicitudEspera) error {
+	solicitud.Email = strings.ToLower(strings.TrimSpace(solicitud.Email))
+	if !strings.Contains(solicitud.Email, "@") {
+		return fmt.Errorf("el email es requerido")
+	}
+
+	if solicitud.CantidadAdultos < 1 {
+		return fmt.Errorf("se requiere al menos un adulto")
+	}
+	if solicitud.CantidadNinhos < 0 {
+		return fmt.Errorf("la cantidad de niños no puede ser negativa")
+	}
+
+	if !solicitud.FechaSalida.After(solicitud.FechaEntrada) {
+		return fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada")
+	}
+	if horario.FechaPeru(solicitud.FechaEntrada).Before(horario.HoyPeru()) {
+		return fmt.Errorf("la fecha de entrada no puede ser anterior a hoy")
+	}
+
+	// Los tipos preferidos deben existir; los repetidos se ignoran manteniendo el orden
+	tipos := make([]int, 0, len(solicitud.TiposHabitacion))
+	vistos := make(map[int]bool)
+	for _, tipoID := range solicitud.TiposHabitacion {
+		if vistos[tipoID] {
+			continue
+		}
+		if _, err := s.habitacionRepo.GetRoomTypeByID(tipoID); err != nil {
+			return err
+		}
+		vistos[tipoID] = true
+		tipos = append(tipos, tipoID)
+	}
+	solicitud.TiposHabitacion = tipos
+
+	solicitud.Estado = domain.EsperaEnCola
+	solicitud.ReservaID = nil
+	solicitud.OfertaExpiraEn = nil
+	return s.repo.CreateSolicitud(solicitud)
+}
+
+// GetSolicitudes lista las solicitudes de la lista de espera en orden de registro
+func (s *ListaEsperaService) GetSolicitudes(estado string) ([]domain.SolicitudEspera, error) {
+	switch estado {
+	case "", domain.EsperaEnCola, domain.EsperaOfertada, domain.EsperaAceptada, domain.EsperaVencida, domain.EsperaCancelada:
+		return s.repo.GetSolicitudes(estado)
+	default:
+		return nil, fmt.Errorf("estado de lista de espera inválido: %s", estado)
+	}
+}
+
+// CancelarSolicitud retira de la lista de espera una solicitud que aún no recibió oferta
+func (s *ListaEsperaService) CancelarSolicitud(id int) error {
+	return s.repo.CambiarEstado(id, domain.EsperaEnCola, domain.EsperaCancelada)
+}
+
+// GetOferta obtiene la solicitud de una oferta y la reserva retenida para ella
+func (s *ListaEsperaService) GetOferta(token string) (*domain.SolicitudEspera, *domain.Reserva, error) {
+	solicitud, err := s.repo.GetSolicitudByToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserva, err := s.reservaService.GetReservaByID(*solicitud.ReservaID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return solicitud, reserva, nil
+}
+
+// ConfirmarOferta confirma la reserva ofrecida a la solicitud mientras su hold siga vigente.
+// Retorna domain.ErrOfertaVencida si la oferta ya no se puede confirmar.
+func (s *ListaEsperaService) ConfirmarOferta(token string) (*domain.Reserva, error) {
+	solicitud, err := s.repo.GetSolicitudByToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if solicitud.Estado != domain.EsperaOfertada {
+		return nil, domain.ErrOfertaVencida
+	}
+
+	err = s.reservaService.ConfirmarReserva(*solicitud.ReservaID, solicitud.Email)
+	var transicion *domain.TransicionInvalidaError
+	if errors.Is(err, domain.ErrReservaExpirada) || errors.As(err, &transicion) {
+		if err := s.repo.CambiarEstado(solicitud.ID, domain.EsperaOfertada, domain.EsperaVencida); err != nil {
+			fmt.Printf("Error al vencer oferta de lista de espera %d: %v\n", solicitud.ID, err)
+		}
+		return nil, domain.ErrOfertaVencida
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CambiarEstado(solicitud.ID, domain.EsperaOfertada, domain.EsperaAceptada); err != nil {
+		return nil, err
+	}
+
+	return s.reservaService.GetReservaByID(*solicitud.ReservaID)
+}
+
+// ProcesarListaEspera recorre las solicitudes en espera en orden de registro y a cada una que
+// pueda alojarse con el inventario actual le retiene una reserva pendiente y le envía la oferta
+// por email. Antes vence las ofertas no confirmadas a tiempo. Retorna cuántas ofertas hizo.
+func (s *ListaEsperaService) ProcesarListaEspera() (int, error) {
+	s.procesando.Lock()
+	defer s.procesando.Unlock()
+
+	if err := s.repo.VencerSolicitudes(time.Now(), horario.HoyPeru()); err != nil {
+		return 0, err
+	}
+
+	solicitudes, err := s.repo.GetSolicitudes(domain.EsperaEnCola)
+	if err != nil {
+		return 0, err
+	}
+
+	ofertas := 0
+	for i := range solicitudes {
+		ofertada, err := s.ofertar(&solicitudes[i])
+		if err != nil {
+			fmt.Printf("Error al ofertar a la solicitud de lista de espera %d: %v\n", solicitudes[i].ID, err)
+			continue
+		}
+		if ofertada {
+			ofertas++
+		}
+	}
+
+	return ofertas, nil
+}
+
+// ofertar intenta retener una reserva por tipo para la solicitud, probando sus tipos preferidos
+// en orden (o todos si no tiene preferencia). Retorna false si ningún tipo tiene cupo.
+func (s *ListaEsperaService) ofertar(solicitud *domain.SolicitudEspera) (bool, error) {
+	tipos := solicitud.TiposHabitacion
+	if len(tipos) == 0 {
+		todos, err := s.habitacionRepo.GetRoomTypes()
+		if err != nil {
+			return false, err
+		}
+		for _, tipo := range todos {
+			tipos = append(tipos, tipo.ID)
+		}
+	}
+
+	for _, tipoID := range tipos {
+		tipo, err := s.habitacionRepo.GetRoomTypeByID(tipoID)
+		if err != nil {
+			// El tipo pudo desactivarse después del registro
+			continue
+		}
+
+		cantidad := habitacionesNecesarias(*tipo, solicitud.CantidadAdultos, solicitud.CantidadNinhos)
+		if cantidad == 0 {
+			continue
+		}
+
+		reserva := &domain.Reserva{
+			ClienteID:       solicitud.Email,
+			CantidadAdultos: solicitud.CantidadAdultos,
+			CantidadNinhos:  solicitud.CantidadNinhos,
+			Estado:          domain.ReservaPendiente,
+			TiposHabitacion: []domain.ReservaTipoHabitacion{{
+				TipoHabitacionID: tipoID,
+				Cantidad:         cantidad,
+				FechaEntrada:     solicitud.FechaEntrada,
+				FechaSalida:      solicitud.FechaSalida,
+			}},
+		}
+
+		err = s.reservaService.crearReserva(reserva, s.duracionOferta, domain.ActorSistema)
+		var agotado *domain.TipoHabitacionAgotadoError
+		var restriccion *domain.RestriccionEstadiaError
+		if errors.As(err, &agotado) || errors.As(err, &restriccion) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if err := s.registrarOferta(solicitud, reserva); err != nil {
+			// Sin oferta registrada nadie confirmaría el hold; se libera de inmediato
+			if _, errCancelar := s.reservaService.CancelarReserva(reserva.ID, domain.ActorSistema); errCancelar != nil {
+				fmt.Printf("Error al liberar la reserva %d de la lista de espera: %v\n", reserva.ID, errCancelar)
+			}
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// registrarOferta asocia la reserva retenida a la solicitud y le envía la oferta por email
+func (s *ListaEsperaService) registrarOferta(solicitud *domain.SolicitudEspera, reserva *domain.Reserva) error {
+	token, err := generarTokenOferta()
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RegistrarOferta(solicitud.ID, reserva.ID, token, *reserva.ExpiraEn); err != nil {
+		return err
+	}
+
+	if s.emailClient != nil {
+		if err := s.enviarEmailOferta(reserva, token); err != nil {
+			// Log error pero no fallar, la oferta ya quedó registrada
+			fmt.Printf("Error al enviar email de oferta de lista de espera: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// enviarEmailOferta envía al huésped el detalle de la reserva retenida y el enlace para confirmarla
+func (s *ListaEsperaService) enviarEmailOferta(reserva *domain.Reserva, token string) error {
+	reservaInfo, err := s.reservaService.reservaInfoEmailConCancelacion(reserva)
+	if err != nil {
+		return err
+	}
+
+	enlace := strings.TrimRight(s.urlSitio, "/") + "/lista-espera/confirmar?token=" + url.QueryEscape(token)
+	return s.emailClient.SendOfertaListaEspera(reservaInfo, enlace, reserva.ExpiraEn.In(horario.Peru))
+}
+
+// habitacionesNecesarias retorna cuántas habitaciones del tipo alojan al grupo, o 0 si el tipo no
+// admite adultos
+func habitacionesNecesarias(tipo domain.TipoHabitacion, adultos, ninhos int) int {
+	if tipo.CapacidadAdultos < 1 {
+		return 0
+	}
+
+	porAdultos := (adultos + tipo.CapacidadAdultos - 1) / tipo.CapacidadAdultos
+	plazas := tipo.CapacidadAdultos + tipo.CapacidadNinhos
+	porHuespedes := (adultos + ninhos + plazas - 1) / plazas
+	return max(porAdultos, porHuespedes)
+}
+
+// generarTokenOferta genera el token aleatorio que identifica una oferta en el enlace del email
+func generarTokenOferta() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("error al generar token de oferta: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+// ResumenNoShow resume una ejecución del proceso de reservas no presentadas
+type ResumenNoShow struct {
+	Reservas       []int   // IDs de las reservas marcadas como NoShow
+	TotalPenalidad float64 // suma de las penalidades aplicadas
+	EmailsFallidos int     // avisos al huésped que no se pudieron enviar
+}
+
+// diasAtrasNoShow es cuántos días antes de la fecha límite todavía se revisan llegadas, para
+// cubrir una ejecución perdida. Las llegadas más antiguas no se marcan automáticamente: las
+// reservas que quedaron confirmadas antes de existir este proceso las resuelve recepción.
+const diasAtrasNoShow = 1
+
+// ProcesarNoShows marca como NoShow las reservas confirmadas cuyos huéspedes no llegaron. Una
+// llegada se considera no presentada desde la hora de corte (hora de Perú) de su fecha de
+// entrada y solo se revisan las llegadas hasta diasAtrasNoShow días antes. Aplica la penalidad de
+// la política de cancelación, devuelve al inventario las noches desde hoy y avisa al huésped por
+// email.
+func (s *ReservaService) ProcesarNoShows(horaCorte int) (*ResumenNoShow, error) {
+	ahora := time.Now().In(horario.Peru)
+	fechaLimite := horario.HoyPeru()
+	if ahora.Hour() < horaCorte {
+		fechaLimite = fechaLimite.AddDate(0, 0, -1)
+	}
+
+	reservas, err := s.reservaRepo.GetReservasNoShow(fechaLimite.AddDate(0, 0, -diasAtrasNoShow), fechaLimite)
+	if err != nil {
+		return nil, err
+	}
+
+	resumen := &ResumenNoShow{}
+	for i := range reservas {
+		reserva, penalidad, err := s.marcarNoShow(reservas[i].ID, domain.ActorSistema)
+		if err != nil {
+			// Si recepción registró la llegada entretanto, ya no hay nada que marcar
+			var transicion *domain.TransicionInvalidaError
+			if !errors.As(err, &transicion) {
+				fmt.Printf("Error al marcar reserva %d como no presentada: %v\n", reservas[i].ID, err)
+			}
+			continue
+		}
+
+		resumen.Reservas = append(resumen.Reservas, reserva.ID)
+		resumen.TotalPenalidad = redondear(resumen.TotalPenalidad + penalidad.Penalidad)
+
+		if s.emailClient != nil {
+			if err := s.enviarEmailNoShow(reserva, penalidad.Penalidad); err != nil {
+				fmt.Printf("Error al enviar email de no presentación de la reserva %d: %v\n", reserva.ID, err)
+				resumen.EmailsFallidos++
+			}
+		}
+	}
+
+	if len(resumen.Reservas) > 0 {
+		s.inventarioLiberado()
+	}
+
+	return resumen, nil
+}
+
+// marcarNoShow pasa la reserva a NoShow con la penalidad de su política de cancelación y libera
+// sus noches desde hoy. La fecha de entrada ya debe haber llegado.
+func (s *ReservaService) marcarNoShow(id int, actor string) (*domain.Reserva, *domain.PenalidadCancelacion, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := domain.ValidarTransicion(reserva.Estado, domain.ReservaNoShow); err != nil {
+		return nil, nil, err
+	}
+
+	hoy := horario.HoyPeru()
+	llegada, _ := rangoEstadia(reserva)
+	if hoy.Format("2006-01-02") < llegada.Format("2006-01-02") {
+		return nil, nil, &domain.FechaRecepcionError{Operacion: "no-show", Hoy: hoy, Fecha: llegada, Motivo: "la estadía aún no comienza"}
+	}
+
+	if err := s.cargarHabitaciones(reserva); err != nil {
+		return nil, nil, err
+	}
+
+	penalidad, err := s.politicaService.CalcularPenalidad(reserva, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cambio := nuevoCambioEstado(reserva, domain.ReservaNoShow, actor)
+	cambio.Fecha = penalidad.FechaCalculo
+	if err := s.reservaRepo.MarcarNoShow(cambio, penalidad, hoy); err != nil {
+		return nil, nil, err
+	}
+
+	return reserva, penalidad, nil
+}
+
+// enviarEmailNoShow avisa al huésped que su reserva se marcó como no presentada
+func (s *ReservaService) enviarEmailNoShow(reserva *domain.Reserva, penalidad float64) error {
+	reservaInfo, err := reservaInfoEmail(reserva)
+	if err != nil {
+		return err
+	}
+
+	llegada, _ := rangoEstadia(reserva)
+	return s.emailClient.SendReservaNoShow(reservaInfo, llegada, penalidad)
+}
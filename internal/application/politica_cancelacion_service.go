@@ -0,0 +1,178 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// politicaPorDefecto se aplica cuando ni el plan de tarifa ni el tipo de habitación tienen una
+// política configurada: cancelación gratuita hasta 48 horas antes, luego se cobra la primera noche
+var politicaPorDefecto = domain.PoliticaCancelacion{
+	Nombre:        "Estándar",
+	HorasLibres:   48,
+	TipoPenalidad: domain.PenalidadPrimeraNoche,
+	Activo:        true,
+}
+
+type PoliticaCancelacionService struct {
+	repo domain.PoliticaCancelacionRepository
+}
+
+// NewPoliticaCancelacionService crea una nueva instancia del servicio de políticas de cancelación
+func NewPoliticaCancelacionService(repo domain.PoliticaCancelacionRepository) *PoliticaCancelacionService {
+	return &PoliticaCancelacionService{
+		repo: repo,
+	}
+}
+
+// GetPoliticas obtiene todas las políticas de cancelación
+func (s *PoliticaCancelacionService) GetPoliticas() ([]domain.PoliticaCancelacion, error) {
+	return s.repo.GetPoliticas()
+}
+
+// CreatePolitica valida y crea una nueva política de cancelación
+func (s *PoliticaCancelacionService) CreatePolitica(politica *domain.PoliticaCancelacion) error {
+	if err := validarPoliticaCancelacion(politica); err != nil {
+		return err
+	}
+	return s.repo.CreatePolitica(politica)
+}
+
+// UpdatePolitica valida y actualiza una política de cancelación
+func (s *PoliticaCancelacionService) UpdatePolitica(politica *domain.PoliticaCancelacion) error {
+	if err := validarPoliticaCancelacion(politica); err != nil {
+		return err
+	}
+	return s.repo.UpdatePolitica(politica)
+}
+
+// CalcularPenalidad calcula la penalidad y el monto reembolsable si la reserva se cancela en el
+// momento dado. Cada habitación usa la política de su plan de tarifa, la de su tipo de habitación
+// o la política por defecto, y el plazo se mide contra la hora de check-in en Perú. Solo las
+// reservas ya pagadas tienen penalidad y reembolso. Las habitaciones deben tener cargada su
+// información de tipo.
+func (s *PoliticaCancelacionService) CalcularPenalidad(reserva *domain.Reserva, ahora time.Time) (*domain.PenalidadCancelacion, error) {
+	return s.calcularPenalidad(reserva, reserva.Unidades(), reserva.Total, ahora)
+}
+
+// CalcularPenalidadParcial calcula la penalidad y el reembolso de cancelar solo las habitaciones
+// dadas de la reserva, cada una con su política. monto es lo que la reserva deja de cobrar sin
+// ellas y limita la penalidad como el total lo hace en una cancelación completa.
+func (s *PoliticaCancelacionService) CalcularPenalidadParcial(reserva *domain.Reserva, canceladas []domain.ReservaHabitacion, monto float64, ahora time.Time) (*domain.PenalidadCancelacion, error) {
+	return s.calcularPenalidad(reserva, canceladas, monto, ahora)
+}
+
+// calcularPenalidad aplica a cada habitación su política y reparte monto entre penalidad y reembolso
+func (s *PoliticaCancelacionService) calcularPenalidad(reserva *domain.Reserva, habitaciones []domain.ReservaHabitacion, monto float64, ahora time.Time) (*domain.PenalidadCancelacion, error) {
+	politicas, err := s.repo.GetPoliticasActivas()
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := &domain.PenalidadCancelacion{
+		ReservaID:    reserva.ID,
+		FechaCalculo: ahora,
+		Politicas:    []string{},
+	}
+
+	penalidad := 0.0
+	aplicadas := make(map[string]bool)
+	for _, hab := range habitaciones {
+		politica := resolverPolitica(politicas, hab)
+		if !aplicadas[politica.Nombre] {
+			aplicadas[politica.Nombre] = true
+			resultado.Politicas = append(resultado.Politicas, politica.Nombre)
+		}
+
+		limite := momentoCheckIn(hab.FechaEntrada).Add(-time.Duration(politica.HorasLibres) * time.Hour)
+		if resultado.LimiteGratuito.IsZero() || limite.Before(resultado.LimiteGratuito) {
+			resultado.LimiteGratuito = limite
+		}
+
+		if ahora.Before(limite) {
+			continue
+		}
+		penalidad += penalidadHabitacion(politica, hab)
+	}
+
+	// Una reserva pendiente aún no se pagó: no hay nada que retener ni devolver
+	if reserva.Estado != domain.ReservaPendiente {
+		monto = math.Max(monto, 0)
+		resultado.Penalidad = redondear(math.Min(penalidad, monto))
+		resultado.Reembolso = redondear(monto - resultado.Penalidad)
+	}
+	resultado.CancelacionGratuita = resultado.Penalidad == 0
+	return resultado, nil
+}
+
+// resolverPolitica busca la política de alguno de los planes de tarifa aplicados a la habitación,
+// luego la de su tipo de habitación y si no hay ninguna retorna la política por defecto
+func resolverPolitica(politicas []domain.PoliticaCancelacion, hab domain.ReservaHabitacion) domain.PoliticaCancelacion {
+	for _, noche := range hab.Desglose {
+		for _, planID := range noche.Planes {
+			for _, politica := range politicas {
+				if politica.PlanTarifaID != nil && *politica.PlanTarifaID == planID {
+					return politica
+				}
+			}
+		}
+	}
+
+	if hab.Habitacion != nil {
+		for _, politica := range politicas {
+			if politica.PlanTarifaID == nil && politica.TipoHabitacionID != nil &&
+				*politica.TipoHabitacionID == hab.Habitacion.TipoHabitacion.ID {
+				return politica
+			}
+		}
+	}
+
+	return politicaPorDefecto
+}
+
+// penalidadHabitacion calcula la penalidad de una habitación cancelada fuera del plazo gratuito
+func penalidadHabitacion(politica domain.PoliticaCancelacion, hab domain.ReservaHabitacion) float64 {
+	switch politica.TipoPenalidad {
+	case domain.PenalidadPorcentaje:
+		return hab.Importe() * politica.Porcentaje / 100
+	case domain.PenalidadPrimeraNoche:
+		if len(hab.Desglose) > 0 {
+			return hab.Desglose[0].Precio
+		}
+		return hab.Precio
+	}
+	return 0
+}
+
+// validarPoliticaCancelacion verifica la consistencia de la política de cancelación
+func validarPoliticaCancelacion(p *domain.PoliticaCancelacion) error {
+	p.Nombre = strings.TrimSpace(p.Nombre)
+	if p.Nombre == "" {
+		return fmt.Errorf("el nombre es requerido")
+	}
+
+	if p.HorasLibres < 0 {
+		return fmt.Errorf("horasLibres no puede ser negativo")
+	}
+
+	switch p.TipoPenalidad {
+	case domain.PenalidadPorcentaje:
+		if p.Porcentaje <= 0 || p.Porcentaje > 100 {
+			return fmt.Errorf("el porcentaje de penalidad debe estar entre 0 y 100")
+		}
+	case domain.PenalidadPrimeraNoche:
+		p.Porcentaje = 0
+	default:
+		return fmt.Errorf("tipo de penalidad inválido: %s", p.TipoPenalidad)
+	}
+
+	if p.TipoHabitacionID == nil && p.PlanTarifaID == nil {
+		return fmt.Errorf("la política debe asociarse a un tipo de habitación o a un plan de tarifa")
+	}
+
+	return nil
+}
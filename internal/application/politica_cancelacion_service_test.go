@@ -0,0 +1,232 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// politicaRepoFalso retorna siempre las mismas políticas activas
+type politicaRepoFalso struct {
+	domain.PoliticaCancelacionRepository
+	politicas []domain.PoliticaCancelacion
+}
+
+func (r *politicaRepoFalso) GetPoliticasActivas() ([]domain.PoliticaCancelacion, error) {
+	return r.politicas, nil
+}
+
+func ptrInt(v int) *int {
+	return &v
+}
+
+// reservaParaCancelar arma una reserva de una habitación de tipo 5 por dos noches desde el
+// 2026-10-20, con la primera noche a 120 y la segunda a 100 en el plan de tarifa 9
+func reservaParaCancelar(estado domain.EstadoReserva) *domain.Reserva {
+	return &domain.Reserva{
+		ID:     1,
+		Estado: estado,
+		Total:  220,
+		Habitaciones: []domain.ReservaHabitacion{{
+			HabitacionID: 3,
+			Precio:       110,
+			FechaEntrada: diaPrueba("2026-10-20"),
+			FechaSalida:  diaPrueba("2026-10-22"),
+			Desglose: []domain.PrecioNoche{
+				{Fecha: diaPrueba("2026-10-20"), Precio: 120, Planes: []int{9}},
+				{Fecha: diaPrueba("2026-10-21"), Precio: 100},
+			},
+			Habitacion: &domain.Habitacion{ID: 3, TipoHabitacion: domain.TipoHabitacion{ID: 5}},
+		}},
+	}
+}
+
+func TestCalcularPenalidad(t *testing.T) {
+	porTipo := domain.PoliticaCancelacion{
+		Nombre:           "Flexible",
+		HorasLibres:      24,
+		TipoPenalidad:    domain.PenalidadPorcentaje,
+		Porcentaje:       50,
+		TipoHabitacionID: ptrInt(5),
+		Activo:           true,
+	}
+	porPlan := domain.PoliticaCancelacion{
+		Nombre:        "No reembolsable",
+		TipoPenalidad: domain.PenalidadPorcentaje,
+		Porcentaje:    100,
+		PlanTarifaID:  ptrInt(9),
+		Activo:        true,
+	}
+
+	// El check-in es a las 15:00 de Perú del 2026-10-20, las 20:00 UTC
+	tests := []struct {
+		nombre    string
+		politicas []domain.PoliticaCancelacion
+		estado    domain.EstadoReserva
+		ahora     time.Time
+		penalidad float64
+		reembolso float64
+		limite    time.Time
+		aplicada  string
+	}{
+		{
+			nombre:    "antes del plazo gratuito por defecto",
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			penalidad: 0,
+			reembolso: 220,
+			limite:    time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+			aplicada:  "Estándar",
+		},
+		{
+			nombre:    "dentro del plazo se cobra la primera noche",
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			penalidad: 120,
+			reembolso: 100,
+			limite:    time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+			aplicada:  "Estándar",
+		},
+		{
+			nombre:    "una reserva pendiente no se pagó y no tiene penalidad ni reembolso",
+			estado:    domain.ReservaPendiente,
+			ahora:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			penalidad: 0,
+			reembolso: 0,
+			limite:    time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC),
+			aplicada:  "Estándar",
+		},
+		{
+			nombre:    "política del tipo de habitación",
+			politicas: []domain.PoliticaCancelacion{porTipo},
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 19, 21, 0, 0, 0, time.UTC),
+			penalidad: 110,
+			reembolso: 110,
+			limite:    time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC),
+			aplicada:  "Flexible",
+		},
+		{
+			nombre:    "la política del plan de tarifa tiene prioridad sobre la del tipo",
+			politicas: []domain.PoliticaCancelacion{porTipo, porPlan},
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 20, 21, 0, 0, 0, time.UTC),
+			penalidad: 220,
+			reembolso: 0,
+			limite:    time.Date(2026, 10, 20, 20, 0, 0, 0, time.UTC),
+			aplicada:  "No reembolsable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			service := NewPoliticaCancelacionService(&politicaRepoFalso{politicas: tt.politicas})
+
+			resultado, err := service.CalcularPenalidad(reservaParaCancelar(tt.estado), tt.ahora)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if resultado.Penalidad != tt.penalidad {
+				t.Errorf("penalidad %.2f, se esperaba %.2f", resultado.Penalidad, tt.penalidad)
+			}
+			if resultado.Reembolso != tt.reembolso {
+				t.Errorf("reembolso %.2f, se esperaba %.2f", resultado.Reembolso, tt.reembolso)
+			}
+			if resultado.CancelacionGratuita != (tt.penalidad == 0) {
+				t.Errorf("cancelación gratuita %v con penalidad %.2f", resultado.CancelacionGratuita, tt.penalidad)
+			}
+			if !resultado.LimiteGratuito.Equal(tt.limite) {
+				t.Errorf("límite gratuito %v, se esperaba %v", resultado.LimiteGratuito.UTC(), tt.limite)
+			}
+			if len(resultado.Politicas) != 1 || resultado.Politicas[0] != tt.aplicada {
+				t.Errorf("políticas aplicadas %v, se esperaba %q", resultado.Politicas, tt.aplicada)
+			}
+		})
+	}
+}
+
+func TestCalcularPenalidadParcial(t *testing.T) {
+	porPlan := domain.PoliticaCancelacion{
+		Nombre:        "No reembolsable",
+		TipoPenalidad: domain.PenalidadPorcentaje,
+		Porcentaje:    100,
+		PlanTarifaID:  ptrInt(9),
+		Activo:        true,
+	}
+
+	tests := []struct {
+		nombre    string
+		politicas []domain.PoliticaCancelacion
+		estado    domain.EstadoReserva
+		ahora     time.Time
+		monto     float64
+		penalidad float64
+		reembolso float64
+		aplicada  string
+	}{
+		{
+			nombre:    "antes del plazo se reembolsa lo que deja de cobrarse",
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+			monto:     259.6,
+			penalidad: 0,
+			reembolso: 259.6,
+			aplicada:  "Estándar",
+		},
+		{
+			nombre:    "dentro del plazo se cobra la primera noche de la habitación",
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			monto:     259.6,
+			penalidad: 120,
+			reembolso: 139.6,
+			aplicada:  "Estándar",
+		},
+		{
+			nombre:    "la penalidad no supera lo que deja de cobrarse",
+			politicas: []domain.PoliticaCancelacion{porPlan},
+			estado:    domain.ReservaConfirmada,
+			ahora:     time.Date(2026, 10, 20, 21, 0, 0, 0, time.UTC),
+			monto:     180,
+			penalidad: 180,
+			reembolso: 0,
+			aplicada:  "No reembolsable",
+		},
+		{
+			nombre:    "una reserva pendiente no tiene penalidad ni reembolso",
+			estado:    domain.ReservaPendiente,
+			ahora:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			monto:     259.6,
+			penalidad: 0,
+			reembolso: 0,
+			aplicada:  "Estándar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			service := NewPoliticaCancelacionService(&politicaRepoFalso{politicas: tt.politicas})
+			reserva := reservaParaCancelar(tt.estado)
+
+			resultado, err := service.CalcularPenalidadParcial(reserva, reserva.Habitaciones, tt.monto, tt.ahora)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if resultado.Penalidad != tt.penalidad {
+				t.Errorf("penalidad %.2f, se esperaba %.2f", resultado.Penalidad, tt.penalidad)
+			}
+			if resultado.Reembolso != tt.reembolso {
+				t.Errorf("reembolso %.2f, se esperaba %.2f", resultado.Reembolso, tt.reembolso)
+			}
+			if resultado.CancelacionGratuita != (tt.penalidad == 0) {
+				t.Errorf("cancelación gratuita %v con penalidad %.2f", resultado.CancelacionGratuita, tt.penalidad)
+			}
+			if len(resultado.Politicas) != 1 || resultado.Politicas[0] != tt.aplicada {
+				t.Errorf("políticas aplicadas %v, se esperaba %q", resultado.Politicas, tt.aplicada)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type PromocionService struct {
+	repo domain.PromocionRepository
+}
+
+// NewPromocionService crea una nueva instancia del servicio de promociones
+func NewPromocionService(repo domain.PromocionRepository) *PromocionService {
+	return &PromocionService{
+		repo: repo,
+	}
+}
+
+// GetPromociones obtiene todas las promociones
+func (s *PromocionService) GetPromociones() ([]domain.Promocion, error) {
+	return s.repo.GetPromociones()
+}
+
+// GetPromocionByID obtiene una promoción por su ID
+func (s *PromocionService) GetPromocionByID(id int) (*domain.Promocion, error) {
+	return s.repo.GetPromocionByID(id)
+}
+
+// CreatePromocion valida y crea una nueva promoción
+func (s *PromocionService) CreatePromocion(promocion *domain.Promocion) error {
+	if err := validarPromocion(promocion); err != nil {
+		return err
+	}
+	return s.repo.CreatePromocion(promocion)
+}
+
+// UpdatePromocion valida y actualiza una promoción
+func (s *PromocionService) UpdatePromocion(promocion *domain.Promocion) error {
+	if err := validarPromocion(promocion); err != nil {
+		return err
+	}
+	return s.repo.UpdatePromocion(promocion)
+}
+
+// DesactivarPromocion desactiva una promoción
+func (s *PromocionService) DesactivarPromocion(id int) error {
+	return s.repo.DesactivarPromocion(id)
+}
+
+// AplicarCodigo valida el código promocional para la reserva y calcula su descuento.
+// Las habitaciones de la reserva deben tener ya su precio e información de tipo.
+// Los límites de uso se verifican de forma atómica al guardar la reserva.
+func (s *PromocionService) AplicarCodigo(codigo string, reserva *domain.Reserva, fecha time.Time) error {
+	promocion, err := s.repo.GetPromocionByCodigo(strings.TrimSpace(codigo))
+	if err != nil {
+		return err
+	}
+
+	if !promocion.Activo {
+		return fmt.Errorf("el código promocional %s no está activo", promocion.Codigo)
+	}
+
+	if fecha.Before(promocion.FechaInicio) || fecha.After(finDelDia(promocion.FechaFin)) {
+		return fmt.Errorf("el código promocional %s no está vigente", promocion.Codigo)
+	}
+
+	if promocion.MaxUsos != nil && promocion.Usos >= *promocion.MaxUsos {
+		return domain.ErrPromocionAgotada
+	}
+
+	descuento := calcularDescuentoPromocion(promocion, reserva.Unidades())
+	if descuento == 0 {
+		return fmt.Errorf("el código promocional %s no aplica a las habitaciones o a la duración de la estadía", promocion.Codigo)
+	}
+
+	reserva.PromocionID = &promocion.ID
+	reserva.CodigoPromocion = promocion.Codigo
+	reserva.Descuento = descuento
+	return nil
+}
+
+// RecalcularDescuento vuelve a calcular el descuento de la promoción ya canjeada por la reserva
+// tras un cambio de habitaciones o fechas. Si la promoción deja de aplicar, se quita de la reserva.
+func (s *PromocionService) RecalcularDescuento(reserva *domain.Reserva) error {
+	reserva.Descuento = 0
+	if reserva.PromocionID == nil {
+		return nil
+	}
+
+	promocion, err := s.repo.GetPromocionByID(*reserva.PromocionID)
+	if err != nil {
+		return err
+	}
+
+	reserva.Descuento = calcularDescuentoPromocion(promocion, reserva.Unidades())
+	if reserva.Descuento == 0 {
+		reserva.PromocionID = nil
+		reserva.CodigoPromocion = ""
+	}
+	return nil
+}
+
+// calcularDescuentoPromocion calcula el descuento de la promoción sobre las habitaciones que
+// cumplen sus reglas de tipo y noches mínimas. Retorna 0 si ninguna habitación califica.
+func calcularDescuentoPromocion(promocion *domain.Promocion, habitaciones []domain.ReservaHabitacion) float64 {
+	base := 0.0
+	for _, hab := range habitaciones {
+		if hab.Habitacion == nil || !promocion.AplicaATipo(hab.Habitacion.TipoHabitacion.ID) {
+			continue
+		}
+		if hab.Noches() < promocion.MinNoches {
+			continue
+		}
+		base += hab.Importe()
+	}
+
+	if base == 0 {
+		return 0
+	}
+
+	switch promocion.TipoDescuento {
+	case domain.DescuentoPorcentaje:
+		return redondear(base * promocion.Valor / 100)
+	case domain.DescuentoMonto:
+		return redondear(math.Min(promocion.Valor, base))
+	}
+	return 0
+}
+
+// validarPromocion normaliza el código y verifica la consistencia de la promoción
+func validarPromocion(p *domain.Promocion) error {
+	p.Codigo = strings.ToUpper(strings.TrimSpace(p.Codigo))
+	if p.Codigo == "" {
+		return fmt.Errorf("el código es requerido")
+	}
+
+	switch p.TipoDescuento {
+	case domain.DescuentoPorcentaje:
+		if p.Valor <= 0 || p.Valor > 100 {
+			return fmt.Errorf("el porcentaje de descuento debe estar entre 0 y 100")
+		}
+	case domain.DescuentoMonto:
+		if p.Valor <= 0 {
+			return fmt.Errorf("el monto de descuento debe ser mayor a 0")
+		}
+	default:
+		return fmt.Errorf("tipo de descuento inválido: %s", p.TipoDescuento)
+	}
+
+	if p.FechaInicio.IsZero() || p.FechaFin.IsZero() {
+		return fmt.Errorf("fechaInicio y fechaFin son requeridas")
+	}
+
+	if p.FechaFin.Before(p.FechaInicio) {
+		return fmt.Errorf("la fecha fin debe ser posterior a la fecha inicio")
+	}
+
+	if p.MinNoches < 0 {
+		return fmt.Errorf("minNoches no puede ser negativo")
+	}
+
+	if p.MaxUsos != nil && *p.MaxUsos < 1 {
+		return fmt.Errorf("maxUsos debe ser mayor a 0")
+	}
+
+	if p.MaxUsosPorCliente != nil && *p.MaxUsosPorCliente < 1 {
+		return fmt.Errorf("maxUsosPorCliente debe ser mayor a 0")
+	}
+
+	return nil
+}
+
+// finDelDia retorna el último instante del día de la fecha dada
+func finDelDia(fecha time.Time) time.Time {
+	return time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 23, 59, 59, 0, fecha.Location())
+}
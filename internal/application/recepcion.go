@@ -0,0 +1,173 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// CheckInReserva son los datos que registra recepción al alojar a los huéspedes
+type CheckInReserva struct {
+	Huespedes []domain.HuespedRegistrado
+	// Asignaciones elige habitaciones para las reservadas por tipo; las que falten se asignan
+	// automáticamente
+	Asignaciones []domain.AsignacionHabitacion
+}
+
+// CheckOutReserva son los datos que registra recepción a la salida de los huéspedes
+type CheckOutReserva struct {
+	Cargos []domain.CargoAdicional
+	// SalidaAnticipada confirma la salida antes de la fecha reservada; las noches no usadas
+	// vuelven al inventario pero no se descuentan de la cuenta
+	SalidaAnticipada bool
+}
+
+// CheckIn registra la llegada de los huéspedes de una reserva confirmada. hoy es la fecha
+// actual en Perú y debe estar dentro de la estadía. Las habitaciones reservadas por tipo se
+// asignan antes, porque el huésped no puede alojarse sin habitación.
+func (s *ReservaService) CheckIn(id int, datos CheckInReserva, hoy time.Time, actor string) (*domain.Reserva, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidarTransicion(reserva.Estado, domain.ReservaCheckIn); err != nil {
+		return nil, err
+	}
+
+	llegada, salida := rangoEstadia(reserva)
+	if hoy.Format("2006-01-02") < llegada.Format("2006-01-02") {
+		return nil, &domain.FechaRecepcionError{Operacion: "check-in", Hoy: hoy, Fecha: llegada, Motivo: "la estadía aún no comienza"}
+	}
+	if hoy.Format("2006-01-02") >= salida.Format("2006-01-02") {
+		return nil, &domain.FechaRecepcionError{Operacion: "check-in", Hoy: hoy, Fecha: salida, Motivo: "la estadía ya terminó"}
+	}
+
+	if err := validarHuespedes(datos.Huespedes, reserva); err != nil {
+		return nil, err
+	}
+
+	if len(reserva.TiposHabitacion) > 0 || len(datos.Asignaciones) > 0 {
+		if reserva, err = s.AsignarHabitaciones(id, datos.Asignaciones); err != nil {
+			return nil, err
+		}
+	}
+
+	// Los huéspedes solo pueden ubicarse en habitaciones de la reserva
+	habitaciones := make(map[int]bool, len(reserva.Habitaciones))
+	for _, hab := range reserva.Habitaciones {
+		habitaciones[hab.HabitacionID] = true
+	}
+	for _, huesped := range datos.Huespedes {
+		if huesped.HabitacionID != nil && !habitaciones[*huesped.HabitacionID] {
+			return nil, fmt.Errorf("la habitación %d no pertenece a la reserva %d", *huesped.HabitacionID, id)
+		}
+	}
+
+	cambio := nuevoCambioEstado(reserva, domain.ReservaCheckIn, actor)
+	if err := s.reservaRepo.RegistrarCheckIn(cambio, datos.Huespedes); err != nil {
+		return nil, err
+	}
+
+	return s.reservaRepo.GetReservaByID(id)
+}
+
+// CheckOut registra la salida de los huéspedes, suma los cargos adicionales a la cuenta,
+// recalcula los montos finales y completa la reserva. hoy es la fecha actual en Perú; salir
+// antes de la fecha reservada requiere confirmar la salida anticipada.
+func (s *ReservaService) CheckOut(id int, datos CheckOutReserva, hoy time.Time, actor string) (*domain.Reserva, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidarTransicion(reserva.Estado, domain.ReservaCompletada); err != nil {
+		return nil, err
+	}
+
+	_, salida := rangoEstadia(reserva)
+	if hoy.Format("2006-01-02") < salida.Format("2006-01-02") && !datos.SalidaAnticipada {
+		return nil, &domain.FechaRecepcionError{
+			Operacion: "check-out",
+			Hoy:       hoy,
+			Fecha:     salida,
+			Motivo:    "es anterior a la fecha de salida, confirme la salida anticipada",
+		}
+	}
+
+	ahora := time.Now()
+	for _, cargo := range datos.Cargos {
+		cargo.Concepto = strings.TrimSpace(cargo.Concepto)
+		if cargo.Concepto == "" {
+			return nil, fmt.Errorf("el concepto del cargo es requerido")
+		}
+		if cargo.Monto <= 0 {
+			return nil, fmt.Errorf("el monto del cargo %q debe ser mayor a 0", cargo.Concepto)
+		}
+
+		cargo.ID = 0
+		cargo.Monto = redondear(cargo.Monto)
+		cargo.Fecha = ahora
+		reserva.Cargos = append(reserva.Cargos, cargo)
+	}
+
+	if err := s.calcularTotales(reserva); err != nil {
+		return nil, err
+	}
+
+	cambio := nuevoCambioEstado(reserva, domain.ReservaCompletada, actor)
+	cambio.Fecha = ahora
+	if err := s.reservaRepo.RegistrarCheckOut(cambio, reserva, hoy); err != nil {
+		return nil, err
+	}
+
+	return s.reservaRepo.GetReservaByID(id)
+}
+
+// validarHuespedes verifica que se registre al menos un huésped, sin superar los de la reserva,
+// y que cada uno tenga nombre y un documento de identidad válido
+func validarHuespedes(huespedes []domain.HuespedRegistrado, reserva *domain.Reserva) error {
+	if len(huespedes) == 0 {
+		return fmt.Errorf("el check-in requiere registrar al menos un huésped con su documento")
+	}
+	if len(huespedes) > reserva.CantidadAdultos+reserva.CantidadNinhos {
+		return fmt.Errorf("la reserva es para %d huésped(es), se registraron %d",
+			reserva.CantidadAdultos+reserva.CantidadNinhos, len(huespedes))
+	}
+
+	for i := range huespedes {
+		huesped := &huespedes[i]
+		huesped.Nombre = strings.TrimSpace(huesped.Nombre)
+		huesped.NumeroDocumento = strings.TrimSpace(huesped.NumeroDocumento)
+		huesped.Nacionalidad = strings.ToUpper(strings.TrimSpace(huesped.Nacionalidad))
+
+		if huesped.Nombre == "" {
+			return fmt.Errorf("el nombre del huésped es requerido")
+		}
+		if huesped.TipoDocumento == "" {
+			return fmt.Errorf("el tipo de documento del huésped %s es requerido", huesped.Nombre)
+		}
+		if err := validarTipoDocumento(huesped.TipoDocumento, huesped.NumeroDocumento); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rangoEstadia retorna la primera fecha de entrada y la última fecha de salida de las
+// habitaciones de la reserva
+func rangoEstadia(reserva *domain.Reserva) (time.Time, time.Time) {
+	var llegada, salida time.Time
+	for i, hab := range reserva.Unidades() {
+		if i == 0 || hab.FechaEntrada.Before(llegada) {
+			llegada = hab.FechaEntrada
+		}
+		if i == 0 || hab.FechaSalida.After(salida) {
+			salida = hab.FechaSalida
+		}
+	}
+	return llegada, salida
+}
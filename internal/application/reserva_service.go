@@ -1,18 +1,30 @@
 package application
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/domain"
 	"github.com/Maxito7/hotel_backend/internal/email"
+	"github.com/Maxito7/hotel_backend/internal/horario"
 )
 
 type ReservaService struct {
 	reservaRepo           domain.ReservaRepository
 	reservaHabitacionRepo domain.ReservaHabitacionRepository
 	habitacionRepo        domain.HabitacionRepository
+	tarifaService         *TarifaService
+	restriccionService    *RestriccionEstadiaService
+	promocionService      *PromocionService
+	politicaService       *PoliticaCancelacionService
+	impuestos             *CalculadoraImpuestos
+	duracionHold          time.Duration
 	emailClient           *email.Client
+	// alLiberarInventario se ejecuta cuando una cancelación, un hold vencido o una no presentación
+	// devuelven habitaciones al inventario
+	alLiberarInventario func()
 }
 
 // NewReservaService crea una nueva instancia del servicio de reservas
@@ -20,89 +32,338 @@ func NewReservaService(
 	reservaRepo domain.ReservaRepository,
 	reservaHabitacionRepo domain.ReservaHabitacionRepository,
 	habitacionRepo domain.HabitacionRepository,
+	tarifaService *TarifaService,
+	restriccionService *RestriccionEstadiaService,
+	promocionService *PromocionService,
+	politicaService *PoliticaCancelacionService,
+	impuestos *CalculadoraImpuestos,
+	duracionHold time.Duration,
 	emailClient *email.Client,
 ) *ReservaService {
 	return &ReservaService{
 		reservaRepo:           reservaRepo,
 		reservaHabitacionRepo: reservaHabitacionRepo,
 		habitacionRepo:        habitacionRepo,
+		tarifaService:         tarifaService,
+		restriccionService:    restriccionService,
+		promocionService:      promocionService,
+		politicaService:       politicaService,
+		impuestos:             impuestos,
+		duracionHold:          duracionHold,
 		emailClient:           emailClient,
 	}
 }
 
-// CreateReserva crea una nueva reserva validando disponibilidad
-func (s *ReservaService) CreateReserva(reserva *domain.Reserva) error {
+// AlLiberarInventario registra la función que se ejecuta, en segundo plano, cada vez que se
+// devuelven habitaciones al inventario
+func (s *ReservaService) AlLiberarInventario(f func()) {
+	s.alLiberarInventario = f
+}
+
+// inventarioLiberado avisa que se devolvieron habitaciones al inventario
+func (s *ReservaService) inventarioLiberado() {
+	if s.alLiberarInventario != nil {
+		go s.alLiberarInventario()
+	}
+}
+
+// ModificacionReserva contiene los cambios solicitados sobre una reserva existente.
+// Los campos nil o vacíos se mantienen como están.
+type ModificacionReserva struct {
+	CantidadAdultos *int
+	CantidadNinhos  *int
+	// FechaEntrada y FechaSalida mueven todas las habitaciones actuales a las nuevas fechas
+	FechaEntrada *time.Time
+	FechaSalida  *time.Time
+	// Habitaciones reemplaza por completo las habitaciones de la reserva
+	Habitaciones []domain.ReservaHabitacion
+}
+
+// CreateReserva crea una nueva reserva validando disponibilidad. El precio y el descuento se
+// calculan en el servidor; si la reserva trae CodigoPromocion se aplica la promoción. Las
+// habitaciones pueden reservarse por número o por tipo (TiposHabitacion), en cuyo caso se
+// asignan después con AsignarHabitaciones. El actor queda como autor del estado inicial en el
+// historial.
+func (s *ReservaService) CreateReserva(reserva *domain.Reserva, actor string) error {
+	return s.crearReserva(reserva, s.duracionHold, actor)
+}
+
+// crearReserva crea la reserva; si queda pendiente retiene sus habitaciones durante duracionHold
+func (s *ReservaService) crearReserva(reserva *domain.Reserva, duracionHold time.Duration, actor string) error {
 	// Validar que la reserva tenga habitaciones
-	if len(reserva.Habitaciones) == 0 {
+	if len(reserva.Habitaciones) == 0 && len(reserva.TiposHabitacion) == 0 {
 		return fmt.Errorf("la reserva debe tener al menos una habitación")
 	}
 
-	// Validar fechas y disponibilidad de cada habitación
+	// El clienteId es el email del cliente y vincula la reserva a su perfil
+	reserva.ClienteID = strings.ToLower(strings.TrimSpace(reserva.ClienteID))
+	if !strings.Contains(reserva.ClienteID, "@") {
+		return fmt.Errorf("el clienteId debe ser el email del cliente")
+	}
+
+	if err := validarDocumento(reserva); err != nil {
+		return err
+	}
+
+	if err := s.cotizarHabitaciones(reserva); err != nil {
+		return err
+	}
+
+	if err := s.cotizarTipos(reserva); err != nil {
+		return err
+	}
+
+	if err := s.validarRestricciones(reserva); err != nil {
+		return err
+	}
+
+	// El descuento solo proviene de un código promocional válido
+	reserva.Descuento = 0
+	reserva.PromocionID = nil
+	if reserva.CodigoPromocion != "" {
+		if err := s.promocionService.AplicarCodigo(reserva.CodigoPromocion, reserva, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.calcularTotales(reserva); err != nil {
+		return err
+	}
+
+	// Establecer fecha de confirmación si no se proporcionó
+	if reserva.FechaConfirmacion.IsZero() {
+		reserva.FechaConfirmacion = time.Now()
+	}
+
+	// Establecer estado inicial si no se especificó
+	if reserva.Estado == "" {
+		reserva.Estado = domain.ReservaPendiente
+	}
+
+	// Una reserva pendiente retiene sus habitaciones solo durante el hold
+	reserva.ExpiraEn = nil
+	if reserva.Estado == domain.ReservaPendiente {
+		expiraEn := time.Now().Add(duracionHold)
+		reserva.ExpiraEn = &expiraEn
+	}
+
+	// Crear la reserva con un código de confirmación único, reintentando ante una colisión
+	for intento := 1; ; intento++ {
+		codigo, err := generarCodigoReserva()
+		if err != nil {
+			return err
+		}
+		reserva.CodigoReserva = codigo
+
+		err = s.reservaRepo.CreateReserva(reserva, actor)
+		if errors.Is(err, domain.ErrCodigoReservaDuplicado) && intento < intentosCodigoReserva {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error al crear reserva: %w", err)
+		}
+		return nil
+	}
+}
+
+// ModificarReserva cambia las fechas, habitaciones o cantidad de huéspedes de una reserva
+// pendiente o confirmada. La disponibilidad se verifica sin considerar las habitaciones de la
+// propia reserva, el precio se recalcula y las habitaciones se reemplazan de forma atómica.
+func (s *ReservaService) ModificarReserva(id int, cambios ModificacionReserva) (*domain.Reserva, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reserva.Estado != domain.ReservaPendiente && reserva.Estado != domain.ReservaConfirmada {
+		return nil, fmt.Errorf("no se puede modificar una reserva en estado %s", reserva.Estado)
+	}
+
+	if reserva.Expirada(time.Now()) {
+		return nil, domain.ErrReservaExpirada
+	}
+
+	// Las fechas y habitaciones se modifican sobre habitaciones concretas
+	if len(reserva.TiposHabitacion) > 0 {
+		return nil, fmt.Errorf("asigne las habitaciones reservadas por tipo antes de modificar la reserva")
+	}
+
+	if cambios.CantidadAdultos != nil {
+		reserva.CantidadAdultos = *cambios.CantidadAdultos
+	}
+	if cambios.CantidadNinhos != nil {
+		reserva.CantidadNinhos = *cambios.CantidadNinhos
+	}
+	if reserva.CantidadAdultos < 1 || reserva.CantidadNinhos < 0 {
+		return nil, fmt.Errorf("la reserva debe tener al menos un adulto")
+	}
+
+	if len(cambios.Habitaciones) > 0 {
+		reserva.Habitaciones = cambios.Habitaciones
+	} else {
+		for i := range reserva.Habitaciones {
+			if cambios.FechaEntrada != nil {
+				reserva.Habitaciones[i].FechaEntrada = *cambios.FechaEntrada
+			}
+			if cambios.FechaSalida != nil {
+				reserva.Habitaciones[i].FechaSalida = *cambios.FechaSalida
+			}
+			// El precio anterior no es una cotización de las nuevas fechas
+			reserva.Habitaciones[i].Precio = 0
+		}
+	}
+
+	if len(reserva.Habitaciones) == 0 {
+		return nil, fmt.Errorf("la reserva debe tener al menos una habitación")
+	}
+
+	if err := s.cotizarHabitaciones(reserva); err != nil {
+		return nil, err
+	}
+
+	// Las restricciones de estadía solo aplican si cambia la estadía
+	if len(cambios.Habitaciones) > 0 || cambios.FechaEntrada != nil || cambios.FechaSalida != nil {
+		if err := s.validarRestricciones(reserva); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.promocionService.RecalcularDescuento(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.calcularTotales(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.reservaRepo.ModificarReserva(reserva); err != nil {
+		return nil, fmt.Errorf("error al modificar reserva: %w", err)
+	}
+
+	if s.emailClient != nil {
+		if err := s.enviarEmailModificacion(reserva); err != nil {
+			// Log error pero no fallar, la modificación ya se guardó
+			fmt.Printf("Error al enviar email de modificación: %v\n", err)
+		}
+	}
+
+	return reserva, nil
+}
+
+// cotizarHabitaciones valida las fechas y la disponibilidad de cada habitación de la reserva y
+// calcula su tarifa en el servidor. Las habitaciones de la propia reserva no cuentan como ocupadas.
+func (s *ReservaService) cotizarHabitaciones(reserva *domain.Reserva) error {
 	for i, hab := range reserva.Habitaciones {
 		// Validar que fecha de salida sea posterior a fecha de entrada
 		if !hab.FechaSalida.After(hab.FechaEntrada) {
 			return fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada para la habitación %d", hab.HabitacionID)
 		}
 
-		// Verificar disponibilidad
-		disponible, err := s.reservaHabitacionRepo.VerificarDisponibilidad(
+		// Verificar disponibilidad (el repositorio la vuelve a verificar de forma atómica al insertar)
+		disponible, err := s.reservaHabitacionRepo.VerificarDisponibilidadExcluyendo(
 			hab.HabitacionID,
 			hab.FechaEntrada,
 			hab.FechaSalida,
+			reserva.ID,
 		)
 		if err != nil {
 			return fmt.Errorf("error al verificar disponibilidad: %w", err)
 		}
 
 		if !disponible {
-			return fmt.Errorf("la habitación %d no está disponible para las fechas seleccionadas", hab.HabitacionID)
+			return &domain.HabitacionNoDisponibleError{HabitacionID: hab.HabitacionID}
 		}
 
-		// Validar que se haya proporcionado un precio
-		if hab.Precio <= 0 {
-			return fmt.Errorf("el precio de la habitación %d debe ser mayor a 0", hab.HabitacionID)
+		habitacion, err := s.habitacionRepo.GetRoomByID(hab.HabitacionID)
+		if err != nil {
+			return err
 		}
+		reserva.Habitaciones[i].Habitacion = habitacion
 
-		reserva.Habitaciones[i].Precio = hab.Precio
-	}
+		// Calcular la tarifa en el servidor; el precio enviado por el cliente es solo una cotización
+		cotizacion, err := s.tarifaService.CotizarTipo(habitacion.TipoHabitacion, hab.FechaEntrada, hab.FechaSalida)
+		if err != nil {
+			return fmt.Errorf("error al calcular la tarifa de la habitación %d: %w", hab.HabitacionID, err)
+		}
+		cotizacion.HabitacionID = hab.HabitacionID
 
-	// Calcular subtotal
-	subtotal := 0.0
-	for _, hab := range reserva.Habitaciones {
-		// Calcular días de estancia
-		dias := hab.FechaSalida.Sub(hab.FechaEntrada).Hours() / 24
-		if dias < 1 {
-			dias = 1
+		if err := s.tarifaService.ValidarPrecioCotizado(hab.Precio, cotizacion); err != nil {
+			return err
 		}
-		subtotal += hab.Precio * dias
-	}
-	reserva.Subtotal = subtotal
 
-	// Si no se especificó descuento, establecerlo en 0
-	if reserva.Descuento < 0 {
-		reserva.Descuento = 0
+		reserva.Habitaciones[i].Precio = cotizacion.PrecioPromedio
+		reserva.Habitaciones[i].Desglose = cotizacion.Noches
 	}
 
-	// Validar que el descuento no sea mayor al subtotal
-	if reserva.Descuento > reserva.Subtotal {
-		return fmt.Errorf("el descuento no puede ser mayor al subtotal")
+	return nil
+}
+
+// cotizarTipos valida las habitaciones reservadas por tipo y calcula su tarifa en el servidor.
+// La capacidad de cada tipo la verifica el repositorio de forma atómica al insertar.
+func (s *ReservaService) cotizarTipos(reserva *domain.Reserva) error {
+	for i, tipo := range reserva.TiposHabitacion {
+		if tipo.Cantidad < 1 {
+			return fmt.Errorf("la cantidad de habitaciones del tipo %d debe ser al menos 1", tipo.TipoHabitacionID)
+		}
+
+		if !tipo.FechaSalida.After(tipo.FechaEntrada) {
+			return fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada para el tipo de habitación %d", tipo.TipoHabitacionID)
+		}
+
+		tipoHabitacion, err := s.habitacionRepo.GetRoomTypeByID(tipo.TipoHabitacionID)
+		if err != nil {
+			return err
+		}
+		reserva.TiposHabitacion[i].TipoHabitacion = tipoHabitacion
+
+		cotizacion, err := s.tarifaService.CotizarTipo(*tipoHabitacion, tipo.FechaEntrada, tipo.FechaSalida)
+		if err != nil {
+			return fmt.Errorf("error al calcular la tarifa del tipo de habitación %d: %w", tipo.TipoHabitacionID, err)
+		}
+
+		if err := s.tarifaService.ValidarPrecioCotizado(tipo.Precio, cotizacion); err != nil {
+			return err
+		}
+
+		reserva.TiposHabitacion[i].Precio = cotizacion.PrecioPromedio
+		reserva.TiposHabitacion[i].Desglose = cotizacion.Noches
 	}
 
-	// Establecer fecha de confirmación si no se proporcionó
-	if reserva.FechaConfirmacion.IsZero() {
-		reserva.FechaConfirmacion = time.Now()
+	return nil
+}
+
+// validarRestricciones verifica que cada habitación de la reserva, asignada o reservada por tipo,
+// cumpla las restricciones de estadía de su tipo. Las habitaciones deben estar cotizadas.
+func (s *ReservaService) validarRestricciones(reserva *domain.Reserva) error {
+	for _, hab := range reserva.Unidades() {
+		if hab.Habitacion == nil {
+			continue
+		}
+		if err := s.restriccionService.ValidarEstadia(hab.Habitacion.TipoHabitacion, hab.FechaEntrada, hab.FechaSalida); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// Establecer estado inicial si no se especificó
-	if reserva.Estado == "" {
-		reserva.Estado = domain.ReservaPendiente
+// calcularTotales calcula el subtotal a partir del desglose por noche y, con el descuento ya
+// asignado, el cargo por servicio, el IGV y el total
+func (s *ReservaService) calcularTotales(reserva *domain.Reserva) error {
+	subtotal := 0.0
+	for _, hab := range reserva.Unidades() {
+		subtotal += hab.Importe()
 	}
+	for _, cargo := range reserva.Cargos {
+		subtotal += cargo.Monto
+	}
+	reserva.Subtotal = redondear(subtotal)
 
-	// Crear la reserva
-	if err := s.reservaRepo.CreateReserva(reserva); err != nil {
-		return fmt.Errorf("error al crear reserva: %w", err)
+	// Validar que el descuento no sea mayor al subtotal
+	if reserva.Descuento > reserva.Subtotal {
+		return fmt.Errorf("el descuento no puede ser mayor al subtotal")
 	}
 
+	s.impuestos.Aplicar(reserva)
 	return nil
 }
 
@@ -111,66 +372,289 @@ func (s *ReservaService) GetReservaByID(id int) (*domain.Reserva, error) {
 	return s.reservaRepo.GetReservaByID(id)
 }
 
+// GetReservaPorCodigo obtiene una reserva por su código de confirmación. El email del huésped
+// actúa como segundo factor: si no coincide se responde igual que si el código no existiera,
+// para no revelar qué códigos son válidos.
+func (s *ReservaService) GetReservaPorCodigo(codigo, emailHuesped string) (*domain.Reserva, error) {
+	codigo = normalizarCodigoReserva(codigo)
+	emailHuesped = strings.TrimSpace(emailHuesped)
+	if codigo == "" || emailHuesped == "" {
+		return nil, fmt.Errorf("el código de reserva y el email son requeridos")
+	}
+
+	reserva, err := s.reservaRepo.GetReservaByCodigo(codigo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(reserva.ClienteID, emailHuesped) {
+		return nil, domain.ErrReservaNoEncontrada
+	}
+
+	return reserva, nil
+}
+
 // GetReservasCliente obtiene todas las reservas de un cliente
 func (s *ReservaService) GetReservasCliente(clienteID string) ([]domain.Reserva, error) {
 	return s.reservaRepo.GetReservasCliente(clienteID)
 }
 
-// UpdateReservaEstado actualiza el estado de una reserva
-func (s *ReservaService) UpdateReservaEstado(id int, estado domain.EstadoReserva) error {
-	// Validar que el estado sea válido
-	validEstados := map[domain.EstadoReserva]bool{
-		domain.ReservaPendiente:  true,
-		domain.ReservaConfirmada: true,
-		domain.ReservaCancelada:  true,
-		domain.ReservaCompletada: true,
+// UpdateReservaEstado cambia el estado de una reserva según su ciclo de vida. La cancelación
+// aplica la política de cancelación y la reactivación de una reserva cancelada vuelve a
+// verificar la disponibilidad de sus habitaciones. La no presentación se procesa como en
+// ProcesarNoShows. El check-in y el check-out se registran con
+// CheckIn y CheckOut. El actor queda registrado en el historial.
+func (s *ReservaService) UpdateReservaEstado(id int, estado domain.EstadoReserva, actor string) error {
+	if !estado.Valido() {
+		return fmt.Errorf("estado de reserva inválido: %s", estado)
 	}
 
-	if !validEstados[estado] {
-		return fmt.Errorf("estado de reserva inválido: %s", estado)
+	// La cancelación siempre pasa por la política de cancelación
+	if estado == domain.ReservaCancelada {
+		_, err := s.CancelarReserva(id, actor)
+		return err
+	}
+
+	// La no presentación aplica penalidad y libera las noches restantes
+	if estado == domain.ReservaNoShow {
+		_, _, err := s.marcarNoShow(id, actor)
+		return err
+	}
+
+	// La llegada y la salida registran huéspedes y cargos, no basta con cambiar el estado
+	if estado == domain.ReservaCheckIn || estado == domain.ReservaCompletada {
+		return fmt.Errorf("el estado %s se registra con el check-in o el check-out de la reserva", estado)
 	}
 
-	// Obtener la reserva actual
 	reserva, err := s.reservaRepo.GetReservaByID(id)
 	if err != nil {
 		return fmt.Errorf("error al obtener reserva: %w", err)
 	}
 
-	// Si se está cancelando, actualizar el estado de las habitaciones
-	if estado == domain.ReservaCancelada {
-		for _, hab := range reserva.Habitaciones {
-			if err := s.reservaHabitacionRepo.UpdateReservaHabitacionEstado(
-				id,
-				hab.HabitacionID,
-				0, // Estado cancelado
-			); err != nil {
-				return fmt.Errorf("error al cancelar habitación: %w", err)
+	if err := domain.ValidarTransicion(reserva.Estado, estado); err != nil {
+		return err
+	}
+
+	// Un hold vencido ya no retiene las habitaciones, no se puede confirmar
+	if reserva.Expirada(time.Now()) {
+		return domain.ErrReservaExpirada
+	}
+
+	cambio := nuevoCambioEstado(reserva, estado, actor)
+	if domain.EsReactivacion(reserva.Estado, estado) {
+		var expiraEn *time.Time
+		if estado == domain.ReservaPendiente {
+			vencimiento := cambio.Fecha.Add(s.duracionHold)
+			expiraEn = &vencimiento
+		}
+		return s.reservaRepo.ReactivarReserva(cambio, expiraEn)
+	}
+
+	if err := s.reservaRepo.CambiarEstado(cambio); err != nil {
+		return err
+	}
+
+	// Al confirmar se intenta asignar las habitaciones reservadas por tipo; si no es posible
+	// quedan pendientes para asignarlas manualmente o al hacer check-in
+	if estado == domain.ReservaConfirmada && len(reserva.TiposHabitacion) > 0 {
+		if _, err := s.AsignarHabitaciones(id, nil); err != nil {
+			fmt.Printf("Error al asignar habitaciones de la reserva %d: %v\n", id, err)
+		}
+	}
+
+	return nil
+}
+
+// GetHistorialEstados obtiene los cambios de estado de una reserva
+func (s *ReservaService) GetHistorialEstados(id int) ([]domain.CambioEstadoReserva, error) {
+	if _, err := s.reservaRepo.GetReservaByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.reservaRepo.GetHistorialEstados(id)
+}
+
+// CancelarReserva cancela una reserva completa aplicando su política de cancelación y
+// registra la penalidad y el monto reembolsable
+func (s *ReservaService) CancelarReserva(id int, actor string) (*domain.PenalidadCancelacion, error) {
+	reserva, penalidad, err := s.calcularCancelacion(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cambio := nuevoCambioEstado(reserva, domain.ReservaCancelada, actor)
+	cambio.Fecha = penalidad.FechaCalculo
+	if err := s.reservaRepo.CancelarReserva(cambio, penalidad); err != nil {
+		return nil, err
+	}
+	s.inventarioLiberado()
+
+	return penalidad, nil
+}
+
+// ExpirarReservasPendientes cancela las reservas pendientes cuyo hold venció y libera sus
+// habitaciones. No aplica penalidad porque la reserva nunca se pagó. Retorna cuántas expiró.
+func (s *ReservaService) ExpirarReservasPendientes() (int, error) {
+	ahora := time.Now()
+	reservas, err := s.reservaRepo.GetReservasExpiradas(ahora)
+	if err != nil {
+		return 0, err
+	}
+
+	expiradas := 0
+	for i := range reservas {
+		cambio := nuevoCambioEstado(&reservas[i], domain.ReservaCancelada, domain.ActorSistema)
+		penalidad := &domain.PenalidadCancelacion{
+			ReservaID:           reservas[i].ID,
+			FechaCalculo:        cambio.Fecha,
+			CancelacionGratuita: true,
+		}
+
+		if err := s.reservaRepo.CancelarReserva(cambio, penalidad); err != nil {
+			// Si la reserva se confirmó o canceló entretanto, ya no hay nada que expirar
+			var transicion *domain.TransicionInvalidaError
+			if !errors.As(err, &transicion) {
+				fmt.Printf("Error al expirar reserva %d: %v\n", reservas[i].ID, err)
 			}
+			continue
 		}
+		expiradas++
+	}
+
+	if expiradas > 0 {
+		s.inventarioLiberado()
 	}
 
-	return s.reservaRepo.UpdateReservaEstado(id, estado)
+	return expiradas, nil
 }
 
-// CancelarReserva cancela una reserva completa
-func (s *ReservaService) CancelarReserva(id int) error {
-	return s.UpdateReservaEstado(id, domain.ReservaCancelada)
+// PrevisualizarCancelacion calcula la penalidad y el reembolso que tendría la cancelación de la
+// reserva en este momento, sin cancelarla
+func (s *ReservaService) PrevisualizarCancelacion(id int) (*domain.PenalidadCancelacion, error) {
+	_, penalidad, err := s.calcularCancelacion(id)
+	return penalidad, err
+}
+
+// calcularCancelacion verifica que la reserva pueda cancelarse y calcula su penalidad
+func (s *ReservaService) calcularCancelacion(id int) (*domain.Reserva, *domain.PenalidadCancelacion, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := domain.ValidarTransicion(reserva.Estado, domain.ReservaCancelada); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.cargarHabitaciones(reserva); err != nil {
+		return nil, nil, err
+	}
+
+	penalidad, err := s.politicaService.CalcularPenalidad(reserva, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reserva, penalidad, nil
+}
+
+// nuevoCambioEstado arma la transición de la reserva desde su estado actual
+func nuevoCambioEstado(reserva *domain.Reserva, estado domain.EstadoReserva, actor string) *domain.CambioEstadoReserva {
+	return &domain.CambioEstadoReserva{
+		ReservaID: reserva.ID,
+		Desde:     reserva.Estado,
+		Hacia:     estado,
+		Actor:     actor,
+		Fecha:     time.Now(),
+	}
+}
+
+// cargarHabitaciones completa la información de tipo de las habitaciones de la reserva
+func (s *ReservaService) cargarHabitaciones(reserva *domain.Reserva) error {
+	for i := range reserva.Habitaciones {
+		habitacion, err := s.habitacionRepo.GetRoomByID(reserva.Habitaciones[i].HabitacionID)
+		if err != nil {
+			return err
+		}
+		reserva.Habitaciones[i].Habitacion = habitacion
+	}
+	return nil
+}
+
+// CancelarHabitacion cancela una sola habitación de la reserva aplicando su política de
+// cancelación y recalcula los montos con las habitaciones restantes. La penalidad se calcula sobre
+// lo que la reserva deja de cobrar por la habitación. Si era la última habitación activa y no
+// quedan habitaciones reservadas por tipo se cancela la reserva completa.
+func (s *ReservaService) CancelarHabitacion(reservaID, habitacionID int, actor string) (*domain.PenalidadCancelacion, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(reservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reserva.Estado.PuedeCambiarA(domain.ReservaCancelada) {
+		return nil, fmt.Errorf("no se puede cancelar habitaciones de una reserva en estado %s", reserva.Estado)
+	}
+
+	// La política y la promoción necesitan el tipo de cada habitación; sus precios se conservan
+	if err := s.cargarHabitaciones(reserva); err != nil {
+		return nil, err
+	}
+
+	restantes := make([]domain.ReservaHabitacion, 0, len(reserva.Habitaciones))
+	var canceladas []domain.ReservaHabitacion
+	for _, hab := range reserva.Habitaciones {
+		if hab.HabitacionID == habitacionID {
+			canceladas = append(canceladas, hab)
+		} else {
+			restantes = append(restantes, hab)
+		}
+	}
+
+	if len(canceladas) == 0 {
+		return nil, fmt.Errorf("la habitación %d no está activa en la reserva %d", habitacionID, reservaID)
+	}
+
+	if len(restantes) == 0 && len(reserva.TiposHabitacion) == 0 {
+		return s.CancelarReserva(reservaID, actor)
+	}
+
+	totalAnterior := reserva.Total
+	reserva.Habitaciones = restantes
+	if err := s.promocionService.RecalcularDescuento(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.calcularTotales(reserva); err != nil {
+		return nil, err
+	}
+
+	penalidad, err := s.politicaService.CalcularPenalidadParcial(reserva, canceladas, totalAnterior-reserva.Total, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reservaRepo.CancelarHabitacion(reserva, habitacionID, penalidad); err != nil {
+		return nil, err
+	}
+	s.inventarioLiberado()
+
+	return penalidad, nil
 }
 
 // ConfirmarReserva confirma una reserva pendiente y envía email de confirmación
-func (s *ReservaService) ConfirmarReserva(id int) error {
-	return s.confirmarReservaInternal(id, true) // true = enviar email
+func (s *ReservaService) ConfirmarReserva(id int, actor string) error {
+	return s.confirmarReservaInternal(id, actor, true) // true = enviar email
 }
 
 // ConfirmarReservaSinEmail confirma una reserva sin enviar email
-func (s *ReservaService) ConfirmarReservaSinEmail(id int) error {
-	return s.confirmarReservaInternal(id, false) // false = no enviar email
+func (s *ReservaService) ConfirmarReservaSinEmail(id int, actor string) error {
+	return s.confirmarReservaInternal(id, actor, false) // false = no enviar email
 }
 
 // confirmarReservaInternal es el método interno que maneja la confirmación
-func (s *ReservaService) confirmarReservaInternal(id int, enviarEmail bool) error {
+func (s *ReservaService) confirmarReservaInternal(id int, actor string, enviarEmail bool) error {
 	// Actualizar estado
-	if err := s.UpdateReservaEstado(id, domain.ReservaConfirmada); err != nil {
+	if err := s.UpdateReservaEstado(id, domain.ReservaConfirmada, actor); err != nil {
 		return err
 	}
 
@@ -200,45 +684,85 @@ func (s *ReservaService) confirmarReservaInternal(id int, enviarEmail bool) erro
 
 // enviarEmailConfirmacion envía el email de confirmación de la reserva
 func (s *ReservaService) enviarEmailConfirmacion(reserva *domain.Reserva) error {
-	// Preparar información de habitaciones
-	habitaciones := make([]email.HabitacionInfo, len(reserva.Habitaciones))
-	for i, hab := range reserva.Habitaciones {
+	reservaInfo, err := s.reservaInfoEmailConCancelacion(reserva)
+	if err != nil {
+		return err
+	}
+
+	return s.emailClient.SendReservaConfirmacion(reservaInfo)
+}
+
+// enviarEmailModificacion envía el email con el detalle actualizado de la reserva
+func (s *ReservaService) enviarEmailModificacion(reserva *domain.Reserva) error {
+	reservaInfo, err := s.reservaInfoEmailConCancelacion(reserva)
+	if err != nil {
+		return err
+	}
+
+	return s.emailClient.SendReservaModificada(reservaInfo)
+}
+
+// reservaInfoEmailConCancelacion prepara la información de la reserva para los correos con el
+// plazo de cancelación gratuita y las políticas que le aplican
+func (s *ReservaService) reservaInfoEmailConCancelacion(reserva *domain.Reserva) (email.ReservaInfo, error) {
+	reservaInfo, err := reservaInfoEmail(reserva)
+	if err != nil {
+		return reservaInfo, err
+	}
+
+	penalidad, err := s.politicaService.CalcularPenalidad(reserva, time.Now())
+	if err != nil {
+		return reservaInfo, err
+	}
+	reservaInfo.PoliticasCancelacion = penalidad.Politicas
+	reservaInfo.LimiteCancelacion = penalidad.LimiteGratuito.In(horario.Peru)
+
+	return reservaInfo, nil
+}
+
+// reservaInfoEmail prepara la información de la reserva para los correos
+func reservaInfoEmail(reserva *domain.Reserva) (email.ReservaInfo, error) {
+	// Preparar información de habitaciones, incluidas las reservadas por tipo aún sin asignar
+	unidades := reserva.Unidades()
+	habitaciones := make([]email.HabitacionInfo, len(unidades))
+	for i, hab := range unidades {
 		// Verificar que la habitación no sea nil
 		if hab.Habitacion == nil {
-			return fmt.Errorf("habitación %d no tiene datos completos", hab.HabitacionID)
+			return email.ReservaInfo{}, fmt.Errorf("habitación %d no tiene datos completos", hab.HabitacionID)
+		}
+
+		nombre, numero := hab.Habitacion.Nombre, hab.Habitacion.Numero
+		if hab.HabitacionID == 0 {
+			nombre, numero = hab.Habitacion.TipoHabitacion.Titulo, "Por asignar"
 		}
 
-		noches := int(hab.FechaSalida.Sub(hab.FechaEntrada).Hours() / 24)
 		habitaciones[i] = email.HabitacionInfo{
-			Nombre:       hab.Habitacion.Nombre,
-			Numero:       hab.Habitacion.Numero,
+			Nombre:       nombre,
+			Numero:       numero,
 			FechaEntrada: hab.FechaEntrada,
 			FechaSalida:  hab.FechaSalida,
 			Precio:       hab.Precio,
-			Noches:       noches,
+			Noches:       hab.Noches(),
+			Total:        hab.Importe(),
 		}
 	}
 
 	// Preparar información de la reserva
-	reservaInfo := email.ReservaInfo{
+	return email.ReservaInfo{
 		ID:                reserva.ID,
+		CodigoReserva:     reserva.CodigoReserva,
 		ClienteEmail:      reserva.ClienteID, // El clienteID es el email
 		CantidadAdultos:   reserva.CantidadAdultos,
 		CantidadNinhos:    reserva.CantidadNinhos,
 		FechaConfirmacion: reserva.FechaConfirmacion,
 		Subtotal:          reserva.Subtotal,
 		Descuento:         reserva.Descuento,
-		Total:             reserva.Subtotal - reserva.Descuento,
+		CargoServicio:     reserva.CargoServicio,
+		IGV:               reserva.IGV,
+		ExoneradoIGV:      reserva.ExoneradoIGV,
+		Total:             reserva.Total,
 		Habitaciones:      habitaciones,
-	}
-
-	// Enviar email
-	return s.emailClient.SendReservaConfirmacion(reservaInfo)
-}
-
-// CompletarReserva marca una reserva como completada
-func (s *ReservaService) CompletarReserva(id int) error {
-	return s.UpdateReservaEstado(id, domain.ReservaCompletada)
+	}, nil
 }
 
 // VerificarDisponibilidad verifica si una habitación está disponible
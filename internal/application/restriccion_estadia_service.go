@@ -0,0 +1,156 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// maxDiasRestriccion limita la cantidad de fechas que se pueden restringir en una sola operación
+const maxDiasRestriccion = 366
+
+// RestriccionEstadiaService administra y aplica las restricciones de estadía (estadía mínima y
+// máxima, cerrado a llegadas y a salidas)
+type RestriccionEstadiaService struct {
+	repo           domain.RestriccionEstadiaRepository
+	habitacionRepo domain.HabitacionRepository
+}
+
+// NewRestriccionEstadiaService crea una nueva instancia del servicio de restricciones de estadía
+func NewRestriccionEstadiaService(repo domain.RestriccionEstadiaRepository, habitacionRepo domain.HabitacionRepository) *RestriccionEstadiaService {
+	return &RestriccionEstadiaService{
+		repo:           repo,
+		habitacionRepo: habitacionRepo,
+	}
+}
+
+// RangoRestriccion es una misma restricción aplicada a cada fecha entre FechaInicio y FechaFin,
+// ambas inclusive
+type RangoRestriccion struct {
+	TipoHabitacionID *int // nil aplica a todos los tipos
+	FechaInicio      time.Time
+	FechaFin         time.Time
+	EstanciaMinima   int
+	EstanciaMaxima   int
+	CerradoLlegada   bool
+	CerradoSalida    bool
+	Motivo           string
+}
+
+// GetRestricciones obtiene las restricciones entre ambas fechas (inclusive). tipoHabitacionID 0
+// incluye todos los tipos.
+func (s *RestriccionEstadiaService) GetRestricciones(desde, hasta time.Time, tipoHabitacionID int) ([]domain.RestriccionEstadia, error) {
+	if hasta.Before(desde) {
+		return nil, fmt.Errorf("la fecha hasta debe ser posterior o igual a la fecha desde")
+	}
+	return s.repo.GetRestricciones(desde, hasta, tipoHabitacionID)
+}
+
+// GetRestriccionesPorFecha obtiene las restricciones del rango indexadas por fecha y tipo
+func (s *RestriccionEstadiaService) GetRestriccionesPorFecha(desde, hasta time.Time, tipoHabitacionID int) (domain.RestriccionesPorFecha, error) {
+	restricciones, err := s.repo.GetRestricciones(desde, hasta, tipoHabitacionID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NuevasRestriccionesPorFecha(restricciones), nil
+}
+
+// GuardarRango valida el rango y crea (o reemplaza) la restricción en cada una de sus fechas
+func (s *RestriccionEstadiaService) GuardarRango(rango RangoRestriccion) ([]domain.RestriccionEstadia, error) {
+	if err := s.validarRango(&rango); err != nil {
+		return nil, err
+	}
+
+	var restricciones []domain.RestriccionEstadia
+	for fecha := rango.FechaInicio; !fecha.After(rango.FechaFin); fecha = fecha.AddDate(0, 0, 1) {
+		restricciones = append(restricciones, domain.RestriccionEstadia{
+			TipoHabitacionID: rango.TipoHabitacionID,
+			Fecha:            fecha,
+			EstanciaMinima:   rango.EstanciaMinima,
+			EstanciaMaxima:   rango.EstanciaMaxima,
+			CerradoLlegada:   rango.CerradoLlegada,
+			CerradoSalida:    rango.CerradoSalida,
+			Motivo:           rango.Motivo,
+		})
+	}
+
+	if err := s.repo.GuardarRestricciones(restricciones); err != nil {
+		return nil, err
+	}
+	return restricciones, nil
+}
+
+// DeleteRestriccion elimina una restricción de estadía
+func (s *RestriccionEstadiaService) DeleteRestriccion(id int) error {
+	return s.repo.DeleteRestriccion(id)
+}
+
+// validarRango verifica las fechas, que el rango restrinja algo y que el tipo de habitación exista
+func (s *RestriccionEstadiaService) validarRango(rango *RangoRestriccion) error {
+	rango.Motivo = strings.TrimSpace(rango.Motivo)
+
+	if rango.FechaFin.Before(rango.FechaInicio) {
+		return fmt.Errorf("la fecha fin debe ser posterior o igual a la fecha inicio")
+	}
+	if rango.FechaFin.Sub(rango.FechaInicio) >= maxDiasRestriccion*24*time.Hour {
+		return fmt.Errorf("el rango de la restricción no puede superar %d días", maxDiasRestriccion)
+	}
+
+	if rango.EstanciaMinima < 0 || rango.EstanciaMaxima < 0 {
+		return fmt.Errorf("la estadía mínima y máxima no pueden ser negativas")
+	}
+	if rango.EstanciaMaxima > 0 && rango.EstanciaMaxima < rango.EstanciaMinima {
+		return fmt.Errorf("la estadía máxima no puede ser menor a la estadía mínima")
+	}
+	if rango.EstanciaMinima <= 1 && rango.EstanciaMaxima == 0 && !rango.CerradoLlegada && !rango.CerradoSalida {
+		return fmt.Errorf("la restricción debe indicar una estadía mínima o máxima, o cerrar llegadas o salidas")
+	}
+
+	if rango.TipoHabitacionID != nil {
+		if _, err := s.habitacionRepo.GetRoomTypeByID(*rango.TipoHabitacionID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidarEstadia verifica que una estadía del tipo de habitación cumpla las restricciones de su
+// fecha de llegada (cerrado a llegadas, estadía mínima y máxima) y de su fecha de salida (cerrado
+// a salidas). Retorna *domain.RestriccionEstadiaError con la regla incumplida.
+func (s *RestriccionEstadiaService) ValidarEstadia(tipo domain.TipoHabitacion, fechaEntrada, fechaSalida time.Time) error {
+	restricciones, err := s.GetRestriccionesPorFecha(fechaEntrada, fechaSalida, tipo.ID)
+	if err != nil {
+		return err
+	}
+
+	incumple := func(fecha time.Time, regla string, noches int) error {
+		return &domain.RestriccionEstadiaError{
+			TipoHabitacionID: tipo.ID,
+			TipoHabitacion:   tipo.Titulo,
+			Fecha:            fecha,
+			Regla:            regla,
+			Noches:           noches,
+		}
+	}
+
+	if llegada := restricciones.Para(tipo.ID, fechaEntrada); llegada != nil {
+		noches := int(fechaSalida.Sub(fechaEntrada).Hours() / 24)
+		switch {
+		case llegada.CerradoLlegada:
+			return incumple(fechaEntrada, domain.ReglaCerradoLlegada, 0)
+		case llegada.EstanciaMinima > 0 && noches < llegada.EstanciaMinima:
+			return incumple(fechaEntrada, domain.ReglaEstanciaMinima, llegada.EstanciaMinima)
+		case llegada.EstanciaMaxima > 0 && noches > llegada.EstanciaMaxima:
+			return incumple(fechaEntrada, domain.ReglaEstanciaMaxima, llegada.EstanciaMaxima)
+		}
+	}
+
+	if salida := restricciones.Para(tipo.ID, fechaSalida); salida != nil && salida.CerradoSalida {
+		return incumple(fechaSalida, domain.ReglaCerradoSalida, 0)
+	}
+
+	return nil
+}
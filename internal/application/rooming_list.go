@@ -0,0 +1,163 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// AsignarRoomingList convierte parte de las habitaciones reservadas por tipo en habitaciones
+// concretas con el nombre de su huésped. Cada línea de la rooming list toma una habitación del
+// tipo indicado, la elegida o, si no se indica, la libre que deja menos huecos en el calendario.
+// Las habitaciones del tipo que no figuran en la lista siguen por asignar.
+func (s *ReservaService) AsignarRoomingList(reservaID int, habitaciones []domain.HabitacionRooming) (*domain.Reserva, error) {
+	if len(habitaciones) == 0 {
+		return nil, fmt.Errorf("la rooming list debe tener al menos una habitación")
+	}
+
+	reserva, err := s.reservaRepo.GetReservaByID(reservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reserva.Estado != domain.ReservaPendiente && reserva.Estado != domain.ReservaConfirmada {
+		return nil, fmt.Errorf("no se pueden asignar habitaciones a una reserva en estado %s", reserva.Estado)
+	}
+
+	if reserva.Expirada(time.Now()) {
+		return nil, domain.ErrReservaExpirada
+	}
+
+	asignaciones, err := s.planificarRoomingList(reserva, habitaciones)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reservaRepo.AsignarRoomingList(reservaID, asignaciones); err != nil {
+		return nil, fmt.Errorf("error al asignar la rooming list: %w", err)
+	}
+
+	return s.reservaRepo.GetReservaByID(reservaID)
+}
+
+// planificarRoomingList ubica cada línea de la rooming list en una reserva por tipo pendiente de
+// su tipo y elige habitación para las que no la indican
+func (s *ReservaService) planificarRoomingList(reserva *domain.Reserva, habitaciones []domain.HabitacionRooming) ([]domain.AsignacionHabitacion, error) {
+	asignadas := make(map[int]int)
+	usadas := make(map[int]bool)
+	asignaciones := make([]domain.AsignacionHabitacion, 0, len(habitaciones))
+	automaticas := make(map[int][]int) // línea -> índices en asignaciones sin habitación
+
+	for _, hab := range habitaciones {
+		nombre := strings.TrimSpace(hab.NombreHuesped)
+		if nombre == "" {
+			return nil, fmt.Errorf("el nombre del huésped es requerido en cada habitación de la rooming list")
+		}
+
+		// La primera reserva por tipo del tipo que aún tenga habitaciones por asignar
+		var linea *domain.ReservaTipoHabitacion
+		for i := range reserva.TiposHabitacion {
+			tipo := &reserva.TiposHabitacion[i]
+			if tipo.TipoHabitacionID == hab.TipoHabitacionID && asignadas[tipo.ID] < tipo.Cantidad {
+				linea = tipo
+				break
+			}
+		}
+		if linea == nil {
+			return nil, fmt.Errorf("la reserva %d no tiene más habitaciones del tipo %d por asignar", reserva.ID, hab.TipoHabitacionID)
+		}
+		asignadas[linea.ID]++
+
+		if hab.HabitacionID != 0 {
+			if usadas[hab.HabitacionID] {
+				return nil, fmt.Errorf("la habitación %d está repetida en la rooming list", hab.HabitacionID)
+			}
+			usadas[hab.HabitacionID] = true
+		} else {
+			automaticas[linea.ID] = append(automaticas[linea.ID], len(asignaciones))
+		}
+
+		asignaciones = append(asignaciones, domain.AsignacionHabitacion{
+			TipoReservaID: linea.ID,
+			HabitacionID:  hab.HabitacionID,
+			NombreHuesped: nombre,
+		})
+	}
+
+	for _, tipo := range reserva.TiposHabitacion {
+		pendientes := automaticas[tipo.ID]
+		if len(pendientes) == 0 {
+			continue
+		}
+
+		candidatas, err := s.reservaHabitacionRepo.GetHabitacionesCandidatas(tipo.TipoHabitacionID, tipo.FechaEntrada, tipo.FechaSalida)
+		if err != nil {
+			return nil, err
+		}
+
+		elegidas := elegirHabitaciones(candidatas, tipo.FechaEntrada, tipo.FechaSalida, len(pendientes), usadas)
+		if len(elegidas) < len(pendientes) {
+			return nil, domain.ErrSinHabitacionAsignable
+		}
+
+		for i, habitacionID := range elegidas {
+			usadas[habitacionID] = true
+			asignaciones[pendientes[i]].HabitacionID = habitacionID
+		}
+	}
+
+	return asignaciones, nil
+}
+
+// LiberarHabitacionesPorAsignar devuelve al inventario las habitaciones reservadas por tipo que
+// aún no tienen habitación y recalcula los montos con las asignadas. Si la reserva no tiene
+// ninguna habitación asignada se cancela sin penalidad.
+func (s *ReservaService) LiberarHabitacionesPorAsignar(reservaID int, actor string) (*domain.Reserva, error) {
+	reserva, err := s.reservaRepo.GetReservaByID(reservaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reserva.TiposHabitacion) == 0 {
+		return reserva, nil
+	}
+
+	if reserva.Estado != domain.ReservaPendiente && reserva.Estado != domain.ReservaConfirmada {
+		return nil, fmt.Errorf("no se pueden liberar habitaciones de una reserva en estado %s", reserva.Estado)
+	}
+
+	if len(reserva.Habitaciones) == 0 {
+		cambio := nuevoCambioEstado(reserva, domain.ReservaCancelada, actor)
+		penalidad := &domain.PenalidadCancelacion{
+			ReservaID:           reserva.ID,
+			FechaCalculo:        cambio.Fecha,
+			CancelacionGratuita: true,
+		}
+		if err := s.reservaRepo.CancelarReserva(cambio, penalidad); err != nil {
+			return nil, err
+		}
+		return s.reservaRepo.GetReservaByID(reservaID)
+	}
+
+	// La promoción necesita el tipo de cada habitación asignada; sus precios se conservan
+	reserva.TiposHabitacion = nil
+	if err := s.cargarHabitaciones(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.promocionService.RecalcularDescuento(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.calcularTotales(reserva); err != nil {
+		return nil, err
+	}
+
+	if err := s.reservaRepo.LiberarTiposPorAsignar(reserva, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.reservaRepo.GetReservaByID(reservaID)
+}
@@ -0,0 +1,317 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+// toleranciaCotizacion es la diferencia relativa máxima aceptada entre el precio que cotizó
+// el cliente y el precio calculado por el servidor (1%)
+const toleranciaCotizacion = 0.01
+
+// TarifaService calcula el precio por noche de las habitaciones a partir del precio base
+// del tipo de habitación y de los planes de tarifa configurados
+type TarifaService struct {
+	repo           domain.TarifaRepository
+	habitacionRepo domain.HabitacionRepository
+}
+
+// NewTarifaService crea una nueva instancia del servicio de tarifas
+func NewTarifaService(repo domain.TarifaRepository, habitacionRepo domain.HabitacionRepository) *TarifaService {
+	return &TarifaService{
+		repo:           repo,
+		habitacionRepo: habitacionRepo,
+	}
+}
+
+// CotizarHabitacion calcula el precio de una habitación para el rango de fechas dado
+func (s *TarifaService) CotizarHabitacion(habitacionID int, fechaEntrada, fechaSalida time.Time) (*domain.Cotizacion, error) {
+	habitacion, err := s.habitacionRepo.GetRoomByID(habitacionID)
+	if err != nil {
+		return nil, err
+	}
+
+	cotizacion, err := s.CotizarTipo(habitacion.TipoHabitacion, fechaEntrada, fechaSalida)
+	if err != nil {
+		return nil, err
+	}
+
+	cotizacion.HabitacionID = habitacionID
+	return cotizacion, nil
+}
+
+// CotizarTipo calcula el precio de un tipo de habitación para el rango de fechas dado.
+// Cada noche parte del precio base y se le aplican, en orden, los planes de fin de semana
+// y de temporada vigentes, el plan por ocupación que corresponda y el precio mínimo y máximo
+// del tipo. Finalmente se aplica el mejor descuento por duración de estadía.
+func (s *TarifaService) CotizarTipo(tipo domain.TipoHabitacion, fechaEntrada, fechaSalida time.Time) (*domain.Cotizacion, error) {
+	if !fechaSalida.After(fechaEntrada) {
+		return nil, fmt.Errorf("la fecha de salida debe ser posterior a la fecha de entrada")
+	}
+
+	planes, err := s.repo.GetPlanesActivos(tipo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	noches, err := s.preciosNoche(tipo, planes, fechaEntrada, fechaSalida)
+	if err != nil {
+		return nil, err
+	}
+
+	// El descuento por estadía depende de la cantidad total de noches
+	if plan := mejorPlanEstadia(planes, fechaEntrada, len(noches)); plan != nil {
+		for i := range noches {
+			noches[i].Precio = redondear(noches[i].Precio * (1 + plan.Porcentaje/100))
+			noches[i].Planes = append(noches[i].Planes, plan.ID)
+		}
+	}
+
+	total := 0.0
+	for _, noche := range noches {
+		total += noche.Precio
+	}
+
+	return &domain.Cotizacion{
+		TipoHabitacionID: tipo.ID,
+		FechaEntrada:     fechaEntrada,
+		FechaSalida:      fechaSalida,
+		Noches:           noches,
+		PrecioPromedio:   redondear(total / float64(len(noches))),
+		Total:            redondear(total),
+	}, nil
+}
+
+// PreciosNoche calcula el precio de cada noche del rango [desde, hasta) para un tipo de habitación
+// con los planes de fin de semana, temporada y ocupación. No incluye descuentos por duración de
+// estadía, que dependen de la reserva completa.
+func (s *TarifaService) PreciosNoche(tipo domain.TipoHabitacion, desde, hasta time.Time) ([]domain.PrecioNoche, error) {
+	planes, err := s.repo.GetPlanesActivos(tipo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.preciosNoche(tipo, planes, desde, hasta)
+}
+
+// PrecioHoy calcula el precio de la noche de hoy para un tipo de habitación
+func (s *TarifaService) PrecioHoy(tipo domain.TipoHabitacion) (float64, error) {
+	hoy := horario.HoyPeru()
+	noches, err := s.PreciosNoche(tipo, hoy, hoy.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	return noches[0].Precio, nil
+}
+
+// preciosNoche calcula el precio de cada noche del rango [desde, hasta). La ocupación de cada
+// noche solo se consulta si hay planes por ocupación.
+func (s *TarifaService) preciosNoche(tipo domain.TipoHabitacion, planes []domain.PlanTarifa, desde, hasta time.Time) ([]domain.PrecioNoche, error) {
+	var ocupaciones map[string]float64
+	for _, plan := range planes {
+		if plan.Tipo == domain.PlanOcupacion {
+			var err error
+			if ocupaciones, err = s.ocupacionPorFecha(tipo.ID, desde, hasta); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	hoy := horario.HoyPeru()
+	var noches []domain.PrecioNoche
+	for fecha := desde; fecha.Before(hasta); fecha = fecha.AddDate(0, 0, 1) {
+		noche := precioNoche(tipo, planes, fecha)
+
+		if ocupacion, ok := ocupaciones[fecha.Format("2006-01-02")]; ok {
+			dias := int(math.Round(fecha.Sub(hoy).Hours() / 24))
+			if plan := planOcupacion(planes, fecha, ocupacion, dias); plan != nil {
+				noche.Precio = redondear(noche.Precio * (1 + plan.Porcentaje/100))
+				noche.Planes = append(noche.Planes, plan.ID)
+			}
+			noche.Ocupacion = &ocupacion
+		}
+
+		noche.Precio = redondear(tipo.LimitarPrecio(noche.Precio))
+		noches = append(noches, noche)
+	}
+	return noches, nil
+}
+
+// ocupacionPorFecha calcula el porcentaje de ocupación prevista del tipo de habitación en cada noche
+// del rango [desde, hasta): habitaciones reservadas sobre habitaciones vendibles (sin las bloqueadas
+// por mantenimiento). Un tipo sin habitaciones vendibles se considera completo.
+func (s *TarifaService) ocupacionPorFecha(tipoHabitacionID int, desde, hasta time.Time) (map[string]float64, error) {
+	disponibilidades, err := s.habitacionRepo.GetDisponibilidadFechas(desde, hasta.AddDate(0, 0, -1), tipoHabitacionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ocupaciones := make(map[string]float64, len(disponibilidades))
+	for _, d := range disponibilidades {
+		ocupacion := 100.0
+		if vendibles := d.Total - d.Bloqueadas; vendibles > 0 {
+			ocupacion = min(100, redondear(float64(d.Reservadas)*100/float64(vendibles)))
+		}
+		ocupaciones[d.Fecha.Format("2006-01-02")] = ocupacion
+	}
+	return ocupaciones, nil
+}
+
+// ValidarPrecioCotizado verifica que el precio por noche enviado por el cliente coincida con la
+// cotización calculada. Un precio cotizado de 0 indica que el cliente no envió cotización.
+func (s *TarifaService) ValidarPrecioCotizado(precioCotizado float64, cotizacion *domain.Cotizacion) error {
+	if precioCotizado == 0 {
+		return nil
+	}
+
+	if math.Abs(precioCotizado-cotizacion.PrecioPromedio) > cotizacion.PrecioPromedio*toleranciaCotizacion {
+		return &domain.PrecioNoCoincideError{
+			HabitacionID: cotizacion.HabitacionID,
+			Cotizado:     precioCotizado,
+			Calculado:    cotizacion.PrecioPromedio,
+		}
+	}
+
+	return nil
+}
+
+// GetPlanes obtiene todos los planes de tarifa
+func (s *TarifaService) GetPlanes() ([]domain.PlanTarifa, error) {
+	return s.repo.GetPlanes()
+}
+
+// CreatePlan valida y crea un nuevo plan de tarifa
+func (s *TarifaService) CreatePlan(plan *domain.PlanTarifa) error {
+	if err := validarPlanTarifa(plan); err != nil {
+		return err
+	}
+	return s.repo.CreatePlan(plan)
+}
+
+// UpdatePlan valida y actualiza un plan de tarifa
+func (s *TarifaService) UpdatePlan(plan *domain.PlanTarifa) error {
+	if err := validarPlanTarifa(plan); err != nil {
+		return err
+	}
+	return s.repo.UpdatePlan(plan)
+}
+
+// validarPlanTarifa verifica la consistencia de un plan según su tipo
+func validarPlanTarifa(plan *domain.PlanTarifa) error {
+	if plan.Nombre == "" {
+		return fmt.Errorf("el nombre del plan es requerido")
+	}
+
+	if plan.Porcentaje <= -100 {
+		return fmt.Errorf("el porcentaje debe ser mayor a -100")
+	}
+
+	if plan.FechaInicio != nil && plan.FechaFin != nil && plan.FechaFin.Before(*plan.FechaInicio) {
+		return fmt.Errorf("la fecha fin del plan debe ser posterior a la fecha inicio")
+	}
+
+	if plan.Tipo != domain.PlanOcupacion {
+		plan.OcupacionMin, plan.OcupacionMax, plan.DiasAnticipacion = nil, nil, nil
+	}
+
+	switch plan.Tipo {
+	case domain.PlanFinDeSemana:
+	case domain.PlanTemporada:
+		if plan.FechaInicio == nil || plan.FechaFin == nil {
+			return fmt.Errorf("los planes de temporada requieren fechaInicio y fechaFin")
+		}
+	case domain.PlanEstadia:
+		if plan.MinNoches < 2 {
+			return fmt.Errorf("los planes por estadía requieren minNoches de al menos 2")
+		}
+		if plan.Porcentaje >= 0 {
+			return fmt.Errorf("los planes por estadía deben tener un porcentaje de descuento negativo")
+		}
+	case domain.PlanOcupacion:
+		if plan.OcupacionMin == nil && plan.OcupacionMax == nil {
+			return fmt.Errorf("los planes por ocupación requieren ocupacionMin u ocupacionMax")
+		}
+		for _, ocupacion := range []*float64{plan.OcupacionMin, plan.OcupacionMax} {
+			if ocupacion != nil && (*ocupacion < 0 || *ocupacion > 100) {
+				return fmt.Errorf("la ocupación debe estar entre 0 y 100")
+			}
+		}
+		if plan.OcupacionMin != nil && plan.OcupacionMax != nil && *plan.OcupacionMax <= *plan.OcupacionMin {
+			return fmt.Errorf("ocupacionMax debe ser mayor a ocupacionMin")
+		}
+		if plan.DiasAnticipacion != nil && *plan.DiasAnticipacion < 0 {
+			return fmt.Errorf("diasAnticipacion no puede ser negativo")
+		}
+	default:
+		return fmt.Errorf("tipo de plan inválido: %s", plan.Tipo)
+	}
+
+	return nil
+}
+
+// precioNoche calcula el precio de una noche aplicando los planes de fin de semana y temporada
+func precioNoche(tipo domain.TipoHabitacion, planes []domain.PlanTarifa, fecha time.Time) domain.PrecioNoche {
+	noche := domain.PrecioNoche{
+		Fecha:      fecha,
+		PrecioBase: tipo.Precio,
+		Precio:     tipo.Precio,
+	}
+
+	finDeSemana := fecha.Weekday() == time.Friday || fecha.Weekday() == time.Saturday
+
+	for _, plan := range planes {
+		if !plan.Vigente(fecha) {
+			continue
+		}
+
+		aplica := (plan.Tipo == domain.PlanFinDeSemana && finDeSemana) || plan.Tipo == domain.PlanTemporada
+		if !aplica {
+			continue
+		}
+
+		noche.Precio = noche.Precio * (1 + plan.Porcentaje/100)
+		noche.Planes = append(noche.Planes, plan.ID)
+	}
+
+	noche.Precio = redondear(noche.Precio)
+	return noche
+}
+
+// planOcupacion retorna el plan por ocupación vigente que aplica a la noche con el mayor ajuste
+// (en valor absoluto), de modo que los planes con rangos superpuestos no se acumulen
+func planOcupacion(planes []domain.PlanTarifa, fecha time.Time, ocupacion float64, diasAnticipacion int) *domain.PlanTarifa {
+	var elegido *domain.PlanTarifa
+	for i, plan := range planes {
+		if plan.Tipo != domain.PlanOcupacion || !plan.Vigente(fecha) || !plan.AplicaOcupacion(ocupacion, diasAnticipacion) {
+			continue
+		}
+		if elegido == nil || math.Abs(plan.Porcentaje) > math.Abs(elegido.Porcentaje) {
+			elegido = &planes[i]
+		}
+	}
+	return elegido
+}
+
+// mejorPlanEstadia retorna el plan por estadía con mayor descuento que aplique a la cantidad de noches
+func mejorPlanEstadia(planes []domain.PlanTarifa, fechaEntrada time.Time, noches int) *domain.PlanTarifa {
+	var mejor *domain.PlanTarifa
+	for i, plan := range planes {
+		if plan.Tipo != domain.PlanEstadia || noches < plan.MinNoches || !plan.Vigente(fechaEntrada) {
+			continue
+		}
+		if mejor == nil || plan.Porcentaje < mejor.Porcentaje {
+			mejor = &planes[i]
+		}
+	}
+	return mejor
+}
+
+// redondear redondea un monto a dos decimales
+func redondear(monto float64) float64 {
+	return math.Round(monto*100) / 100
+}
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// tarifaRepoFalso retorna siempre los mismos planes activos
+type tarifaRepoFalso struct {
+	domain.TarifaRepository
+	planes []domain.PlanTarifa
+}
+
+func (r *tarifaRepoFalso) GetPlanesActivos(int) ([]domain.PlanTarifa, error) {
+	return r.planes, nil
+}
+
+func diaPrueba(dia string) time.Time {
+	f, err := time.Parse("2006-01-02", dia)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func ptrDiaPrueba(dia string) *time.Time {
+	f := diaPrueba(dia)
+	return &f
+}
+
+func ptrFloat(v float64) *float64 {
+	return &v
+}
+
+func TestCotizarTipo(t *testing.T) {
+	tipo := domain.TipoHabitacion{ID: 1, Precio: 100}
+
+	finDeSemana := domain.PlanTarifa{ID: 1, Tipo: domain.PlanFinDeSemana, Porcentaje: 20}
+	temporada := domain.PlanTarifa{
+		ID:          2,
+		Tipo:        domain.PlanTemporada,
+		Porcentaje:  10,
+		FechaInicio: ptrDiaPrueba("2026-10-18"),
+		FechaFin:    ptrDiaPrueba("2026-10-31"),
+	}
+	estadiaCorta := domain.PlanTarifa{ID: 3, Tipo: domain.PlanEstadia, Porcentaje: -5, MinNoches: 3}
+	estadiaLarga := domain.PlanTarifa{ID: 4, Tipo: domain.PlanEstadia, Porcentaje: -10, MinNoches: 4}
+
+	tests := []struct {
+		nombre  string
+		tipo    domain.TipoHabitacion
+		planes  []domain.PlanTarifa
+		entrada string
+		salida  string
+		precios []float64
+		total   float64
+	}{
+		{
+			nombre:  "sin planes usa el precio base",
+			tipo:    tipo,
+			entrada: "2026-10-14",
+			salida:  "2026-10-16",
+			precios: []float64{100, 100},
+			total:   200,
+		},
+		{
+			nombre:  "fin de semana solo viernes y sábado",
+			tipo:    tipo,
+			planes:  []domain.PlanTarifa{finDeSemana},
+			entrada: "2026-10-15",
+			salida:  "2026-10-19",
+			precios: []float64{100, 120, 120, 100},
+			total:   440,
+		},
+		{
+			nombre:  "temporada se acumula con fin de semana",
+			tipo:    tipo,
+			planes:  []domain.PlanTarifa{finDeSemana, temporada},
+			entrada: "2026-10-17",
+			salida:  "2026-10-19",
+			precios: []float64{120, 110},
+			total:   230,
+		},
+		{
+			nombre:  "estadía aplica solo el mayor descuento que alcanza",
+			tipo:    tipo,
+			planes:  []domain.PlanTarifa{estadiaCorta, estadiaLarga},
+			entrada: "2026-10-12",
+			salida:  "2026-10-16",
+			precios: []float64{90, 90, 90, 90},
+			total:   360,
+		},
+		{
+			nombre:  "estadía sin noches suficientes no descuenta",
+			tipo:    tipo,
+			planes:  []domain.PlanTarifa{estadiaCorta},
+			entrada: "2026-10-12",
+			salida:  "2026-10-14",
+			precios: []float64{100, 100},
+			total:   200,
+		},
+		{
+			nombre: "precio máximo del tipo limita el recargo",
+			tipo: domain.TipoHabitacion{
+				ID:           1,
+				Precio:       100,
+				PrecioMaximo: ptrFloat(110),
+			},
+			planes:  []domain.PlanTarifa{finDeSemana},
+			entrada: "2026-10-16",
+			salida:  "2026-10-17",
+			precios: []float64{110},
+			total:   110,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			service := NewTarifaService(&tarifaRepoFalso{planes: tt.planes}, nil)
+
+			cotizacion, err := service.CotizarTipo(tt.tipo, diaPrueba(tt.entrada), diaPrueba(tt.salida))
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+
+			if len(cotizacion.Noches) != len(tt.precios) {
+				t.Fatalf("se cotizaron %d noches, se esperaban %d", len(cotizacion.Noches), len(tt.precios))
+			}
+			for i, noche := range cotizacion.Noches {
+				if noche.Precio != tt.precios[i] {
+					t.Errorf("noche %s: precio %.2f, se esperaba %.2f", noche.Fecha.Format("2006-01-02"), noche.Precio, tt.precios[i])
+				}
+			}
+			if cotizacion.Total != tt.total {
+				t.Errorf("total %.2f, se esperaba %.2f", cotizacion.Total, tt.total)
+			}
+		})
+	}
+}
+
+func TestCotizarTipoFechasInvalidas(t *testing.T) {
+	service := NewTarifaService(&tarifaRepoFalso{}, nil)
+
+	if _, err := service.CotizarTipo(domain.TipoHabitacion{Precio: 100}, diaPrueba("2026-10-16"), diaPrueba("2026-10-16")); err == nil {
+		t.Fatal("se esperaba un error con la salida igual a la entrada")
+	}
+}
+
+func TestValidarPrecioCotizado(t *testing.T) {
+	service := NewTarifaService(&tarifaRepoFalso{}, nil)
+	cotizacion := &domain.Cotizacion{HabitacionID: 7, PrecioPromedio: 200}
+
+	tests := []struct {
+		nombre    string
+		cotizado  float64
+		rechazado bool
+	}{
+		{nombre: "sin cotización", cotizado: 0},
+		{nombre: "precio exacto", cotizado: 200},
+		{nombre: "dentro de la tolerancia", cotizado: 198.5},
+		{nombre: "fuera de la tolerancia", cotizado: 150, rechazado: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			err := service.ValidarPrecioCotizado(tt.cotizado, cotizacion)
+
+			var noCoincide *domain.PrecioNoCoincideError
+			if rechazado := errors.As(err, &noCoincide); rechazado != tt.rechazado {
+				t.Fatalf("error %v, se esperaba rechazo: %v", err, tt.rechazado)
+			}
+			if tt.rechazado && noCoincide.Calculado != 200 {
+				t.Errorf("precio calculado %.2f, se esperaba 200", noCoincide.Calculado)
+			}
+		})
+	}
+}
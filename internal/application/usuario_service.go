@@ -0,0 +1,178 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/auth"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// longitudMinimaPassword es la longitud mínima de la contraseña de un usuario del personal
+const longitudMinimaPassword = 10
+
+// SesionUsuario es la sesión emitida al usuario del personal que inició sesión
+type SesionUsuario struct {
+	Token    string          `json:"token"`
+	ExpiraEn time.Time       `json:"expiraEn"`
+	Usuario  *domain.Usuario `json:"usuario"`
+}
+
+// UsuarioService administra los usuarios del personal y su inicio de sesión con contraseña.
+// Sin firmador no se pueden iniciar sesiones.
+type UsuarioService struct {
+	repo     domain.UsuarioRepository
+	firmador *auth.Firmador
+	// hashSinUsuario se verifica cuando el email no existe para que la respuesta tarde lo mismo
+	hashSinUsuario func() string
+}
+
+// NewUsuarioService crea una nueva instancia del servicio de usuarios
+func NewUsuarioService(repo domain.UsuarioRepository, firmador *auth.Firmador) *UsuarioService {
+	return &UsuarioService{
+		repo:     repo,
+		firmador: firmador,
+		hashSinUsuario: sync.OnceValue(func() string {
+			hash, _ := auth.HashPassword("sin-usuario")
+			return hash
+		}),
+	}
+}
+
+// Login verifica el email y la contraseña del usuario y emite su token de sesión. Retorna
+// domain.ErrCredencialesInvalidas si no coinciden o el usuario está inactivo.
+func (s *UsuarioService) Login(email, password string) (*SesionUsuario, error) {
+	if s.firmador == nil {
+		return nil, ErrAccesoDeshabilitado
+	}
+
+	usuario, err := s.repo.GetUsuarioByEmail(email)
+	if errors.Is(err, domain.ErrUsuarioNoEncontrado) {
+		auth.VerificarPassword(s.hashSinUsuario(), password)
+		return nil, domain.ErrCredencialesInvalidas
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.VerificarPassword(usuario.HashPassword, password) || !usuario.Activo {
+		return nil, domain.ErrCredencialesInvalidas
+	}
+
+	ahora := time.Now()
+	token, expiraEn, err := s.firmador.Firmar(auth.Claims{
+		Sujeto:  strconv.Itoa(usuario.ID),
+		Email:   usuario.Email,
+		Rol:     usuario.Rol,
+		Version: usuario.VersionSesion,
+	}, ahora)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RegistrarAcceso(usuario.ID, ahora); err != nil {
+		// Log error pero no fallar, la sesión ya es válida
+		fmt.Printf("Error al registrar acceso del usuario %d: %v\n", usuario.ID, err)
+	}
+	usuario.UltimoAcceso = &ahora
+
+	return &SesionUsuario{
+		Token:    token,
+		ExpiraEn: expiraEn,
+		Usuario:  usuario,
+	}, nil
+}
+
+// ValidarSesion verifica que el usuario de una sesión del personal siga activo y que el token no
+// sea anterior a un cambio de su rol, estado o contraseña. Retorna el usuario con su rol actual,
+// o domain.ErrSesionRevocada.
+func (s *UsuarioService) ValidarSesion(sesion *auth.Claims) (*domain.Usuario, error) {
+	id, err := strconv.Atoi(sesion.Sujeto)
+	if err != nil {
+		return nil, domain.ErrSesionRevocada
+	}
+
+	usuario, err := s.repo.GetUsuarioByID(id)
+	if errors.Is(err, domain.ErrUsuarioNoEncontrado) {
+		return nil, domain.ErrSesionRevocada
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !usuario.Activo || usuario.VersionSesion != sesion.Version {
+		return nil, domain.ErrSesionRevocada
+	}
+
+	return usuario, nil
+}
+
+// GetUsuarios lista los usuarios del personal
+func (s *UsuarioService) GetUsuarios() ([]domain.Usuario, error) {
+	return s.repo.GetUsuarios()
+}
+
+// CreateUsuario registra un usuario del personal con su contraseña
+func (s *UsuarioService) CreateUsuario(usuario *domain.Usuario, password string) error {
+	usuario.Email = strings.ToLower(strings.TrimSpace(usuario.Email))
+	if !strings.Contains(usuario.Email, "@") {
+		return fmt.Errorf("el email es requerido")
+	}
+
+	if err := validarUsuario(usuario); err != nil {
+		return err
+	}
+
+	hash, err := hashPasswordUsuario(password)
+	if err != nil {
+		return err
+	}
+	usuario.HashPassword = hash
+	usuario.Activo = true
+
+	return s.repo.CreateUsuario(usuario)
+}
+
+// UpdateUsuario actualiza el nombre, el rol y si el usuario está activo
+func (s *UsuarioService) UpdateUsuario(usuario *domain.Usuario) error {
+	if err := validarUsuario(usuario); err != nil {
+		return err
+	}
+	return s.repo.UpdateUsuario(usuario)
+}
+
+// CambiarPassword reemplaza la contraseña del usuario
+func (s *UsuarioService) CambiarPassword(id int, password string) error {
+	hash, err := hashPasswordUsuario(password)
+	if err != nil {
+		return err
+	}
+	return s.repo.CambiarPassword(id, hash)
+}
+
+// validarUsuario normaliza y verifica el nombre y el rol del usuario
+func validarUsuario(usuario *domain.Usuario) error {
+	usuario.Nombre = strings.TrimSpace(usuario.Nombre)
+	if usuario.Nombre == "" {
+		return fmt.Errorf("el nombre es requerido")
+	}
+
+	switch usuario.Rol {
+	case domain.RolAdmin, domain.RolRecepcion, domain.RolRevenue, domain.RolMarketing:
+		return nil
+	default:
+		return fmt.Errorf("rol inválido: %s", usuario.Rol)
+	}
+}
+
+// hashPasswordUsuario verifica la longitud de la contraseña y retorna su hash
+func hashPasswordUsuario(password string) (string, error) {
+	if len([]rune(password)) < longitudMinimaPassword {
+		return "", fmt.Errorf("la contraseña debe tener al menos %d caracteres", longitudMinimaPassword)
+	}
+	return auth.HashPassword(password)
+}
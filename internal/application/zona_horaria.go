@@ -0,0 +1,15 @@
+package application
+
+import (
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/horario"
+)
+
+// horaCheckIn es la hora de check-in del hotel (hora de Perú)
+const horaCheckIn = 15
+
+// momentoCheckIn retorna el instante del check-in para la fecha de entrada dada
+func momentoCheckIn(fechaEntrada time.Time) time.Time {
+	return time.Date(fechaEntrada.Year(), fechaEntrada.Month(), fechaEntrada.Day(), horaCheckIn, 0, 0, 0, horario.Peru)
+}
@@ -0,0 +1,97 @@
+// Package auth firma y verifica los tokens de sesión (JWT firmados con HS256)
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTokenInvalido indica un token mal formado, con firma incorrecta o vencido
+var ErrTokenInvalido = errors.New("token de sesión inválido o vencido")
+
+// encabezado es el único encabezado aceptado; fijarlo evita la confusión de algoritmos
+var encabezado = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims son los datos que lleva el token de sesión
+type Claims struct {
+	Sujeto string `json:"sub"`
+	Email  string `json:"email,omitempty"`
+	Rol    string `json:"rol"`
+	// Version es la versión de las sesiones del usuario del personal al emitirse el token; un
+	// token con una versión anterior a la actual está revocado
+	Version   int   `json:"ver,omitempty"`
+	EmitidoEn int64 `json:"iat"`
+	ExpiraEn  int64 `json:"exp"`
+}
+
+// Firmador emite y verifica tokens de sesión con una clave secreta
+type Firmador struct {
+	clave    []byte
+	duracion time.Duration
+}
+
+// NewFirmador crea un firmador cuyos tokens duran duracion
+func NewFirmador(clave string, duracion time.Duration) *Firmador {
+	return &Firmador{
+		clave:    []byte(clave),
+		duracion: duracion,
+	}
+}
+
+// Firmar emite un token con los claims dados, completando su emisión y vencimiento. Retorna el
+// token y su vencimiento.
+func (f *Firmador) Firmar(claims Claims, ahora time.Time) (string, time.Time, error) {
+	expiraEn := ahora.Add(f.duracion)
+	claims.EmitidoEn = ahora.Unix()
+	claims.ExpiraEn = expiraEn.Unix()
+	datos, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error al generar token de sesión: %w", err)
+	}
+
+	contenido := encabezado + "." + base64.RawURLEncoding.EncodeToString(datos)
+	return contenido + "." + f.firma(contenido), expiraEn, nil
+}
+
+// Verificar comprueba la firma y el vencimiento del token y retorna sus claims
+func (f *Firmador) Verificar(token string, ahora time.Time) (*Claims, error) {
+	partes := strings.Split(token, ".")
+	if len(partes) != 3 || partes[0] != encabezado {
+		return nil, ErrTokenInvalido
+	}
+
+	contenido := partes[0] + "." + partes[1]
+	if subtle.ConstantTimeCompare([]byte(partes[2]), []byte(f.firma(contenido))) != 1 {
+		return nil, ErrTokenInvalido
+	}
+
+	datos, err := base64.RawURLEncoding.DecodeString(partes[1])
+	if err != nil {
+		return nil, ErrTokenInvalido
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(datos, &claims); err != nil || claims.Sujeto == "" || claims.Rol == "" {
+		return nil, ErrTokenInvalido
+	}
+
+	if ahora.Unix() >= claims.ExpiraEn {
+		return nil, ErrTokenInvalido
+	}
+
+	return &claims, nil
+}
+
+// firma calcula la firma HMAC-SHA256 del contenido codificada en base64url
+func (f *Firmador) firma(contenido string) string {
+	mac := hmac.New(sha256.New, f.clave)
+	mac.Write([]byte(contenido))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const claveDePrueba = "clave-de-prueba-de-al-menos-32-caracteres"
+
+func TestFirmarYVerificar(t *testing.T) {
+	firmador := NewFirmador(claveDePrueba, time.Hour)
+	ahora := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	token, expiraEn, err := firmador.Firmar(Claims{Sujeto: "42", Email: "ana@example.com", Rol: "cliente"}, ahora)
+	if err != nil {
+		t.Fatalf("error al firmar: %v", err)
+	}
+	if !expiraEn.Equal(ahora.Add(time.Hour)) {
+		t.Errorf("vencimiento %v, se esperaba %v", expiraEn, ahora.Add(time.Hour))
+	}
+
+	claims, err := firmador.Verificar(token, ahora.Add(59*time.Minute))
+	if err != nil {
+		t.Fatalf("error al verificar: %v", err)
+	}
+	if claims.Sujeto != "42" || claims.Email != "ana@example.com" || claims.Rol != "cliente" {
+		t.Errorf("claims inesperados: %+v", claims)
+	}
+	if claims.EmitidoEn != ahora.Unix() || claims.ExpiraEn != expiraEn.Unix() {
+		t.Errorf("emisión %d y vencimiento %d inesperados", claims.EmitidoEn, claims.ExpiraEn)
+	}
+}
+
+func TestVerificarRechazaTokens(t *testing.T) {
+	firmador := NewFirmador(claveDePrueba, time.Hour)
+	ahora := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	token, _, err := firmador.Firmar(Claims{Sujeto: "1", Rol: "admin", Version: 3}, ahora)
+	if err != nil {
+		t.Fatalf("error al firmar: %v", err)
+	}
+	partes := strings.Split(token, ".")
+
+	otroFirmador := NewFirmador("otra-clave-de-prueba-de-32-caracteres", time.Hour)
+	tokenAjeno, _, _ := otroFirmador.Firmar(Claims{Sujeto: "1", Rol: "admin"}, ahora)
+
+	// Mismo contenido con el rol cambiado, conservando la firma original
+	datos, _ := base64.RawURLEncoding.DecodeString(partes[1])
+	alterado := strings.Replace(string(datos), `"rol":"admin"`, `"rol":"cliente"`, 1)
+	tokenAlterado := partes[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(alterado)) + "." + partes[2]
+
+	sinFirma := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + partes[1] + "."
+
+	vacio := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":9999999999}`))
+	contenidoVacio := partes[0] + "." + vacio
+	sinSujeto := contenidoVacio + "." + firmador.firma(contenidoVacio)
+
+	tests := []struct {
+		nombre string
+		token  string
+		ahora  time.Time
+	}{
+		{nombre: "vencido", token: token, ahora: ahora.Add(time.Hour)},
+		{nombre: "firmado con otra clave", token: tokenAjeno, ahora: ahora},
+		{nombre: "contenido alterado", token: tokenAlterado, ahora: ahora},
+		{nombre: "algoritmo none", token: sinFirma, ahora: ahora},
+		{nombre: "sin sujeto ni rol", token: sinSujeto, ahora: ahora},
+		{nombre: "mal formado", token: "no-es-un-token", ahora: ahora},
+		{nombre: "vacío", token: "", ahora: ahora},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			if _, err := firmador.Verificar(tt.token, tt.ahora); !errors.Is(err, ErrTokenInvalido) {
+				t.Fatalf("se esperaba ErrTokenInvalido, se obtuvo %v", err)
+			}
+		})
+	}
+}
+
+func TestVerificarConservaVersion(t *testing.T) {
+	firmador := NewFirmador(claveDePrueba, time.Hour)
+	ahora := time.Now()
+
+	token, _, err := firmador.Firmar(Claims{Sujeto: "7", Rol: "recepcion", Version: 4}, ahora)
+	if err != nil {
+		t.Fatalf("error al firmar: %v", err)
+	}
+
+	claims, err := firmador.Verificar(token, ahora)
+	if err != nil {
+		t.Fatalf("error al verificar: %v", err)
+	}
+	if claims.Version != 4 {
+		t.Errorf("versión %d, se esperaba 4", claims.Version)
+	}
+}
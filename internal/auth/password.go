@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// iteracionesPassword es el costo de PBKDF2-SHA256 para las contraseñas nuevas
+const iteracionesPassword = 600000
+
+// HashPassword deriva el hash de la contraseña con PBKDF2-SHA256 y una sal aleatoria. El resultado
+// incluye el algoritmo, las iteraciones y la sal: pbkdf2-sha256$iteraciones$sal$hash
+func HashPassword(password string) (string, error) {
+	sal := make([]byte, 16)
+	if _, err := rand.Read(sal); err != nil {
+		return "", fmt.Errorf("error al generar sal: %w", err)
+	}
+
+	clave, err := pbkdf2.Key(sha256.New, password, sal, iteracionesPassword, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("error al derivar contraseña: %w", err)
+	}
+
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+		iteracionesPassword,
+		base64.RawStdEncoding.EncodeToString(sal),
+		base64.RawStdEncoding.EncodeToString(clave),
+	), nil
+}
+
+// VerificarPassword indica si la contraseña corresponde al hash generado por HashPassword
+func VerificarPassword(hash, password string) bool {
+	partes := strings.Split(hash, "$")
+	if len(partes) != 4 || partes[0] != "pbkdf2-sha256" {
+		return false
+	}
+
+	iteraciones, err := strconv.Atoi(partes[1])
+	if err != nil || iteraciones < 1 {
+		return false
+	}
+	sal, err := base64.RawStdEncoding.DecodeString(partes[2])
+	if err != nil {
+		return false
+	}
+	esperada, err := base64.RawStdEncoding.DecodeString(partes[3])
+	if err != nil {
+		return false
+	}
+
+	clave, err := pbkdf2.Key(sha256.New, password, sal, iteraciones, len(esperada))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(clave, esperada) == 1
+}
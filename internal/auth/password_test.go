@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPasswordYVerificar(t *testing.T) {
+	hash, err := HashPassword("contraseña-segura")
+	if err != nil {
+		t.Fatalf("error al generar hash: %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$600000$") {
+		t.Errorf("formato de hash inesperado: %s", hash)
+	}
+	if strings.Contains(hash, "contraseña-segura") {
+		t.Error("el hash no debe contener la contraseña")
+	}
+
+	if !VerificarPassword(hash, "contraseña-segura") {
+		t.Error("la contraseña correcta debería verificarse")
+	}
+	if VerificarPassword(hash, "contraseña-incorrecta") {
+		t.Error("una contraseña incorrecta no debería verificarse")
+	}
+}
+
+func TestHashPasswordUsaSalAleatoria(t *testing.T) {
+	primero, err := HashPassword("misma-contraseña")
+	if err != nil {
+		t.Fatalf("error al generar hash: %v", err)
+	}
+	segundo, err := HashPassword("misma-contraseña")
+	if err != nil {
+		t.Fatalf("error al generar hash: %v", err)
+	}
+
+	if primero == segundo {
+		t.Error("dos hashes de la misma contraseña no deberían coincidir")
+	}
+}
+
+func TestVerificarPasswordHashInvalido(t *testing.T) {
+	hashes := []string{
+		"",
+		"texto-plano",
+		"bcrypt$10$c2Fs$aGFzaA",
+		"pbkdf2-sha256$0$c2Fs$aGFzaA",
+		"pbkdf2-sha256$abc$c2Fs$aGFzaA",
+		"pbkdf2-sha256$1000$sal inválida$aGFzaA",
+		"pbkdf2-sha256$1000$c2Fs",
+	}
+
+	for _, hash := range hashes {
+		if VerificarPassword(hash, "cualquiera") {
+			t.Errorf("el hash %q no debería verificar ninguna contraseña", hash)
+		}
+	}
+}
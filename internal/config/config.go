@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -23,6 +24,30 @@ type Config struct {
 	SMTPFromName  string
 	SMTPFromEmail string
 	HotelLocation string `env:"HOTEL_LOCATION" json:"hotel_location"`
+	// ServiceChargePercent es el porcentaje de cargo por servicio aplicado a las reservas (0 lo desactiva)
+	ServiceChargePercent float64
+	// HoldMinutes es el tiempo que una reserva pendiente retiene sus habitaciones
+	HoldMinutes int
+	// AdminAPIKey da acceso de administrador a las rutas del personal con el header X-Admin-Key,
+	// por ejemplo para crear el primer usuario (vacía lo deshabilita)
+	AdminAPIKey string
+	// NoShowCutoffHour es la hora (0-23, hora de Perú) del día de llegada a partir de la cual una
+	// reserva confirmada sin check-in se marca como no presentada
+	NoShowCutoffHour int
+	// WaitlistOfferMinutes es el tiempo que se retiene la reserva ofrecida a la lista de espera
+	WaitlistOfferMinutes int
+	// FrontendURL es la dirección del sitio del hotel usada en los enlaces de los correos
+	FrontendURL string
+	// JWTSecret firma los tokens de sesión de los clientes y del personal (vacía deshabilita el
+	// inicio de sesión)
+	JWTSecret string
+	// SessionHours es la duración de una sesión
+	SessionHours int
+	// LoginCodeMinutes es la vigencia del código de acceso enviado por email
+	LoginCodeMinutes int
+	// IdempotencyTTLHours es el tiempo que se guarda la respuesta de una solicitud con
+	// Idempotency-Key para repetirla en los reintentos
+	IdempotencyTTLHours int
 }
 
 func LoadConfig() (*Config, error) {
@@ -46,6 +71,9 @@ func LoadConfig() (*Config, error) {
 		SMTPFromName:  getEnv("SMTP_FROM_NAME", "Hotel Reservas"),
 		SMTPFromEmail: getEnv("SMTP_FROM_EMAIL", ""),
 		HotelLocation: getEnv("HOTEL_LOCATION", ""),
+		AdminAPIKey:   getEnv("ADMIN_API_KEY", ""),
+		FrontendURL:   getEnv("FRONTEND_URL", "http://localhost:3000"),
+		JWTSecret:     getEnv("JWT_SECRET", ""),
 	}
 
 	// Validar que las variables requeridas no estén vacías
@@ -53,6 +81,52 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("DB_PASSWORD is required")
 	}
 
+	serviceCharge, err := strconv.ParseFloat(getEnv("SERVICE_CHARGE_PERCENT", "0"), 64)
+	if err != nil || serviceCharge < 0 {
+		return nil, fmt.Errorf("SERVICE_CHARGE_PERCENT must be a non-negative number")
+	}
+	config.ServiceChargePercent = serviceCharge
+
+	holdMinutes, err := strconv.Atoi(getEnv("HOLD_MINUTES", "15"))
+	if err != nil || holdMinutes <= 0 {
+		return nil, fmt.Errorf("HOLD_MINUTES must be a positive integer")
+	}
+	config.HoldMinutes = holdMinutes
+
+	noShowCutoff, err := strconv.Atoi(getEnv("NO_SHOW_CUTOFF_HOUR", "23"))
+	if err != nil || noShowCutoff < 0 || noShowCutoff > 23 {
+		return nil, fmt.Errorf("NO_SHOW_CUTOFF_HOUR must be an hour between 0 and 23")
+	}
+	config.NoShowCutoffHour = noShowCutoff
+
+	waitlistOffer, err := strconv.Atoi(getEnv("WAITLIST_OFFER_MINUTES", "120"))
+	if err != nil || waitlistOffer <= 0 {
+		return nil, fmt.Errorf("WAITLIST_OFFER_MINUTES must be a positive number of minutes")
+	}
+	config.WaitlistOfferMinutes = waitlistOffer
+
+	if config.JWTSecret != "" && len(config.JWTSecret) < 32 {
+		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters long")
+	}
+
+	sessionHours, err := strconv.Atoi(getEnv("SESSION_HOURS", "24"))
+	if err != nil || sessionHours <= 0 {
+		return nil, fmt.Errorf("SESSION_HOURS must be a positive number of hours")
+	}
+	config.SessionHours = sessionHours
+
+	loginCode, err := strconv.Atoi(getEnv("LOGIN_CODE_MINUTES", "15"))
+	if err != nil || loginCode <= 0 {
+		return nil, fmt.Errorf("LOGIN_CODE_MINUTES must be a positive number of minutes")
+	}
+	config.LoginCodeMinutes = loginCode
+
+	idempotencyTTL, err := strconv.Atoi(getEnv("IDEMPOTENCY_TTL_HOURS", "24"))
+	if err != nil || idempotencyTTL <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_TTL_HOURS must be a positive number of hours")
+	}
+	config.IdempotencyTTLHours = idempotencyTTL
+
 	return config, nil
 }
 
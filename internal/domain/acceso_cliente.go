@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// CodigoAcceso es un código de un solo uso enviado al email del cliente para iniciar sesión
+type CodigoAcceso struct {
+	ID          int
+	Email       string
+	HashCodigo  string // SHA-256 del código en hexadecimal
+	ExpiraEn    time.Time
+	Intentos    int    // verificaciones realizadas
+	IPSolicitud string // IP desde la que se pidió el código
+}
+
+// LimiteSolicitudesCodigo es la cantidad máxima de códigos que se emiten desde un momento por
+// email y por IP
+type LimiteSolicitudesCodigo struct {
+	Desde    time.Time
+	PorEmail int
+	PorIP    int
+}
+
+// ErrCodigoAccesoInvalido indica un código de acceso incorrecto, vencido o ya usado
+var ErrCodigoAccesoInvalido = errors.New("código de acceso inválido o vencido")
+
+// ErrDemasiadasSolicitudesCodigo indica que se superó el límite de códigos de acceso solicitados
+var ErrDemasiadasSolicitudesCodigo = errors.New("demasiadas solicitudes de código de acceso, intente más tarde")
+
+// AccesoClienteRepository define las operaciones disponibles con los códigos de acceso
+type AccesoClienteRepository interface {
+	// CrearCodigo registra un nuevo código del email e invalida los anteriores sin usar. Retorna
+	// ErrDemasiadasSolicitudesCodigo si el email o la IP superaron el límite.
+	CrearCodigo(codigo *CodigoAcceso, limite LimiteSolicitudesCodigo) error
+	// GetCodigoVigente obtiene el último código sin usar ni vencer del email. Retorna
+	// ErrCodigoAccesoInvalido si no hay.
+	GetCodigoVigente(email string, ahora time.Time) (*CodigoAcceso, error)
+	// RegistrarIntento suma una verificación al código si no agotó sus intentos. Retorna
+	// ErrCodigoAccesoInvalido si ya los agotó.
+	RegistrarIntento(id int, maxIntentos int) error
+	// MarcarUsado consume el código. Retorna ErrCodigoAccesoInvalido si ya fue usado.
+	MarcarUsado(id int, ahora time.Time) error
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// BloqueoHabitacion retira una habitación del inventario en un rango de fechas, por ejemplo por
+// mantenimiento. Como en las estadías, la fecha fin no se incluye.
+type BloqueoHabitacion struct {
+	ID            int       `json:"id"`
+	HabitacionID  int       `json:"habitacionId"`
+	FechaInicio   time.Time `json:"fechaInicio"`
+	FechaFin      time.Time `json:"fechaFin"`
+	Motivo        string    `json:"motivo"`
+	FechaCreacion time.Time `json:"fechaCreacion"`
+}
+
+// BloqueoConReservasError indica que el bloqueo se solapa con reservas activas de la habitación
+type BloqueoConReservasError struct {
+	HabitacionID int
+	Reservas     []int // IDs de las reservas en conflicto
+}
+
+func (e *BloqueoConReservasError) Error() string {
+	return fmt.Sprintf("la habitación %d tiene %d reserva(s) activa(s) en las fechas del bloqueo", e.HabitacionID, len(e.Reservas))
+}
+
+// BloqueoHabitacionRepository define las operaciones disponibles con los bloqueos de habitaciones
+type BloqueoHabitacionRepository interface {
+	// GetBloqueos obtiene los bloqueos que se solapan con el rango dado. habitacionID 0 incluye
+	// todas las habitaciones.
+	GetBloqueos(habitacionID int, desde, hasta time.Time) ([]BloqueoHabitacion, error)
+	// GetBloqueoByID obtiene un bloqueo por su ID
+	GetBloqueoByID(id int) (*BloqueoHabitacion, error)
+	// CreateBloqueo crea un bloqueo. Retorna *BloqueoConReservasError si la habitación tiene
+	// reservas activas en el rango.
+	CreateBloqueo(bloqueo *BloqueoHabitacion) error
+	// UpdateBloqueo actualiza un bloqueo. Retorna *BloqueoConReservasError si la habitación tiene
+	// reservas activas en el nuevo rango.
+	UpdateBloqueo(bloqueo *BloqueoHabitacion) error
+	// DeleteBloqueo elimina un bloqueo
+	DeleteBloqueo(id int) error
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 type ChatMessage struct {
 	Role    string `json:"role"`
@@ -8,10 +11,11 @@ type ChatMessage struct {
 }
 
 type ChatRequest struct {
-	Message        string       `json:"message"`
-	ConversationID *string      `json:"conversationId,omitempty"`
-	ClienteID      *int         `json:"clienteId,omitempty"`
-	Context        *ChatContext `json:"context,omitempty"`
+	Message        string  `json:"message"`
+	ConversationID *string `json:"conversationId,omitempty"`
+	// ClienteID es el ID del perfil del cliente (Cliente.ID); se toma de la sesión, nunca del body
+	ClienteID *int         `json:"-"`
+	Context   *ChatContext `json:"context,omitempty"`
 	// UseWeb: nil = auto (service decides), true = force web search, false = disable web search
 	UseWeb *bool `json:"useWeb,omitempty"`
 }
@@ -33,12 +37,15 @@ type ChatResponse struct {
 
 type ConversationHistory struct {
 	ID        string        `json:"id"`
-	ClienteID *int          `json:"clienteId,omitempty"`
+	ClienteID *int          `json:"clienteId,omitempty"` // ID del perfil del cliente (Cliente.ID)
 	Messages  []ChatMessage `json:"messages"`
 	CreatedAt time.Time     `json:"createdAt"`
 	UpdatedAt time.Time     `json:"updatedAt"`
 }
 
+// ErrConversacionAjena indica que la conversación pertenece a otro cliente
+var ErrConversacionAjena = errors.New("la conversación pertenece a otro cliente")
+
 type ChatbotRepository interface {
 	SaveConversation(conversation *ConversationHistory) error
 	GetConversation(conversationID string) (*ConversationHistory, error)
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Cliente es el perfil de un huésped, identificado por su email. Vincula sus reservas, sus
+// conversaciones con el chatbot y sus formularios de contacto.
+type Cliente struct {
+	ID                 int       `json:"id"`
+	Email              string    `json:"email"`
+	Nombre             string    `json:"nombre,omitempty"`
+	Telefono           string    `json:"telefono,omitempty"`
+	TipoDocumento      string    `json:"tipoDocumento,omitempty"`
+	NumeroDocumento    string    `json:"numeroDocumento,omitempty"`
+	Nacionalidad       string    `json:"nacionalidad,omitempty"` // Código ISO del país, ej. PE
+	Preferencias       string    `json:"preferencias,omitempty"`
+	Notas              string    `json:"notas,omitempty"` // notas internas del hotel
+	FechaRegistro      time.Time `json:"fechaRegistro"`
+	FechaActualizacion time.Time `json:"fechaActualizacion"`
+}
+
+// EstadiaCliente resume una reserva en el historial del cliente
+type EstadiaCliente struct {
+	ReservaID     int           `json:"reservaId"`
+	CodigoReserva string        `json:"codigoReserva"`
+	Estado        EstadoReserva `json:"estado"`
+	FechaEntrada  *time.Time    `json:"fechaEntrada,omitempty"`
+	FechaSalida   *time.Time    `json:"fechaSalida,omitempty"`
+	Noches        int           `json:"noches"`
+	Habitaciones  int           `json:"habitaciones"`
+	Total         float64       `json:"total"`
+}
+
+// HistorialCliente es el perfil del cliente con su historial de reservas. Las estadías, noches
+// y el gasto total solo cuentan las reservas completadas.
+type HistorialCliente struct {
+	Cliente
+	Estadias      int              `json:"estadias"`
+	Noches        int              `json:"noches"`
+	GastoTotal    float64          `json:"gastoTotal"`
+	UltimaEstadia *time.Time       `json:"ultimaEstadia,omitempty"`
+	Reservas      []EstadiaCliente `json:"reservas"`
+}
+
+// ErrClienteNoEncontrado indica que no existe el cliente
+var ErrClienteNoEncontrado = errors.New("cliente no encontrado")
+
+// ErrClienteDuplicado indica que ya existe un cliente con el email
+var ErrClienteDuplicado = errors.New("ya existe un cliente con ese email")
+
+// ClienteRepository define las operaciones disponibles con los clientes
+type ClienteRepository interface {
+	// GetClientes lista los clientes cuyo nombre o email contiene busqueda (vacía incluye todos)
+	GetClientes(busqueda string) ([]Cliente, error)
+	// GetClienteByID obtiene un cliente. Retorna ErrClienteNoEncontrado si no existe.
+	GetClienteByID(id int) (*Cliente, error)
+	// GetClienteByEmail obtiene un cliente por su email. Retorna ErrClienteNoEncontrado si no existe.
+	GetClienteByEmail(email string) (*Cliente, error)
+	// VincularCliente retorna el ID del cliente con el email de datos, creándolo si no existe. Los
+	// demás datos solo completan los campos que el cliente aún no tiene.
+	VincularCliente(datos Cliente) (int, error)
+	// CreateCliente crea un cliente. Retorna ErrClienteDuplicado si el email ya está registrado.
+	CreateCliente(cliente *Cliente) error
+	// UpdateCliente actualiza un cliente. Retorna ErrClienteNoEncontrado si no existe y
+	// ErrClienteDuplicado si el nuevo email ya está registrado.
+	UpdateCliente(cliente *Cliente) error
+	// GetHistorial obtiene las reservas del cliente, las más recientes primero, y los totales de
+	// sus estadías completadas
+	GetHistorial(id int) (*HistorialCliente, error)
+}
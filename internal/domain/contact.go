@@ -21,6 +21,7 @@ type Contact struct {
 	Estado         EstadoFormulario `db:"status" json:"estado"`
 	FechaEnvio     time.Time        `db:"sent_date" json:"fechaEnvio"`
 	FechaRespuesta *time.Time       `db:"response_date" json:"fechaRespuesta,omitempty"`
+	ClienteID      *int             `db:"client_profile_id" json:"clienteId,omitempty"`
 }
 
 type CreateContactRequest struct {
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ActorSistema identifica en el historial los cambios de estado hechos por procesos automáticos
+const ActorSistema = "sistema"
+
+// transicionesReserva define el ciclo de vida de una reserva: desde cada estado, los estados a los
+// que puede pasar. Completada y NoShow son estados finales.
+var transicionesReserva = map[EstadoReserva][]EstadoReserva{
+	ReservaPendiente:  {ReservaConfirmada, ReservaCancelada},
+	ReservaConfirmada: {ReservaCheckIn, ReservaNoShow, ReservaCancelada},
+	ReservaCheckIn:    {ReservaCompletada},
+	ReservaCancelada:  {ReservaPendiente, ReservaConfirmada}, // reactivación, requiere disponibilidad
+	ReservaCompletada: {},
+	ReservaNoShow:     {},
+}
+
+// Valido indica si el estado pertenece al ciclo de vida de las reservas
+func (e EstadoReserva) Valido() bool {
+	_, ok := transicionesReserva[e]
+	return ok
+}
+
+// PuedeCambiarA indica si la transición desde este estado al destino está permitida
+func (e EstadoReserva) PuedeCambiarA(destino EstadoReserva) bool {
+	for _, estado := range transicionesReserva[e] {
+		if estado == destino {
+			return true
+		}
+	}
+	return false
+}
+
+// EsReactivacion indica si la transición devuelve a vigencia una reserva cancelada
+func EsReactivacion(desde, hacia EstadoReserva) bool {
+	return desde == ReservaCancelada && (hacia == ReservaPendiente || hacia == ReservaConfirmada)
+}
+
+// TransicionInvalidaError indica que la reserva no puede pasar del estado actual al solicitado
+type TransicionInvalidaError struct {
+	Desde EstadoReserva
+	Hacia EstadoReserva
+}
+
+func (e *TransicionInvalidaError) Error() string {
+	return fmt.Sprintf("no se puede cambiar una reserva de %s a %s", e.Desde, e.Hacia)
+}
+
+// ValidarTransicion retorna *TransicionInvalidaError si la transición no está permitida
+func ValidarTransicion(desde, hacia EstadoReserva) error {
+	if !desde.PuedeCambiarA(hacia) {
+		return &TransicionInvalidaError{Desde: desde, Hacia: hacia}
+	}
+	return nil
+}
+
+// CambioEstadoReserva es una transición de estado de una reserva, tal como queda en el historial
+type CambioEstadoReserva struct {
+	ID        int           `json:"id"`
+	ReservaID int           `json:"reservaId"`
+	Desde     EstadoReserva `json:"desde"`
+	Hacia     EstadoReserva `json:"hacia"`
+	Actor     string        `json:"actor"` // quién realizó el cambio (usuario, "api" o "sistema")
+	Fecha     time.Time     `json:"fecha"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidarTransicion(t *testing.T) {
+	estados := []EstadoReserva{
+		ReservaPendiente,
+		ReservaConfirmada,
+		ReservaCheckIn,
+		ReservaCompletada,
+		ReservaCancelada,
+		ReservaNoShow,
+	}
+
+	permitidas := map[[2]EstadoReserva]bool{
+		{ReservaPendiente, ReservaConfirmada}: true,
+		{ReservaPendiente, ReservaCancelada}:  true,
+		{ReservaConfirmada, ReservaCheckIn}:   true,
+		{ReservaConfirmada, ReservaNoShow}:    true,
+		{ReservaConfirmada, ReservaCancelada}: true,
+		{ReservaCheckIn, ReservaCompletada}:   true,
+		{ReservaCancelada, ReservaPendiente}:  true,
+		{ReservaCancelada, ReservaConfirmada}: true,
+	}
+
+	for _, desde := range estados {
+		for _, hacia := range estados {
+			err := ValidarTransicion(desde, hacia)
+			if permitidas[[2]EstadoReserva{desde, hacia}] {
+				if err != nil {
+					t.Errorf("%s -> %s: error inesperado: %v", desde, hacia, err)
+				}
+				continue
+			}
+
+			var invalida *TransicionInvalidaError
+			if !errors.As(err, &invalida) {
+				t.Errorf("%s -> %s: se esperaba TransicionInvalidaError, se obtuvo %v", desde, hacia, err)
+				continue
+			}
+			if invalida.Desde != desde || invalida.Hacia != hacia {
+				t.Errorf("%s -> %s: el error reporta %s -> %s", desde, hacia, invalida.Desde, invalida.Hacia)
+			}
+		}
+	}
+}
+
+func TestEstadosFinales(t *testing.T) {
+	for _, estado := range []EstadoReserva{ReservaCompletada, ReservaNoShow} {
+		if !estado.Valido() {
+			t.Errorf("%s debería ser un estado válido", estado)
+		}
+		for destino := range transicionesReserva {
+			if estado.PuedeCambiarA(destino) {
+				t.Errorf("%s es final y no debería poder cambiar a %s", estado, destino)
+			}
+		}
+	}
+
+	if EstadoReserva("Inexistente").Valido() {
+		t.Error("un estado desconocido no debería ser válido")
+	}
+}
+
+func TestEsReactivacion(t *testing.T) {
+	tests := []struct {
+		desde, hacia EstadoReserva
+		esperado     bool
+	}{
+		{ReservaCancelada, ReservaPendiente, true},
+		{ReservaCancelada, ReservaConfirmada, true},
+		{ReservaPendiente, ReservaConfirmada, false},
+		{ReservaConfirmada, ReservaCancelada, false},
+	}
+
+	for _, tt := range tests {
+		if got := EsReactivacion(tt.desde, tt.hacia); got != tt.esperado {
+			t.Errorf("EsReactivacion(%s, %s) = %v, se esperaba %v", tt.desde, tt.hacia, got, tt.esperado)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Grupo es una reserva de grupo (boda, operador turístico) que retiene un cupo de habitaciones
+// por tipo. El cupo se guarda como reservas por tipo de su reserva; la rooming list las convierte
+// en habitaciones con huésped y las que no se asignan vuelven al inventario en FechaLiberacion.
+type Grupo struct {
+	ID              int        `json:"id"`
+	Nombre          string     `json:"nombre"`
+	EmailContacto   string     `json:"emailContacto"`
+	ReservaID       int        `json:"reservaId"`
+	FechaLiberacion time.Time  `json:"fechaLiberacion"`         // fecha de corte del cupo
+	FechaLiberado   *time.Time `json:"fechaLiberado,omitempty"` // cuándo se liberó el cupo no asignado
+	FechaCreacion   time.Time  `json:"fechaCreacion"`
+	Reserva         *Reserva   `json:"reserva,omitempty"`
+}
+
+// CupoGrupo es la cantidad de habitaciones de un tipo que retiene un grupo
+type CupoGrupo struct {
+	TipoHabitacionID int `json:"tipoHabitacionId"`
+	Cantidad         int `json:"cantidad"`
+}
+
+// HabitacionRooming es una línea de la rooming list: el huésped de una habitación del cupo
+type HabitacionRooming struct {
+	TipoHabitacionID int    `json:"tipoHabitacionId"`
+	HabitacionID     int    `json:"habitacionId,omitempty"` // 0 elige una habitación libre del tipo
+	NombreHuesped    string `json:"nombreHuesped"`
+}
+
+// ErrGrupoNoEncontrado indica que no existe el grupo
+var ErrGrupoNoEncontrado = errors.New("grupo no encontrado")
+
+// ErrCupoGrupoLiberado indica que el cupo del grupo ya se liberó y no admite más asignaciones
+var ErrCupoGrupoLiberado = errors.New("el cupo del grupo ya fue liberado")
+
+// GrupoRepository define las operaciones disponibles con los grupos
+type GrupoRepository interface {
+	// CreateGrupo registra el grupo de una reserva ya creada
+	CreateGrupo(grupo *Grupo) error
+	// GetGrupos lista los grupos por fecha de liberación
+	GetGrupos() ([]Grupo, error)
+	// GetGrupoByID obtiene un grupo (sin su reserva). Retorna ErrGrupoNoEncontrado si no existe.
+	GetGrupoByID(id int) (*Grupo, error)
+	// GetGruposPorLiberar obtiene los grupos con el cupo aún retenido cuya fecha de liberación es
+	// a más tardar hoy
+	GetGruposPorLiberar(hoy time.Time) ([]Grupo, error)
+	// MarcarLiberado registra que el cupo no asignado del grupo se devolvió al inventario
+	MarcarLiberado(id int, fecha time.Time) error
+}
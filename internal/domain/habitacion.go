@@ -1,16 +1,64 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // TipoHabitacion represents the room type
 type TipoHabitacion struct {
-	ID               int     `json:"id"`
-	Titulo           string  `json:"titulo"`
-	Descripcion      string  `json:"descripcion"`
-	CapacidadAdultos int     `json:"capacidadAdultos"`
-	CapacidadNinhos  int     `json:"capacidadNinhos"`
-	CantidadCamas    int     `json:"cantidadCamas"`
-	Precio           float64 `json:"precio"`
+	ID               int      `json:"id"`
+	Titulo           string   `json:"titulo"`
+	Descripcion      string   `json:"descripcion"`
+	CapacidadAdultos int      `json:"capacidadAdultos"`
+	CapacidadNinhos  int      `json:"capacidadNinhos"`
+	CantidadCamas    int      `json:"cantidadCamas"`
+	Precio           float64  `json:"precio"`
+	PrecioMinimo     *float64 `json:"precioMinimo,omitempty"` // límites del precio dinámico por noche
+	PrecioMaximo     *float64 `json:"precioMaximo,omitempty"`
+	Amenidades       []string `json:"amenidades,omitempty"`
+}
+
+// LimitarPrecio ajusta el precio de una noche al precio mínimo y máximo del tipo, si están definidos
+func (t TipoHabitacion) LimitarPrecio(precio float64) float64 {
+	if t.PrecioMinimo != nil && precio < *t.PrecioMinimo {
+		precio = *t.PrecioMinimo
+	}
+	if t.PrecioMaximo != nil && precio > *t.PrecioMaximo {
+		precio = *t.PrecioMaximo
+	}
+	return precio
+}
+
+// Estados de una habitación
+const (
+	HabitacionDisponible      = "Disponible"
+	HabitacionFueraDeServicio = "FueraDeServicio"
+)
+
+// ErrNumeroHabitacionDuplicado indica que ya existe otra habitación con el mismo número
+var ErrNumeroHabitacionDuplicado = errors.New("ya existe una habitación con ese número")
+
+// HabitacionConReservasError indica que la habitación tiene reservas activas futuras y no puede
+// retirarse de servicio
+type HabitacionConReservasError struct {
+	HabitacionID int
+	Reservas     int
+}
+
+func (e *HabitacionConReservasError) Error() string {
+	return fmt.Sprintf("la habitación %d tiene %d reserva(s) activa(s) futura(s)", e.HabitacionID, e.Reservas)
+}
+
+// TipoHabitacionEnUsoError indica que el tipo de habitación aún tiene habitaciones en servicio
+type TipoHabitacionEnUsoError struct {
+	TipoHabitacionID int
+	Habitaciones     int
+}
+
+func (e *TipoHabitacionEnUsoError) Error() string {
+	return fmt.Sprintf("el tipo de habitación %d tiene %d habitación(es) en servicio", e.TipoHabitacionID, e.Habitaciones)
 }
 
 // Habitacion represents a room in the hotel with its type information
@@ -21,6 +69,7 @@ type Habitacion struct {
 	Capacidad          int            `json:"capacidad"`
 	Estado             string         `json:"estado"`
 	DescripcionGeneral string         `json:"descripcionGeneral"`
+	EstadoLimpieza     string         `json:"estadoLimpieza,omitempty"` // Limpia, Ocupada o Sucia
 	TipoHabitacion     TipoHabitacion `json:"tipoHabitacion"`
 	MediaID            int            `json:"-"` // El tag "-" hace que este campo se omita en la serialización JSON
 }
@@ -28,25 +77,128 @@ type Habitacion struct {
 // FechasBloqueadas representa las fechas donde no hay disponibilidad
 type FechasBloqueadas struct {
 	FechasNoDisponibles []time.Time `json:"fechasNoDisponibles"`
+	// Fechas en las que ningún tipo de habitación admite llegadas o salidas
+	FechasSinLlegada []time.Time `json:"fechasSinLlegada"`
+	FechasSinSalida  []time.Time `json:"fechasSinSalida"`
+	// Estadía mínima y máxima para llegar en cada fecha (YYYY-MM-DD), con el tipo más flexible
+	EstanciasMinimas map[string]int `json:"estanciasMinimas,omitempty"`
+	EstanciasMaximas map[string]int `json:"estanciasMaximas,omitempty"`
 }
 
-// DisponibilidadFecha representa la disponibilidad de habitaciones para una fecha específica
+// DisponibilidadFecha representa la disponibilidad de un tipo de habitación para una fecha específica
 type DisponibilidadFecha struct {
-	Fecha        time.Time `json:"fecha"`
-	Disponible   bool      `json:"disponible"`
-	Habitaciones int       `json:"habitaciones"`
+	Fecha            time.Time `json:"fecha"`
+	TipoHabitacionID int       `json:"tipoHabitacionId"`
+	Disponible       bool      `json:"disponible"`
+	Habitaciones     int       `json:"habitaciones"` // habitaciones libres
+	Reservadas       int       `json:"reservadas"`
+	Bloqueadas       int       `json:"bloqueadas"` // en mantenimiento
+	Total            int       `json:"total"`
 }
 
+// DisponibilidadTipo resume la disponibilidad y el precio por noche de un tipo de habitación en una fecha
+type DisponibilidadTipo struct {
+	TipoHabitacionID int     `json:"tipoHabitacionId"`
+	Titulo           string  `json:"titulo"`
+	Libres           int     `json:"libres"`
+	Reservadas       int     `json:"reservadas"`
+	Bloqueadas       int     `json:"bloqueadas"`
+	Total            int     `json:"total"`
+	Precio           float64 `json:"precio"`
+	// Restricciones de estadía para las llegadas (y salidas) de la fecha
+	EstanciaMinima int  `json:"estanciaMinima,omitempty"`
+	EstanciaMaxima int  `json:"estanciaMaxima,omitempty"`
+	CerradoLlegada bool `json:"cerradoLlegada,omitempty"`
+	CerradoSalida  bool `json:"cerradoSalida,omitempty"`
+}
+
+// DiaCalendario representa un día del calendario de disponibilidad con el detalle por tipo de habitación
+type DiaCalendario struct {
+	Fecha time.Time            `json:"fecha"`
+	Tipos []DisponibilidadTipo `json:"tipos"`
+}
+
+// Criterios de orden de la búsqueda de habitaciones
+const (
+	OrdenPrecioAsc  = "precio"
+	OrdenPrecioDesc = "-precio"
+	OrdenCapacidad  = "capacidad"
+	OrdenNumero     = "numero"
+)
+
+// FiltroBusquedaHabitaciones contiene los criterios de búsqueda de habitaciones disponibles.
+// Los valores cero no filtran.
+type FiltroBusquedaHabitaciones struct {
+	FechaEntrada     time.Time
+	FechaSalida      time.Time
+	Adultos          int
+	Ninhos           int
+	TipoHabitacionID int
+	PrecioMin        float64 // sobre el precio base por noche del tipo de habitación
+	PrecioMax        float64
+	MinCamas         int
+	Amenidades       []string // la habitación debe tener todas
+	Orden            string
+	Pagina           int // desde 1
+	PorPagina        int // 0 retorna todos los resultados
+}
+
+// Paginacion describe la página retornada de un listado
+type Paginacion struct {
+	Pagina       int `json:"pagina"`
+	PorPagina    int `json:"porPagina"`
+	Total        int `json:"total"`
+	TotalPaginas int `json:"totalPaginas"`
+}
+
+// CombinacionHabitaciones es un conjunto de habitaciones que en conjunto alojan a todo el grupo
+type CombinacionHabitaciones struct {
+	Habitaciones     []Habitacion `json:"habitaciones"`
+	CapacidadAdultos int          `json:"capacidadAdultos"`
+	CapacidadTotal   int          `json:"capacidadTotal"`
+	Noches           int          `json:"noches"`
+	Total            float64      `json:"total"` // precio de la estadía de todas las habitaciones
+}
+
+// ErrSinCombinacionHabitaciones indica que las habitaciones disponibles no alcanzan para alojar al grupo
+var ErrSinCombinacionHabitaciones = errors.New("no hay habitaciones disponibles suficientes para alojar al grupo")
+
 // HabitacionRepository defines the interface for room data operations
 type HabitacionRepository interface {
 	// GetAllRooms returns all rooms in the system
 	GetAllRooms() ([]Habitacion, error)
+	// GetRoomByID returns a room with its type information
+	GetRoomByID(id int) (*Habitacion, error)
 	// GetAvailableRooms returns rooms that are available for the given date range
 	GetAvailableRooms(fechaEntrada, fechaSalida time.Time) ([]Habitacion, error)
 	// GetFechasBloqueadas returns dates where there are no rooms available
 	GetFechasBloqueadas(desde time.Time, hasta time.Time) (*FechasBloqueadas, error)
-	// GetDisponibilidadFechas returns the availability status for each date in the given range
-	GetDisponibilidadFechas(desde time.Time, hasta time.Time) ([]DisponibilidadFecha, error)
-	// GetRoomTypes returns all room types in the system
+	// GetDisponibilidadFechas returns the availability of each room type for each night in the
+	// given range (both inclusive). tipoHabitacionID 0 includes every room type.
+	GetDisponibilidadFechas(desde time.Time, hasta time.Time, tipoHabitacionID int) ([]DisponibilidadFecha, error)
+	// BuscarHabitaciones returns the rooms available for the filter's date range that match the
+	// filter, together with the total number of matches before pagination
+	BuscarHabitaciones(filtro FiltroBusquedaHabitaciones) ([]Habitacion, int, error)
+	// GetRoomTypes returns all active room types in the system
 	GetRoomTypes() ([]TipoHabitacion, error)
+	// GetRoomTypeByID returns an active room type
+	GetRoomTypeByID(id int) (*TipoHabitacion, error)
+	// CreateRoom creates a new room. Returns ErrNumeroHabitacionDuplicado if the number is taken.
+	CreateRoom(habitacion *Habitacion) error
+	// UpdateRoom updates a room. Taking it out of service returns *HabitacionConReservasError if
+	// it has future active reservations, and a taken number returns ErrNumeroHabitacionDuplicado.
+	UpdateRoom(habitacion *Habitacion) error
+	// DeactivateRoom takes a room out of service. Returns *HabitacionConReservasError if it has
+	// future active reservations.
+	DeactivateRoom(id int) error
+	// UpdateEstadoLimpieza sets the housekeeping status of a room
+	UpdateEstadoLimpieza(id int, estado string) error
+	// CreateRoomType creates a new room type
+	CreateRoomType(tipo *TipoHabitacion) error
+	// UpdateRoomType updates a room type. Returns an error if its new capacity is lower than the
+	// capacity of any of its rooms.
+	UpdateRoomType(tipo *TipoHabitacion) error
+	// DeactivateRoomType deactivates a room type. Returns *TipoHabitacionEnUsoError if it still
+	// has rooms in service.
+	DeactivateRoomType(id int) error
 }
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// RegistroIdempotencia es la solicitud registrada con una clave de idempotencia y, una vez
+// procesada, su respuesta
+type RegistroIdempotencia struct {
+	Clave         string
+	Ruta          string // método y ruta de la solicitud
+	HashSolicitud string // SHA-256 del cuerpo en hexadecimal
+	Status        int    // 0 mientras la solicitud original está en proceso
+	ContentType   string
+	Cuerpo        []byte
+	ExpiraEn      time.Time
+	// BloqueadaHasta es el plazo de la solicitud en proceso; vencido, un reintento la reemplaza
+	BloqueadaHasta time.Time
+}
+
+// EnProceso indica si la solicitud original aún no tiene respuesta
+func (r *RegistroIdempotencia) EnProceso() bool {
+	return r.Status == 0
+}
+
+// ErrIdempotenciaConflicto indica que la clave ya se usó con una solicitud distinta
+var ErrIdempotenciaConflicto = errors.New("la clave de idempotencia ya se usó con otra solicitud")
+
+// ErrIdempotenciaEnProceso indica que la solicitud original con la clave aún se está procesando
+var ErrIdempotenciaEnProceso = errors.New("la solicitud con esta clave de idempotencia aún se está procesando")
+
+// IdempotenciaRepository define las operaciones disponibles con las claves de idempotencia
+type IdempotenciaRepository interface {
+	// Reservar registra la clave en proceso si no existe, ya venció o si la misma solicitud quedó
+	// en proceso más allá de su plazo. Si no, no la modifica y retorna el registro existente; si
+	// la registró retorna nil.
+	Reservar(registro *RegistroIdempotencia, ahora time.Time) (*RegistroIdempotencia, error)
+	// GuardarRespuesta guarda la respuesta de la solicitud registrada con la clave
+	GuardarRespuesta(registro *RegistroIdempotencia) error
+	// Liberar elimina la clave para que un reintento vuelva a procesar la solicitud
+	Liberar(clave, ruta string) error
+	// EliminarVencidas elimina las claves vencidas. Retorna cuántas eliminó.
+	EliminarVencidas(ahora time.Time) (int64, error)
+}
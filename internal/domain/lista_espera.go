@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Estados de una solicitud de la lista de espera
+const (
+	EsperaEnCola    = "Esperando"
+	EsperaOfertada  = "Ofertada"  // tiene una reserva pendiente en hold esperando confirmación
+	EsperaAceptada  = "Aceptada"  // el huésped confirmó la reserva ofrecida
+	EsperaVencida   = "Vencida"   // la oferta no se confirmó a tiempo o la fecha de entrada ya pasó
+	EsperaCancelada = "Cancelada" // el huésped dejó la lista de espera
+)
+
+// SolicitudEspera registra el interés de un huésped en fechas agotadas. Cuando se liberan
+// habitaciones se le ofrece una reserva pendiente que debe confirmar antes de OfertaExpiraEn.
+type SolicitudEspera struct {
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	FechaEntrada    time.Time  `json:"fechaEntrada"`
+	FechaSalida     time.Time  `json:"fechaSalida"`
+	CantidadAdultos int        `json:"cantidadAdultos"`
+	CantidadNinhos  int        `json:"cantidadNinhos"`
+	TiposHabitacion []int      `json:"tiposHabitacion"` // en orden de preferencia; vacío acepta cualquier tipo
+	Estado          string     `json:"estado"`
+	ReservaID       *int       `json:"reservaId,omitempty"` // reserva ofrecida
+	TokenOferta     string     `json:"-"`                   // identifica la oferta en el enlace enviado por email
+	OfertaExpiraEn  *time.Time `json:"ofertaExpiraEn,omitempty"`
+	FechaRegistro   time.Time  `json:"fechaRegistro"`
+}
+
+// ErrSolicitudEsperaNoEncontrada indica que no existe la solicitud u oferta de la lista de espera
+var ErrSolicitudEsperaNoEncontrada = errors.New("solicitud de lista de espera no encontrada")
+
+// ErrOfertaVencida indica que la oferta de la lista de espera ya no se puede confirmar
+var ErrOfertaVencida = errors.New("la oferta de la lista de espera venció o ya no está disponible")
+
+// ListaEsperaRepository define las operaciones disponibles con la lista de espera
+type ListaEsperaRepository interface {
+	// CreateSolicitud registra una solicitud al final de la lista de espera
+	CreateSolicitud(solicitud *SolicitudEspera) error
+	// GetSolicitudes lista las solicitudes en orden de registro; estado vacío incluye todas
+	GetSolicitudes(estado string) ([]SolicitudEspera, error)
+	// GetSolicitudByToken obtiene la solicitud de una oferta. Retorna
+	// ErrSolicitudEsperaNoEncontrada si el token no existe.
+	GetSolicitudByToken(token string) (*SolicitudEspera, error)
+	// RegistrarOferta asocia la reserva ofrecida a la solicitud si sigue esperando. Retorna
+	// ErrSolicitudEsperaNoEncontrada si la solicitud ya no está en espera.
+	RegistrarOferta(id, reservaID int, token string, expiraEn time.Time) error
+	// CambiarEstado cambia el estado de la solicitud si sigue en el estado desde
+	CambiarEstado(id int, desde, hacia string) error
+	// VencerSolicitudes marca como vencidas las ofertas cuyo hold venció en ahora y las
+	// solicitudes en espera cuya fecha de entrada ya pasó
+	VencerSolicitudes(ahora, hoy time.Time) error
+}
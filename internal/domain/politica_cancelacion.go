@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// TipoPenalidad indica cómo se calcula la penalidad fuera del plazo de cancelación gratuita
+type TipoPenalidad string
+
+const (
+	// PenalidadPorcentaje cobra un porcentaje del importe de la estadía
+	PenalidadPorcentaje TipoPenalidad = "Porcentaje"
+	// PenalidadPrimeraNoche cobra el precio de la primera noche
+	PenalidadPrimeraNoche TipoPenalidad = "PrimeraNoche"
+)
+
+// PoliticaCancelacion define hasta cuándo se puede cancelar sin costo y la penalidad posterior.
+// Se asocia a un plan de tarifa o a un tipo de habitación; el plan de tarifa tiene prioridad.
+type PoliticaCancelacion struct {
+	ID               int           `json:"id"`
+	Nombre           string        `json:"nombre"`
+	HorasLibres      int           `json:"horasLibres"` // horas antes del check-in con cancelación gratuita
+	TipoPenalidad    TipoPenalidad `json:"tipoPenalidad"`
+	Porcentaje       float64       `json:"porcentaje"` // solo para PenalidadPorcentaje
+	TipoHabitacionID *int          `json:"tipoHabitacionId,omitempty"`
+	PlanTarifaID     *int          `json:"planTarifaId,omitempty"`
+	Activo           bool          `json:"activo"`
+}
+
+// PenalidadCancelacion es el resultado de aplicar las políticas de cancelación a una reserva
+type PenalidadCancelacion struct {
+	ReservaID           int       `json:"reservaId"`
+	FechaCalculo        time.Time `json:"fechaCalculo"`
+	LimiteGratuito      time.Time `json:"limiteGratuito"` // último momento para cancelar sin costo
+	CancelacionGratuita bool      `json:"cancelacionGratuita"`
+	Penalidad           float64   `json:"penalidad"`
+	Reembolso           float64   `json:"reembolso"`
+	Politicas           []string  `json:"politicas"` // nombres de las políticas aplicadas
+}
+
+// PoliticaCancelacionRepository define las operaciones disponibles con las políticas de cancelación
+type PoliticaCancelacionRepository interface {
+	// GetPoliticas obtiene todas las políticas de cancelación
+	GetPoliticas() ([]PoliticaCancelacion, error)
+	// GetPoliticasActivas obtiene las políticas activas
+	GetPoliticasActivas() ([]PoliticaCancelacion, error)
+	// CreatePolitica crea una nueva política de cancelación
+	CreatePolitica(politica *PoliticaCancelacion) error
+	// UpdatePolitica actualiza una política de cancelación existente
+	UpdatePolitica(politica *PoliticaCancelacion) error
+}
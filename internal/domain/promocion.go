@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// TipoDescuento indica cómo se calcula el descuento de una promoción
+type TipoDescuento string
+
+const (
+	DescuentoPorcentaje TipoDescuento = "Porcentaje"
+	DescuentoMonto      TipoDescuento = "Monto"
+)
+
+var (
+	// ErrPromocionAgotada indica que la promoción alcanzó su límite global de usos
+	ErrPromocionAgotada = errors.New("el código promocional alcanzó su límite de usos")
+	// ErrPromocionLimiteCliente indica que el cliente ya usó la promoción el máximo de veces permitido
+	ErrPromocionLimiteCliente = errors.New("ya utilizaste este código promocional el máximo de veces permitido")
+)
+
+// Promocion representa un código promocional con sus reglas de aplicación
+type Promocion struct {
+	ID                int           `json:"id"`
+	Codigo            string        `json:"codigo"`
+	Descripcion       string        `json:"descripcion"`
+	TipoDescuento     TipoDescuento `json:"tipoDescuento"`
+	Valor             float64       `json:"valor"`
+	FechaInicio       time.Time     `json:"fechaInicio"`
+	FechaFin          time.Time     `json:"fechaFin"`
+	TiposHabitacion   []int         `json:"tiposHabitacion"` // vacío aplica a todos los tipos
+	MinNoches         int           `json:"minNoches"`
+	MaxUsos           *int          `json:"maxUsos,omitempty"`
+	MaxUsosPorCliente *int          `json:"maxUsosPorCliente,omitempty"`
+	Usos              int           `json:"usos"`
+	Activo            bool          `json:"activo"`
+}
+
+// AplicaATipo indica si la promoción aplica al tipo de habitación dado
+func (p Promocion) AplicaATipo(tipoHabitacionID int) bool {
+	if len(p.TiposHabitacion) == 0 {
+		return true
+	}
+	for _, id := range p.TiposHabitacion {
+		if id == tipoHabitacionID {
+			return true
+		}
+	}
+	return false
+}
+
+// PromocionRepository define las operaciones disponibles con las promociones
+type PromocionRepository interface {
+	// GetPromociones obtiene todas las promociones con su cantidad de usos
+	GetPromociones() ([]Promocion, error)
+	// GetPromocionByID obtiene una promoción por su ID
+	GetPromocionByID(id int) (*Promocion, error)
+	// GetPromocionByCodigo obtiene una promoción por su código
+	GetPromocionByCodigo(codigo string) (*Promocion, error)
+	// CreatePromocion crea una nueva promoción
+	CreatePromocion(promocion *Promocion) error
+	// UpdatePromocion actualiza una promoción existente
+	UpdatePromocion(promocion *Promocion) error
+	// DesactivarPromocion desactiva una promoción
+	DesactivarPromocion(id int) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Estados de limpieza de una habitación
+const (
+	LimpiezaLimpia  = "Limpia"
+	LimpiezaOcupada = "Ocupada" // huésped alojado
+	LimpiezaSucia   = "Sucia"   // pendiente de limpieza tras el check-out
+)
+
+// EstadoLimpiezaValido indica si el estado de limpieza existe
+func EstadoLimpiezaValido(estado string) bool {
+	return estado == LimpiezaLimpia || estado == LimpiezaOcupada || estado == LimpiezaSucia
+}
+
+// HuespedRegistrado es un huésped identificado con su documento al hacer check-in
+type HuespedRegistrado struct {
+	ID              int    `json:"id"`
+	ReservaID       int    `json:"reservaId"`
+	HabitacionID    *int   `json:"habitacionId,omitempty"`
+	Nombre          string `json:"nombre"`
+	TipoDocumento   string `json:"tipoDocumento"` // DNI, CE o Pasaporte
+	NumeroDocumento string `json:"numeroDocumento"`
+	Nacionalidad    string `json:"nacionalidad,omitempty"` // Código ISO del país, ej. PE
+}
+
+// CargoAdicional es un consumo o servicio de la estadía que se suma a la cuenta al hacer check-out
+type CargoAdicional struct {
+	ID        int       `json:"id"`
+	ReservaID int       `json:"reservaId"`
+	Concepto  string    `json:"concepto"`
+	Monto     float64   `json:"monto"`
+	Fecha     time.Time `json:"fecha"`
+}
+
+// FechaRecepcionError indica que la fecha de hoy no permite registrar el check-in o el check-out
+type FechaRecepcionError struct {
+	Operacion string // check-in o check-out
+	Hoy       time.Time
+	Fecha     time.Time // fecha de entrada o de salida de la reserva
+	Motivo    string
+}
+
+func (e *FechaRecepcionError) Error() string {
+	return fmt.Sprintf("no se puede registrar el %s el %s: %s (%s)",
+		e.Operacion, e.Hoy.Format("2006-01-02"), e.Motivo, e.Fecha.Format("2006-01-02"))
+}
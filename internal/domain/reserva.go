@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,29 +14,160 @@ const (
 	ReservaConfirmada EstadoReserva = "Confirmada"
 	ReservaCancelada  EstadoReserva = "Cancelada"
 	ReservaCompletada EstadoReserva = "Completada"
+	ReservaCheckIn    EstadoReserva = "CheckIn" // huésped alojado
+	ReservaNoShow     EstadoReserva = "NoShow"  // huésped no se presentó
+)
+
+// Tipos de documento de identidad del huésped
+const (
+	DocumentoDNI       = "DNI"
+	DocumentoCE        = "CE" // Carné de extranjería
+	DocumentoPasaporte = "Pasaporte"
 )
 
 // Reserva representa una reserva principal
 type Reserva struct {
 	ID                int                 `json:"id"`
+	CodigoReserva     string              `json:"codigoReserva"` // código de confirmación no secuencial, ej. HIN-7K3Q9P
 	CantidadAdultos   int                 `json:"cantidadAdultos"`
 	CantidadNinhos    int                 `json:"cantidadNinhos"`
 	Estado            EstadoReserva       `json:"estado"`
-	ClienteID         string              `json:"clienteId"`
+	ClienteID         string              `json:"clienteId"`                 // email del cliente
+	PerfilClienteID   *int                `json:"perfilClienteId,omitempty"` // perfil del cliente con ese email
 	Subtotal          float64             `json:"subtotal"`
 	Descuento         float64             `json:"descuento"`
+	PromocionID       *int                `json:"promocionId,omitempty"`
+	CodigoPromocion   string              `json:"codigoPromocion,omitempty"`
+	CargoServicio     float64             `json:"cargoServicio"`
+	IGV               float64             `json:"igv"`
+	ExoneradoIGV      bool                `json:"exoneradoIgv"`
+	Total             float64             `json:"total"`
+	TipoDocumento     string              `json:"tipoDocumento,omitempty"`
+	NumeroDocumento   string              `json:"numeroDocumento,omitempty"`
+	Nacionalidad      string              `json:"nacionalidad,omitempty"` // Código ISO del país, ej. PE
 	FechaConfirmacion time.Time           `json:"fechaConfirmacion"`
+	ExpiraEn          *time.Time          `json:"expiraEn,omitempty"`             // vencimiento del hold de una reserva pendiente
+	Penalidad         *float64            `json:"penalidadCancelacion,omitempty"` // se registra al cancelar
+	Reembolso         *float64            `json:"reembolso,omitempty"`
+	FechaCancelacion  *time.Time          `json:"fechaCancelacion,omitempty"`
+	FechaCheckIn      *time.Time          `json:"fechaCheckIn,omitempty"`  // llegada real del huésped
+	FechaCheckOut     *time.Time          `json:"fechaCheckOut,omitempty"` // salida real del huésped
 	Habitaciones      []ReservaHabitacion `json:"habitaciones"`
+	// TiposHabitacion son las habitaciones reservadas por tipo que aún no tienen habitación asignada
+	TiposHabitacion []ReservaTipoHabitacion `json:"tiposHabitacion,omitempty"`
+	// Huespedes son los huéspedes identificados en el check-in
+	Huespedes []HuespedRegistrado `json:"huespedes,omitempty"`
+	// Cargos son los consumos de la estadía que se suman a la cuenta en el check-out
+	Cargos []CargoAdicional `json:"cargos,omitempty"`
+}
+
+// Unidades retorna las habitaciones asignadas y una unidad por cada habitación reservada por tipo
+// aún sin asignar, para calcular montos, promociones y penalidades sobre toda la reserva
+func (r Reserva) Unidades() []ReservaHabitacion {
+	unidades := make([]ReservaHabitacion, 0, len(r.Habitaciones)+len(r.TiposHabitacion))
+	unidades = append(unidades, r.Habitaciones...)
+	for _, tipo := range r.TiposHabitacion {
+		for i := 0; i < tipo.Cantidad; i++ {
+			unidades = append(unidades, tipo.Unidad())
+		}
+	}
+	return unidades
+}
+
+// PerteneceACliente indica si la reserva es del cliente con el perfil o el email dados
+func (r *Reserva) PerteneceACliente(perfilClienteID int, email string) bool {
+	if r.PerfilClienteID != nil && *r.PerfilClienteID == perfilClienteID {
+		return true
+	}
+	return email != "" && strings.EqualFold(strings.TrimSpace(r.ClienteID), email)
+}
+
+// ErrReservaNoEncontrada indica que no existe una reserva para los datos de búsqueda
+var ErrReservaNoEncontrada = errors.New("reserva no encontrada")
+
+// ErrCodigoReservaDuplicado indica que el código de confirmación generado ya existe
+var ErrCodigoReservaDuplicado = errors.New("el código de reserva ya existe")
+
+// ErrReservaExpirada indica que el hold de la reserva pendiente venció
+var ErrReservaExpirada = errors.New("la reserva pendiente expiró y sus habitaciones fueron liberadas")
+
+// Expirada indica si la reserva está pendiente y su hold ya venció en el momento dado
+func (r Reserva) Expirada(ahora time.Time) bool {
+	return r.Estado == ReservaPendiente && r.ExpiraEn != nil && !ahora.Before(*r.ExpiraEn)
+}
+
+// HabitacionNoDisponibleError indica que la habitación ya está ocupada en las fechas solicitadas
+type HabitacionNoDisponibleError struct {
+	HabitacionID int
+}
+
+func (e *HabitacionNoDisponibleError) Error() string {
+	return fmt.Sprintf("la habitación %d no está disponible para las fechas seleccionadas", e.HabitacionID)
 }
 
 // ReservaRepository define las operaciones disponibles con las reservas
 type ReservaRepository interface {
 	// GetReservaByID obtiene una reserva por su ID
 	GetReservaByID(id int) (*Reserva, error)
-	// CreateReserva crea una nueva reserva
-	CreateReserva(reserva *Reserva) error
-	// UpdateReservaEstado actualiza el estado de una reserva
-	UpdateReservaEstado(id int, estado EstadoReserva) error
+	// GetReservaByCodigo obtiene una reserva por su código de confirmación.
+	// Retorna ErrReservaNoEncontrada si el código no existe.
+	GetReservaByCodigo(codigo string) (*Reserva, error)
+	// CreateReserva crea una nueva reserva verificando la disponibilidad de forma atómica.
+	// Retorna *HabitacionNoDisponibleError si alguna habitación ya está ocupada,
+	// *TipoHabitacionAgotadoError si no quedan habitaciones de algún tipo reservado,
+	// ErrPromocionAgotada o ErrPromocionLimiteCliente si el canje supera los límites de uso y
+	// ErrCodigoReservaDuplicado si el código de confirmación ya existe. Registra el estado
+	// inicial en el historial a nombre del actor.
+	CreateReserva(reserva *Reserva, actor string) error
+	// ModificarReserva actualiza los montos y huéspedes de la reserva y reemplaza sus habitaciones
+	// activas en una sola transacción. Retorna *HabitacionNoDisponibleError si alguna habitación
+	// nueva ya está ocupada por otra reserva.
+	ModificarReserva(reserva *Reserva) error
+	// CancelarHabitacion cancela una habitación activa de la reserva registrando su penalidad y
+	// reembolso, y guarda los montos ya recalculados con las habitaciones restantes
+	CancelarHabitacion(reserva *Reserva, habitacionID int, penalidad *PenalidadCancelacion) error
+	// CancelarReserva cancela la reserva y todas sus habitaciones registrando la penalidad, el
+	// reembolso y el cambio de estado en el historial
+	CancelarReserva(cambio *CambioEstadoReserva, penalidad *PenalidadCancelacion) error
+	// ReactivarReserva restaura las habitaciones liberadas por la cancelación verificando que sigan
+	// disponibles. Retorna *HabitacionNoDisponibleError si alguna ya fue ocupada.
+	// Si la reserva vuelve a Pendiente, expiraEn es el vencimiento de su nuevo hold.
+	ReactivarReserva(cambio *CambioEstadoReserva, expiraEn *time.Time) error
+	// CambiarEstado aplica el cambio de estado si la reserva sigue en el estado de origen y lo
+	// registra en el historial. Retorna *TransicionInvalidaError si el estado cambió entretanto.
+	CambiarEstado(cambio *CambioEstadoReserva) error
+	// GetHistorialEstados obtiene los cambios de estado de la reserva en orden cronológico
+	GetHistorialEstados(reservaID int) ([]CambioEstadoReserva, error)
+	// GetReservasExpiradas obtiene las reservas pendientes cuyo hold venció (sin sus habitaciones)
+	GetReservasExpiradas(ahora time.Time) ([]Reserva, error)
+	// GetReservasNoShow obtiene las reservas confirmadas cuya primera llegada está entre desde y
+	// hasta, ambas inclusive (sin sus habitaciones)
+	GetReservasNoShow(desde, hasta time.Time) ([]Reserva, error)
+	// MarcarNoShow pasa la reserva a NoShow registrando la penalidad y el reembolso y devuelve al
+	// inventario sus noches desde fechaLiberacion
+	MarcarNoShow(cambio *CambioEstadoReserva, penalidad *PenalidadCancelacion, fechaLiberacion time.Time) error
 	// GetReservasCliente obtiene todas las reservas de un cliente
 	GetReservasCliente(clienteID string) ([]Reserva, error)
+	// AsignarHabitaciones asigna habitaciones concretas a las reservas por tipo pendientes de la
+	// reserva. Cada reserva por tipo incluida debe recibir todas sus habitaciones. Retorna
+	// *HabitacionNoDisponibleError si alguna habitación fue ocupada entretanto.
+	AsignarHabitaciones(reservaID int, asignaciones []AsignacionHabitacion) error
+	// AsignarRoomingList asigna parte de las habitaciones de las reservas por tipo pendientes con
+	// el huésped de cada una; las demás siguen pendientes. Retorna *HabitacionNoDisponibleError si
+	// alguna habitación fue ocupada entretanto.
+	AsignarRoomingList(reservaID int, asignaciones []AsignacionHabitacion) error
+	// LiberarTiposPorAsignar cancela las reservas por tipo pendientes de la reserva, devolviéndolas
+	// al inventario, y guarda los montos ya recalculados sin ellas
+	LiberarTiposPorAsignar(reserva *Reserva, canceladaEn time.Time) error
+	// ReasignarHabitacion mueve la reserva de una habitación a otra para las mismas fechas.
+	// Retorna *HabitacionNoDisponibleError si la nueva habitación está ocupada y
+	// *TipoHabitacionAgotadoError si el cambio deja sin habitaciones a un tipo reservado.
+	ReasignarHabitacion(reservaID, habitacionActualID, habitacionNuevaID int) error
+	// RegistrarCheckIn pasa la reserva a CheckIn con la fecha del cambio como llegada real,
+	// registra sus huéspedes y marca sus habitaciones como ocupadas
+	RegistrarCheckIn(cambio *CambioEstadoReserva, huespedes []HuespedRegistrado) error
+	// RegistrarCheckOut pasa la reserva a Completada con la fecha del cambio como salida real,
+	// guarda sus cargos nuevos y montos finales, libera las noches desde fechaSalida (salida
+	// anticipada) y marca sus habitaciones para limpieza
+	RegistrarCheckOut(cambio *CambioEstadoReserva, reserva *Reserva, fechaSalida time.Time) error
 }
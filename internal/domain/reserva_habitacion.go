@@ -6,13 +6,38 @@ import (
 
 // ReservaHabitacion representa la relación entre una reserva y una habitación
 type ReservaHabitacion struct {
-	ReservaID    int         `json:"reservaId"`
-	HabitacionID int         `json:"habitacionId"`
-	Precio       float64     `json:"precio"`
-	FechaEntrada time.Time   `json:"fechaEntrada"`
-	FechaSalida  time.Time   `json:"fechaSalida"`
-	Estado       int         `json:"estado"` // 1: Activa, 0: Cancelada
-	Habitacion   *Habitacion `json:"habitacion,omitempty"`
+	ReservaID    int           `json:"reservaId"`
+	HabitacionID int           `json:"habitacionId"`
+	Precio       float64       `json:"precio"`
+	FechaEntrada time.Time     `json:"fechaEntrada"`
+	FechaSalida  time.Time     `json:"fechaSalida"`
+	Estado       int           `json:"estado"` // 1: Activa, 0: Cancelada
+	Desglose     []PrecioNoche `json:"desglose,omitempty"`
+	Habitacion   *Habitacion   `json:"habitacion,omitempty"`
+	// NombreHuesped es el huésped de la habitación según la rooming list de un grupo
+	NombreHuesped string `json:"nombreHuesped,omitempty"`
+}
+
+// Noches retorna la cantidad de noches de la estadía (mínimo 1)
+func (rh ReservaHabitacion) Noches() int {
+	noches := int(rh.FechaSalida.Sub(rh.FechaEntrada).Hours() / 24)
+	if noches < 1 {
+		noches = 1
+	}
+	return noches
+}
+
+// Importe retorna el monto de la habitación por toda la estadía
+func (rh ReservaHabitacion) Importe() float64 {
+	if len(rh.Desglose) == 0 {
+		return rh.Precio * float64(rh.Noches())
+	}
+
+	total := 0.0
+	for _, noche := range rh.Desglose {
+		total += noche.Precio
+	}
+	return total
 }
 
 // ReservaHabitacionRepository define las operaciones disponibles con las reservas de habitaciones
@@ -25,6 +50,11 @@ type ReservaHabitacionRepository interface {
 	UpdateReservaHabitacionEstado(reservaID, habitacionID int, estado int) error
 	// VerificarDisponibilidad verifica si una habitación está disponible para las fechas dadas
 	VerificarDisponibilidad(habitacionID int, fechaEntrada, fechaSalida time.Time) (bool, error)
+	// VerificarDisponibilidadExcluyendo verifica la disponibilidad ignorando las habitaciones de la reserva dada
+	VerificarDisponibilidadExcluyendo(habitacionID int, fechaEntrada, fechaSalida time.Time, reservaID int) (bool, error)
+	// GetHabitacionesCandidatas obtiene las habitaciones en servicio del tipo que están libres
+	// durante todo el rango, con la ocupación más cercana antes y después de él
+	GetHabitacionesCandidatas(tipoHabitacionID int, fechaEntrada, fechaSalida time.Time) ([]HabitacionCandidata, error)
 	// GetReservasEnRango obtiene todas las reservas activas en un rango de fechas
 	GetReservasEnRango(fechaInicio, fechaFin time.Time) ([]ReservaHabitacion, error)
 }
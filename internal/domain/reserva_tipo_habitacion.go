@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Estados de una reserva por tipo de habitación
+const (
+	TipoReservaCancelada  = 0
+	TipoReservaPorAsignar = 1
+	TipoReservaAsignada   = 2 // sus habitaciones ya están en Reserva.Habitaciones
+)
+
+// ReservaTipoHabitacion reserva una cantidad de habitaciones de un tipo para un rango de fechas
+// sin elegir todavía las habitaciones concretas
+type ReservaTipoHabitacion struct {
+	ID               int             `json:"id"`
+	ReservaID        int             `json:"reservaId"`
+	TipoHabitacionID int             `json:"tipoHabitacionId"`
+	Cantidad         int             `json:"cantidad"`
+	Precio           float64         `json:"precio"` // precio promedio por noche de cada habitación
+	FechaEntrada     time.Time       `json:"fechaEntrada"`
+	FechaSalida      time.Time       `json:"fechaSalida"`
+	Estado           int             `json:"estado"`
+	Desglose         []PrecioNoche   `json:"desglose,omitempty"`
+	TipoHabitacion   *TipoHabitacion `json:"tipoHabitacion,omitempty"`
+}
+
+// Unidad retorna una habitación sin asignar del tipo, con el precio de la reserva por tipo
+func (t ReservaTipoHabitacion) Unidad() ReservaHabitacion {
+	unidad := ReservaHabitacion{
+		ReservaID:    t.ReservaID,
+		Precio:       t.Precio,
+		FechaEntrada: t.FechaEntrada,
+		FechaSalida:  t.FechaSalida,
+		Estado:       1,
+		Desglose:     t.Desglose,
+	}
+	if t.TipoHabitacion != nil {
+		unidad.Habitacion = &Habitacion{TipoHabitacion: *t.TipoHabitacion}
+	}
+	return unidad
+}
+
+// AsignacionHabitacion asigna una habitación concreta a una reserva por tipo
+type AsignacionHabitacion struct {
+	TipoReservaID int    `json:"tipoReservaId"`
+	HabitacionID  int    `json:"habitacionId"`
+	NombreHuesped string `json:"nombreHuesped,omitempty"`
+}
+
+// HabitacionCandidata es una habitación libre durante toda una estadía junto con la ocupación
+// más cercana antes y después, para elegir la que deja menos huecos
+type HabitacionCandidata struct {
+	HabitacionID    int
+	FinAnterior     *time.Time // salida de la reserva o fin del bloqueo anterior
+	InicioSiguiente *time.Time // entrada de la reserva o inicio del bloqueo siguiente
+}
+
+// ErrSinHabitacionAsignable indica que ninguna habitación del tipo está libre durante toda la estadía
+var ErrSinHabitacionAsignable = errors.New("no hay una habitación del tipo libre durante toda la estadía; asígnela manualmente")
+
+// TipoHabitacionAgotadoError indica que no quedan habitaciones del tipo para una noche
+type TipoHabitacionAgotadoError struct {
+	TipoHabitacionID int
+	Fecha            time.Time
+}
+
+func (e *TipoHabitacionAgotadoError) Error() string {
+	return fmt.Sprintf("no quedan habitaciones del tipo %d para la noche del %s", e.TipoHabitacionID, e.Fecha.Format("2006-01-02"))
+}
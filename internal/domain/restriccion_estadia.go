@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestriccionEstadia limita las estadías de un tipo de habitación en una fecha. La estadía mínima
+// y máxima aplican a las llegadas de la fecha; los valores 0 no restringen.
+type RestriccionEstadia struct {
+	ID               int       `json:"id"`
+	TipoHabitacionID *int      `json:"tipoHabitacionId,omitempty"` // nil aplica a todos los tipos
+	Fecha            time.Time `json:"fecha"`
+	EstanciaMinima   int       `json:"estanciaMinima"`
+	EstanciaMaxima   int       `json:"estanciaMaxima"`
+	CerradoLlegada   bool      `json:"cerradoLlegada"` // CTA: no se permiten llegadas
+	CerradoSalida    bool      `json:"cerradoSalida"`  // CTD: no se permiten salidas
+	Motivo           string    `json:"motivo,omitempty"`
+}
+
+// RestriccionesPorFecha indexa las restricciones por fecha y tipo de habitación (0 para las generales)
+type RestriccionesPorFecha map[string]map[int]RestriccionEstadia
+
+// NuevasRestriccionesPorFecha indexa las restricciones dadas
+func NuevasRestriccionesPorFecha(restricciones []RestriccionEstadia) RestriccionesPorFecha {
+	indice := make(RestriccionesPorFecha)
+	for _, r := range restricciones {
+		fecha := r.Fecha.Format("2006-01-02")
+		if indice[fecha] == nil {
+			indice[fecha] = make(map[int]RestriccionEstadia)
+		}
+		tipoID := 0
+		if r.TipoHabitacionID != nil {
+			tipoID = *r.TipoHabitacionID
+		}
+		indice[fecha][tipoID] = r
+	}
+	return indice
+}
+
+// Para retorna la restricción del tipo de habitación en la fecha o, si el tipo no tiene una
+// propia, la general. Retorna nil si la fecha no tiene restricciones.
+func (r RestriccionesPorFecha) Para(tipoHabitacionID int, fecha time.Time) *RestriccionEstadia {
+	porTipo := r[fecha.Format("2006-01-02")]
+	if restriccion, ok := porTipo[tipoHabitacionID]; ok {
+		return &restriccion
+	}
+	if restriccion, ok := porTipo[0]; ok {
+		return &restriccion
+	}
+	return nil
+}
+
+// Reglas de estadía que puede incumplir una reserva
+const (
+	ReglaEstanciaMinima = "estanciaMinima"
+	ReglaEstanciaMaxima = "estanciaMaxima"
+	ReglaCerradoLlegada = "cerradoLlegada"
+	ReglaCerradoSalida  = "cerradoSalida"
+)
+
+// RestriccionEstadiaError indica que la estadía incumple una restricción del tipo de habitación
+type RestriccionEstadiaError struct {
+	TipoHabitacionID int
+	TipoHabitacion   string
+	Fecha            time.Time
+	Regla            string
+	Noches           int // estadía mínima o máxima exigida
+}
+
+func (e *RestriccionEstadiaError) Error() string {
+	fecha := e.Fecha.Format("02/01/2006")
+	switch e.Regla {
+	case ReglaCerradoLlegada:
+		return fmt.Sprintf("no se permiten llegadas el %s para %s", fecha, e.TipoHabitacion)
+	case ReglaCerradoSalida:
+		return fmt.Sprintf("no se permiten salidas el %s para %s", fecha, e.TipoHabitacion)
+	case ReglaEstanciaMinima:
+		return fmt.Sprintf("las llegadas del %s para %s requieren una estadía mínima de %d noche(s)", fecha, e.TipoHabitacion, e.Noches)
+	default:
+		return fmt.Sprintf("las llegadas del %s para %s permiten una estadía máxima de %d noche(s)", fecha, e.TipoHabitacion, e.Noches)
+	}
+}
+
+// RestriccionEstadiaRepository define las operaciones disponibles con las restricciones de estadía
+type RestriccionEstadiaRepository interface {
+	// GetRestricciones obtiene las restricciones entre ambas fechas (inclusive). tipoHabitacionID 0
+	// incluye todos los tipos; en otro caso se incluyen también las generales.
+	GetRestricciones(desde, hasta time.Time, tipoHabitacionID int) ([]RestriccionEstadia, error)
+	// GuardarRestricciones crea las restricciones o reemplaza las existentes para la misma fecha y tipo
+	GuardarRestricciones(restricciones []RestriccionEstadia) error
+	// DeleteRestriccion elimina una restricción
+	DeleteRestriccion(id int) error
+}
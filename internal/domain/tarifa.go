@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TipoPlanTarifa indica cómo se aplica un plan de tarifa sobre el precio base
+type TipoPlanTarifa string
+
+const (
+	// PlanFinDeSemana ajusta el precio de las noches de viernes y sábado
+	PlanFinDeSemana TipoPlanTarifa = "FinDeSemana"
+	// PlanTemporada ajusta el precio de las noches dentro de un rango de fechas
+	PlanTemporada TipoPlanTarifa = "Temporada"
+	// PlanEstadia aplica un descuento a estadías con una cantidad mínima de noches
+	PlanEstadia TipoPlanTarifa = "Estadia"
+	// PlanOcupacion ajusta el precio de las noches según la ocupación prevista del tipo de habitación
+	PlanOcupacion TipoPlanTarifa = "Ocupacion"
+)
+
+// PlanTarifa representa una regla configurable que ajusta el precio base de un tipo de habitación
+type PlanTarifa struct {
+	ID               int            `json:"id"`
+	Nombre           string         `json:"nombre"`
+	Tipo             TipoPlanTarifa `json:"tipo"`
+	TipoHabitacionID *int           `json:"tipoHabitacionId,omitempty"` // nil aplica a todos los tipos
+	Porcentaje       float64        `json:"porcentaje"`                 // positivo recarga, negativo descuenta
+	FechaInicio      *time.Time     `json:"fechaInicio,omitempty"`
+	FechaFin         *time.Time     `json:"fechaFin,omitempty"`
+	MinNoches        int            `json:"minNoches"`
+	OcupacionMin     *float64       `json:"ocupacionMin,omitempty"`     // porcentaje, inclusivo
+	OcupacionMax     *float64       `json:"ocupacionMax,omitempty"`     // porcentaje, exclusivo
+	DiasAnticipacion *int           `json:"diasAnticipacion,omitempty"` // solo noches a lo sumo a N días de hoy
+	Activo           bool           `json:"activo"`
+}
+
+// Vigente indica si el plan aplica para la fecha dada según su rango de fechas (inclusivo)
+func (p PlanTarifa) Vigente(fecha time.Time) bool {
+	dia := fecha.Format("2006-01-02")
+	if p.FechaInicio != nil && dia < p.FechaInicio.Format("2006-01-02") {
+		return false
+	}
+	if p.FechaFin != nil && dia > p.FechaFin.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// AplicaOcupacion indica si el plan por ocupación aplica a una noche con el porcentaje de ocupación
+// dado, que está a diasAnticipacion días de hoy
+func (p PlanTarifa) AplicaOcupacion(ocupacion float64, diasAnticipacion int) bool {
+	if p.OcupacionMin != nil && ocupacion < *p.OcupacionMin {
+		return false
+	}
+	if p.OcupacionMax != nil && ocupacion >= *p.OcupacionMax {
+		return false
+	}
+	if p.DiasAnticipacion != nil && diasAnticipacion > *p.DiasAnticipacion {
+		return false
+	}
+	return true
+}
+
+// PrecioNoche representa el precio calculado para una noche de la estadía
+type PrecioNoche struct {
+	Fecha      time.Time `json:"fecha"`
+	PrecioBase float64   `json:"precioBase"`
+	Precio     float64   `json:"precio"`
+	Planes     []int     `json:"planes,omitempty"` // IDs de los planes de tarifa aplicados
+	// Ocupación prevista del tipo de habitación (porcentaje), si algún plan por ocupación la usa
+	Ocupacion *float64 `json:"ocupacion,omitempty"`
+}
+
+// Cotizacion es el resultado del motor de tarifas para un rango de fechas
+type Cotizacion struct {
+	HabitacionID     int           `json:"habitacionId,omitempty"`
+	TipoHabitacionID int           `json:"tipoHabitacionId"`
+	FechaEntrada     time.Time     `json:"fechaEntrada"`
+	FechaSalida      time.Time     `json:"fechaSalida"`
+	Noches           []PrecioNoche `json:"noches"`
+	PrecioPromedio   float64       `json:"precioPromedio"`
+	Total            float64       `json:"total"`
+}
+
+// PrecioNoCoincideError indica que el precio cotizado por el cliente no coincide con la tarifa vigente
+type PrecioNoCoincideError struct {
+	HabitacionID int
+	Cotizado     float64
+	Calculado    float64
+}
+
+func (e *PrecioNoCoincideError) Error() string {
+	return fmt.Sprintf("el precio cotizado para la habitación %d (%.2f) no coincide con la tarifa vigente (%.2f)",
+		e.HabitacionID, e.Cotizado, e.Calculado)
+}
+
+// TarifaRepository define las operaciones disponibles con los planes de tarifa
+type TarifaRepository interface {
+	// GetPlanes obtiene todos los planes de tarifa
+	GetPlanes() ([]PlanTarifa, error)
+	// GetPlanesActivos obtiene los planes activos que aplican al tipo de habitación
+	GetPlanesActivos(tipoHabitacionID int) ([]PlanTarifa, error)
+	// CreatePlan crea un nuevo plan de tarifa
+	CreatePlan(plan *PlanTarifa) error
+	// UpdatePlan actualiza un plan de tarifa existente
+	UpdatePlan(plan *PlanTarifa) error
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Roles de las sesiones. Los del personal determinan a qué rutas puede acceder cada usuario;
+// RolCliente identifica la sesión de un huésped.
+const (
+	RolAdmin     = "admin" // acceso a todas las rutas
+	RolRecepcion = "recepcion"
+	RolRevenue   = "revenue"
+	RolMarketing = "marketing"
+	RolCliente   = "cliente"
+)
+
+// Usuario es un miembro del personal del hotel
+type Usuario struct {
+	ID                 int        `json:"id"`
+	Email              string     `json:"email"`
+	Nombre             string     `json:"nombre"`
+	Rol                string     `json:"rol"`
+	Activo             bool       `json:"activo"`
+	HashPassword       string     `json:"-"`
+	VersionSesion      int        `json:"-"` // aumenta al cambiar rol, estado o contraseña
+	UltimoAcceso       *time.Time `json:"ultimoAcceso,omitempty"`
+	FechaCreacion      time.Time  `json:"fechaCreacion"`
+	FechaActualizacion time.Time  `json:"fechaActualizacion"`
+}
+
+// ErrUsuarioNoEncontrado indica que no existe el usuario
+var ErrUsuarioNoEncontrado = errors.New("usuario no encontrado")
+
+// ErrUsuarioDuplicado indica que ya existe un usuario con el email
+var ErrUsuarioDuplicado = errors.New("ya existe un usuario con ese email")
+
+// ErrSesionRevocada indica que el usuario de la sesión fue desactivado o cambió su rol o su
+// contraseña después de emitirse el token
+var ErrSesionRevocada = errors.New("la sesión fue revocada, inicie sesión nuevamente")
+
+// ErrCredencialesInvalidas indica un email o contraseña incorrectos, o un usuario inactivo
+var ErrCredencialesInvalidas = errors.New("email o contraseña incorrectos")
+
+// UsuarioRepository define las operaciones disponibles con los usuarios del personal
+type UsuarioRepository interface {
+	GetUsuarios() ([]Usuario, error)
+	// GetUsuarioByID obtiene un usuario. Retorna ErrUsuarioNoEncontrado si no existe.
+	GetUsuarioByID(id int) (*Usuario, error)
+	// GetUsuarioByEmail obtiene un usuario por su email. Retorna ErrUsuarioNoEncontrado si no existe.
+	GetUsuarioByEmail(email string) (*Usuario, error)
+	// CreateUsuario crea un usuario. Retorna ErrUsuarioDuplicado si el email ya está registrado.
+	CreateUsuario(usuario *Usuario) error
+	// UpdateUsuario actualiza el nombre, el rol y si está activo. Si cambia el rol o el estado
+	// revoca las sesiones del usuario. Retorna ErrUsuarioNoEncontrado si no existe.
+	UpdateUsuario(usuario *Usuario) error
+	// CambiarPassword reemplaza el hash de la contraseña del usuario y revoca sus sesiones
+	CambiarPassword(id int, hashPassword string) error
+	// RegistrarAcceso guarda la fecha del último inicio de sesión
+	RegistrarAcceso(id int, fecha time.Time) error
+}
@@ -3,7 +3,9 @@ package email
 import (
 	"crypto/tls"
 	"fmt"
+	"html"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/wneessen/go-mail"
@@ -91,8 +93,15 @@ type ReservaInfo struct {
 	FechaConfirmacion time.Time
 	Subtotal          float64
 	Descuento         float64
+	CargoServicio     float64
+	IGV               float64
+	ExoneradoIGV      bool
 	Total             float64
 	Habitaciones      []HabitacionInfo
+	// PoliticasCancelacion son los nombres de las políticas de cancelación de la reserva; si está
+	// vacío el correo no menciona la cancelación
+	PoliticasCancelacion []string
+	LimiteCancelacion    time.Time // último momento para cancelar sin costo
 }
 
 // HabitacionInfo contiene la información de una habitación
@@ -101,20 +110,83 @@ type HabitacionInfo struct {
 	Numero       string
 	FechaEntrada time.Time
 	FechaSalida  time.Time
-	Precio       float64
+	Precio       float64 // Precio promedio por noche
 	Noches       int
+	Total        float64 // Importe de la habitación por toda la estadía
 }
 
 // SendReservaConfirmacion envía un correo de confirmación de reserva
 func (c *Client) SendReservaConfirmacion(reserva ReservaInfo) error {
-	subject := fmt.Sprintf("Confirmación de Reserva #%d - %s", reserva.ID, c.fromName)
+	subject := fmt.Sprintf("Confirmación de Reserva %s - %s", reserva.CodigoReserva, c.fromName)
 	htmlBody := generarHTMLConfirmacion(reserva)
 
 	return c.SendEmail(reserva.ClienteEmail, subject, htmlBody)
 }
 
+// SendReservaModificada envía un correo con el detalle actualizado de una reserva modificada
+func (c *Client) SendReservaModificada(reserva ReservaInfo) error {
+	subject := fmt.Sprintf("Reserva %s actualizada - %s", reserva.CodigoReserva, c.fromName)
+	htmlBody := generarHTMLReserva(reserva, "Reserva Actualizada", "Estos son los nuevos detalles de su reserva")
+
+	return c.SendEmail(reserva.ClienteEmail, subject, htmlBody)
+}
+
+// SendReservaNoShow avisa al huésped que su reserva se marcó como no presentada y la penalidad
+// aplicada según la política de cancelación
+func (c *Client) SendReservaNoShow(reserva ReservaInfo, fechaLlegada time.Time, penalidad float64) error {
+	subject := fmt.Sprintf("Reserva %s no presentada - %s", reserva.CodigoReserva, c.fromName)
+	mensaje := fmt.Sprintf("No registramos su llegada el %s, por lo que su reserva fue marcada como no presentada y sus habitaciones fueron liberadas.",
+		fechaLlegada.Format("02/01/2006"))
+	if penalidad > 0 {
+		mensaje += fmt.Sprintf(" Según la política de cancelación de su reserva se aplicó una penalidad de S/ %.2f.", penalidad)
+	}
+	htmlBody := generarHTMLReserva(reserva, "Reserva No Presentada", mensaje)
+
+	return c.SendEmail(reserva.ClienteEmail, subject, htmlBody)
+}
+
+// SendOfertaListaEspera ofrece al huésped de la lista de espera la reserva retenida para él, con el
+// enlace para confirmarla antes de que venza
+func (c *Client) SendOfertaListaEspera(reserva ReservaInfo, enlace string, expiraEn time.Time) error {
+	subject := fmt.Sprintf("Hay disponibilidad para sus fechas - %s", c.fromName)
+	mensaje := fmt.Sprintf(`Se liberaron habitaciones para las fechas que solicitó en nuestra lista de espera y las retuvimos para usted hasta el %s.<br><br>
+		<a href="%s" style="display: inline-block; padding: 12px 24px; background-color: #667eea; color: #ffffff; text-decoration: none; border-radius: 4px;">Confirmar reserva</a>`,
+		expiraEn.Format("02/01/2006 15:04"), html.EscapeString(enlace))
+	htmlBody := generarHTMLReserva(reserva, "¡Hay Disponibilidad!", mensaje)
+
+	return c.SendEmail(reserva.ClienteEmail, subject, htmlBody)
+}
+
+// SendCodigoAcceso envía al cliente el código de un solo uso para iniciar sesión
+func (c *Client) SendCodigoAcceso(destinatario, codigo string, expiraEn time.Time) error {
+	subject := fmt.Sprintf("Su código de acceso es %s - %s", codigo, c.fromName)
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="es">
+<head>
+	<meta charset="UTF-8">
+</head>
+<body style="margin: 0; padding: 20px; font-family: Arial, sans-serif; background-color: #f4f4f4;">
+	<div style="max-width: 480px; margin: 0 auto; padding: 30px; background-color: #ffffff; border-radius: 8px; text-align: center;">
+		<h2 style="color: #333333;">Código de acceso</h2>
+		<p style="color: #555555;">Use este código para ingresar a su cuenta de %s:</p>
+		<p style="font-size: 32px; font-weight: bold; letter-spacing: 8px; color: #667eea;">%s</p>
+		<p style="color: #555555;">Vence el %s. Si no lo solicitó, ignore este correo.</p>
+	</div>
+</body>
+</html>`,
+		html.EscapeString(c.fromName), codigo, expiraEn.Format("02/01/2006 15:04"))
+
+	return c.SendEmail(destinatario, subject, htmlBody)
+}
+
 // generarHTMLConfirmacion genera el HTML del correo de confirmación
 func generarHTMLConfirmacion(reserva ReservaInfo) string {
+	return generarHTMLReserva(reserva, "¡Reserva Confirmada!", "Gracias por reservar con nosotros")
+}
+
+// generarHTMLReserva genera el HTML con el detalle de la reserva bajo el título y mensaje dados
+func generarHTMLReserva(reserva ReservaInfo, titulo, mensaje string) string {
 	// Generar lista de habitaciones
 	habitacionesHTML := ""
 	for _, hab := range reserva.Habitaciones {
@@ -134,7 +206,7 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 					%d noche(s)
 				</td>
 				<td style="padding: 15px; border-bottom: 1px solid #e0e0e0; text-align: right;">
-					$%.2f
+					S/ %.2f
 				</td>
 			</tr>
 		`,
@@ -143,10 +215,33 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 			hab.FechaEntrada.Format("02/01/2006"),
 			hab.FechaSalida.Format("02/01/2006"),
 			hab.Noches,
-			hab.Precio*float64(hab.Noches),
+			hab.Total,
 		)
 	}
 
+	// Generar líneas de cargo por servicio e IGV
+	cargosHTML := ""
+	if reserva.CargoServicio > 0 {
+		cargosHTML += fmt.Sprintf(`
+									<tr>
+										<td style="padding: 8px 0;"><strong>Cargo por servicio:</strong></td>
+										<td style="padding: 8px 0; text-align: right;">S/ %.2f</td>
+									</tr>`, reserva.CargoServicio)
+	}
+	if reserva.ExoneradoIGV {
+		cargosHTML += `
+									<tr>
+										<td style="padding: 8px 0;"><strong>IGV (18%):</strong></td>
+										<td style="padding: 8px 0; text-align: right;">Exonerado</td>
+									</tr>`
+	} else {
+		cargosHTML += fmt.Sprintf(`
+									<tr>
+										<td style="padding: 8px 0;"><strong>IGV (18%%):</strong></td>
+										<td style="padding: 8px 0; text-align: right;">S/ %.2f</td>
+									</tr>`, reserva.IGV)
+	}
+
 	// Plantilla HTML completa
 	html := fmt.Sprintf(`
 <!DOCTYPE html>
@@ -154,7 +249,7 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 <head>
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
-	<title>Confirmación de Reserva</title>
+	<title>%s</title>
 </head>
 <body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
 	<table width="100%%" cellpadding="0" cellspacing="0" style="background-color: #f4f4f4; padding: 20px;">
@@ -165,8 +260,8 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 					<!-- Header -->
 					<tr>
 						<td style="background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); padding: 40px 20px; text-align: center;">
-							<h1 style="color: #ffffff; margin: 0; font-size: 28px;">¡Reserva Confirmada!</h1>
-							<p style="color: #ffffff; margin: 10px 0 0 0; font-size: 16px;">Gracias por reservar con nosotros</p>
+							<h1 style="color: #ffffff; margin: 0; font-size: 28px;">%s</h1>
+							<p style="color: #ffffff; margin: 10px 0 0 0; font-size: 16px;">%s</p>
 						</td>
 					</tr>
 
@@ -178,8 +273,8 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 								<h2 style="margin: 0 0 15px 0; color: #333; font-size: 20px;">Detalles de la Reserva</h2>
 								<table width="100%%" cellpadding="0" cellspacing="0">
 									<tr>
-										<td style="padding: 8px 0;"><strong>ID de Reserva:</strong></td>
-										<td style="padding: 8px 0; text-align: right;">#%d</td>
+										<td style="padding: 8px 0;"><strong>Código de Reserva:</strong></td>
+										<td style="padding: 8px 0; text-align: right; font-family: monospace; font-size: 16px;"><strong>%s</strong></td>
 									</tr>
 									<tr>
 										<td style="padding: 8px 0;"><strong>Fecha de Confirmación:</strong></td>
@@ -214,15 +309,15 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 								<table width="100%%" cellpadding="0" cellspacing="0">
 									<tr>
 										<td style="padding: 8px 0;"><strong>Subtotal:</strong></td>
-										<td style="padding: 8px 0; text-align: right;">$%.2f</td>
+										<td style="padding: 8px 0; text-align: right;">S/ %.2f</td>
 									</tr>
 									<tr>
 										<td style="padding: 8px 0;"><strong>Descuento:</strong></td>
-										<td style="padding: 8px 0; text-align: right; color: #28a745;">-$%.2f</td>
-									</tr>
+										<td style="padding: 8px 0; text-align: right; color: #28a745;">-S/ %.2f</td>
+									</tr>%s
 									<tr style="border-top: 2px solid #667eea;">
 										<td style="padding: 15px 0 0 0;"><strong style="font-size: 18px;">Total:</strong></td>
-										<td style="padding: 15px 0 0 0; text-align: right;"><strong style="font-size: 24px; color: #667eea;">$%.2f</strong></td>
+										<td style="padding: 15px 0 0 0; text-align: right;"><strong style="font-size: 24px; color: #667eea;">S/ %.2f</strong></td>
 									</tr>
 								</table>
 							</div>
@@ -233,7 +328,7 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 								<ul style="margin: 0; padding-left: 20px; color: #856404;">
 									<li>Presentar este correo al momento del check-in</li>
 									<li>Check-in: 15:00 hrs | Check-out: 12:00 hrs</li>
-									<li>Para cancelaciones, contactar con 48 horas de anticipación</li>
+									%s
 								</ul>
 							</div>
 						</td>
@@ -257,7 +352,10 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 </body>
 </html>
 	`,
-		reserva.ID,
+		titulo,
+		titulo,
+		mensaje,
+		reserva.CodigoReserva,
 		reserva.FechaConfirmacion.Format("02/01/2006 15:04"),
 		reserva.CantidadAdultos,
 		func() string {
@@ -269,8 +367,24 @@ func generarHTMLConfirmacion(reserva ReservaInfo) string {
 		habitacionesHTML,
 		reserva.Subtotal,
 		reserva.Descuento,
+		cargosHTML,
 		reserva.Total,
+		lineaCancelacion(reserva),
 	)
 
 	return html
 }
+
+// lineaCancelacion describe el plazo de cancelación gratuita según las políticas de la reserva
+func lineaCancelacion(reserva ReservaInfo) string {
+	if len(reserva.PoliticasCancelacion) == 0 {
+		return ""
+	}
+
+	politicas := html.EscapeString(strings.Join(reserva.PoliticasCancelacion, ", "))
+	if reserva.LimiteCancelacion.After(time.Now()) {
+		return fmt.Sprintf("<li>Cancelación gratuita hasta el %s (política %s)</li>",
+			reserva.LimiteCancelacion.Format("02/01/2006 15:04"), politicas)
+	}
+	return fmt.Sprintf("<li>La cancelación tiene penalidad según la política %s</li>", politicas)
+}
@@ -0,0 +1,27 @@
+// Package horario reúne las fechas y horas del hotel, que se manejan en hora de Perú
+package horario
+
+import "time"
+
+// Peru es la zona horaria del hotel
+var Peru = cargarZonaPeru()
+
+func cargarZonaPeru() *time.Location {
+	zona, err := time.LoadLocation("America/Lima")
+	if err != nil {
+		// Fallback a UTC-5 (Perú no usa horario de verano)
+		return time.FixedZone("PET", -5*60*60)
+	}
+	return zona
+}
+
+// HoyPeru retorna la fecha de hoy a las 00:00:00 en zona horaria de Perú
+func HoyPeru() time.Time {
+	return FechaPeru(time.Now().In(Peru))
+}
+
+// FechaPeru retorna el día calendario de la fecha a las 00:00:00 en zona horaria de Perú, para
+// comparar con HoyPeru fechas YYYY-MM-DD parseadas en otra zona
+func FechaPeru(fecha time.Time) time.Time {
+	return time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, Peru)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type accesoClienteRepository struct {
+	db *sql.DB
+}
+
+// NewAccesoClienteRepository crea una nueva instancia del repositorio de códigos de acceso
+func NewAccesoClienteRepository(db *sql.DB) domain.AccesoClienteRepository {
+	return &accesoClienteRepository{db: db}
+}
+
+// CrearCodigo registra el código e invalida los anteriores del email en una transacción. Las
+// solicitudes del mismo email se serializan para que el límite no se supere en paralelo.
+func (r *accesoClienteRepository) CrearCodigo(codigo *domain.CodigoAcceso, limite domain.LimiteSolicitudesCodigo) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('client_login_code:' || $1))`, codigo.Email); err != nil {
+		return fmt.Errorf("error al bloquear solicitudes de código: %w", err)
+	}
+
+	var porEmail, porIP int
+	err = tx.QueryRow(`
+		SELECT
+			COUNT(*) FILTER (WHERE email = $1),
+			COUNT(*) FILTER (WHERE requested_ip = $2)
+		FROM client_login_code
+		WHERE (email = $1 OR requested_ip = $2) AND created_at > $3`,
+		codigo.Email, codigo.IPSolicitud, limite.Desde,
+	).Scan(&porEmail, &porIP)
+	if err != nil {
+		return fmt.Errorf("error al contar solicitudes de código: %w", err)
+	}
+	if porEmail >= limite.PorEmail || porIP >= limite.PorIP {
+		return domain.ErrDemasiadasSolicitudesCodigo
+	}
+
+	_, err = tx.Exec(`
+		UPDATE client_login_code
+		SET used_at = NOW()
+		WHERE email = $1 AND used_at IS NULL`, codigo.Email)
+	if err != nil {
+		return fmt.Errorf("error al invalidar códigos de acceso: %w", err)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO client_login_code (email, code_hash, expires_at, requested_ip)
+		VALUES ($1, $2, $3, $4)
+		RETURNING login_code_id`,
+		codigo.Email, codigo.HashCodigo, codigo.ExpiraEn, codigo.IPSolicitud,
+	).Scan(&codigo.ID)
+	if err != nil {
+		return fmt.Errorf("error al crear código de acceso: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetCodigoVigente obtiene el último código sin usar ni vencer del email
+func (r *accesoClienteRepository) GetCodigoVigente(email string, ahora time.Time) (*domain.CodigoAcceso, error) {
+	query := `
+		SELECT login_code_id, email, code_hash, expires_at, attempts, requested_ip
+		FROM client_login_code
+		WHERE email = $1 AND used_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var codigo domain.CodigoAcceso
+	err := r.db.QueryRow(query, email, ahora).Scan(
+		&codigo.ID,
+		&codigo.Email,
+		&codigo.HashCodigo,
+		&codigo.ExpiraEn,
+		&codigo.Intentos,
+		&codigo.IPSolicitud,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCodigoAccesoInvalido
+		}
+		return nil, fmt.Errorf("error al obtener código de acceso: %w", err)
+	}
+
+	return &codigo, nil
+}
+
+// RegistrarIntento suma una verificación al código en la misma sentencia que revisa el límite,
+// para que las verificaciones en paralelo no lo superen
+func (r *accesoClienteRepository) RegistrarIntento(id int, maxIntentos int) error {
+	var intentos int
+	err := r.db.QueryRow(`
+		UPDATE client_login_code
+		SET attempts = attempts + 1
+		WHERE login_code_id = $1 AND attempts < $2 AND used_at IS NULL
+		RETURNING attempts`, id, maxIntentos,
+	).Scan(&intentos)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrCodigoAccesoInvalido
+		}
+		return fmt.Errorf("error al registrar intento de acceso: %w", err)
+	}
+	return nil
+}
+
+// MarcarUsado consume el código si nadie lo usó antes
+func (r *accesoClienteRepository) MarcarUsado(id int, ahora time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE client_login_code
+		SET used_at = $2
+		WHERE login_code_id = $1 AND used_at IS NULL`, id, ahora)
+	if err != nil {
+		return fmt.Errorf("error al usar código de acceso: %w", err)
+	}
+
+	filas, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar código de acceso: %w", err)
+	}
+	if filas == 0 {
+		return domain.ErrCodigoAccesoInvalido
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type bloqueoHabitacionRepository struct {
+	db *sql.DB
+}
+
+// NewBloqueoHabitacionRepository crea una nueva instancia del repositorio de bloqueos de habitaciones
+func NewBloqueoHabitacionRepository(db *sql.DB) domain.BloqueoHabitacionRepository {
+	return &bloqueoHabitacionRepository{db: db}
+}
+
+// condicionSinBloqueo retorna la condición SQL que se cumple si la habitación no tiene bloqueos
+// que se solapen con el rango [entrada, salida). Los argumentos son expresiones SQL.
+func condicionSinBloqueo(habitacion, entrada, salida string) string {
+	return `NOT EXISTS (
+				SELECT 1 FROM room_block b
+				WHERE b.room_id = ` + habitacion + `
+				AND b.start_date < cast(` + salida + ` as date)
+				AND b.end_date > cast(` + entrada + ` as date)
+			)`
+}
+
+const columnasBloqueo = `
+			block_id,
+			room_id,
+			start_date,
+			end_date,
+			reason,
+			created_at`
+
+// GetBloqueos obtiene los bloqueos que se solapan con el rango dado
+func (r *bloqueoHabitacionRepository) GetBloqueos(habitacionID int, desde, hasta time.Time) ([]domain.BloqueoHabitacion, error) {
+	query := `SELECT` + columnasBloqueo + `
+		FROM room_block
+		WHERE ($1::int = 0 OR room_id = $1::int)
+		AND start_date < cast($3 as date)
+		AND end_date > cast($2 as date)
+		ORDER BY start_date, room_id`
+
+	rows, err := r.db.Query(query, habitacionID, desde, hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener bloqueos: %w", err)
+	}
+	defer rows.Close()
+
+	bloqueos := make([]domain.BloqueoHabitacion, 0)
+	for rows.Next() {
+		bloqueo, err := scanBloqueo(rows)
+		if err != nil {
+			return nil, err
+		}
+		bloqueos = append(bloqueos, *bloqueo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar bloqueos: %w", err)
+	}
+
+	return bloqueos, nil
+}
+
+// GetBloqueoByID obtiene un bloqueo por su ID
+func (r *bloqueoHabitacionRepository) GetBloqueoByID(id int) (*domain.BloqueoHabitacion, error) {
+	query := `SELECT` + columnasBloqueo + `
+		FROM room_block
+		WHERE block_id = $1`
+
+	bloqueo, err := scanBloqueo(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("bloqueo con ID %d no encontrado", id)
+	}
+	return bloqueo, err
+}
+
+// CreateBloqueo crea un bloqueo verificando que no haya reservas activas en el rango. La
+// habitación se bloquea con el mismo lock que toman las reservas, así ninguna reserva puede
+// colarse entre la verificación y la inserción.
+func (r *bloqueoHabitacionRepository) CreateBloqueo(bloqueo *domain.BloqueoHabitacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearHabitacion(tx, bloqueo.HabitacionID); err != nil {
+		return err
+	}
+
+	if err := verificarBloqueoSinReservas(tx, bloqueo); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO room_block (room_id, start_date, end_date, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING block_id, created_at`
+
+	err = tx.QueryRow(query, bloqueo.HabitacionID, bloqueo.FechaInicio, bloqueo.FechaFin, bloqueo.Motivo).
+		Scan(&bloqueo.ID, &bloqueo.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error al crear bloqueo: %w", err)
+	}
+
+	// Sacar la habitación de la venta no debe dejar sin cupo a las reservas por tipo
+	if err := verificarCapacidadHabitacion(tx, bloqueo.HabitacionID, bloqueo.FechaInicio, &bloqueo.FechaFin); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateBloqueo actualiza un bloqueo verificando que no haya reservas activas en el nuevo rango
+func (r *bloqueoHabitacionRepository) UpdateBloqueo(bloqueo *domain.BloqueoHabitacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearHabitacion(tx, bloqueo.HabitacionID); err != nil {
+		return err
+	}
+
+	if err := verificarBloqueoSinReservas(tx, bloqueo); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE room_block
+		SET room_id = $1,
+			start_date = $2,
+			end_date = $3,
+			reason = $4
+		WHERE block_id = $5
+		RETURNING created_at`
+
+	err = tx.QueryRow(query, bloqueo.HabitacionID, bloqueo.FechaInicio, bloqueo.FechaFin, bloqueo.Motivo, bloqueo.ID).
+		Scan(&bloqueo.FechaCreacion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("bloqueo con ID %d no encontrado", bloqueo.ID)
+		}
+		return fmt.Errorf("error al actualizar bloqueo: %w", err)
+	}
+
+	if err := verificarCapacidadHabitacion(tx, bloqueo.HabitacionID, bloqueo.FechaInicio, &bloqueo.FechaFin); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteBloqueo elimina un bloqueo
+func (r *bloqueoHabitacionRepository) DeleteBloqueo(id int) error {
+	result, err := r.db.Exec(`DELETE FROM room_block WHERE block_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error al eliminar bloqueo: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("bloqueo con ID %d no encontrado", id)
+	}
+
+	return nil
+}
+
+// verificarBloqueoSinReservas retorna *domain.BloqueoConReservasError con las reservas activas de
+// la habitación que se solapan con el bloqueo
+func verificarBloqueoSinReservas(tx *sql.Tx, bloqueo *domain.BloqueoHabitacion) error {
+	query := `
+		SELECT DISTINCT rh.reservation_id
+		FROM reservation_room rh
+		INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+		WHERE rh.room_id = $1
+		AND rh.status = 1
+		AND ` + condicionReservaVigente + `
+		AND rh.check_in_date < $3
+		AND rh.check_out_date > $2
+		ORDER BY rh.reservation_id`
+
+	rows, err := tx.Query(query, bloqueo.HabitacionID, bloqueo.FechaInicio, bloqueo.FechaFin)
+	if err != nil {
+		return fmt.Errorf("error al verificar reservas del bloqueo: %w", err)
+	}
+	defer rows.Close()
+
+	var reservas []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("error al escanear reserva: %w", err)
+		}
+		reservas = append(reservas, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error al iterar reservas: %w", err)
+	}
+
+	if len(reservas) > 0 {
+		return &domain.BloqueoConReservasError{HabitacionID: bloqueo.HabitacionID, Reservas: reservas}
+	}
+	return nil
+}
+
+func scanBloqueo(row rowScanner) (*domain.BloqueoHabitacion, error) {
+	var bloqueo domain.BloqueoHabitacion
+	err := row.Scan(
+		&bloqueo.ID,
+		&bloqueo.HabitacionID,
+		&bloqueo.FechaInicio,
+		&bloqueo.FechaFin,
+		&bloqueo.Motivo,
+		&bloqueo.FechaCreacion,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error al escanear bloqueo: %w", err)
+	}
+	return &bloqueo, nil
+}
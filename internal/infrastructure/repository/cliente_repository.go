@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type clienteRepository struct {
+	db *sql.DB
+}
+
+// NewClienteRepository crea una nueva instancia del repositorio de clientes
+func NewClienteRepository(db *sql.DB) domain.ClienteRepository {
+	return &clienteRepository{db: db}
+}
+
+const columnasCliente = `
+			client_profile_id,
+			email,
+			COALESCE(full_name, ''),
+			COALESCE(phone, ''),
+			COALESCE(document_type, ''),
+			COALESCE(document_number, ''),
+			COALESCE(nationality, ''),
+			COALESCE(preferences, ''),
+			COALESCE(notes, ''),
+			created_at,
+			updated_at`
+
+// scanCliente escanea una fila seleccionada con columnasCliente
+func scanCliente(row rowScanner) (*domain.Cliente, error) {
+	var cliente domain.Cliente
+	err := row.Scan(
+		&cliente.ID,
+		&cliente.Email,
+		&cliente.Nombre,
+		&cliente.Telefono,
+		&cliente.TipoDocumento,
+		&cliente.NumeroDocumento,
+		&cliente.Nacionalidad,
+		&cliente.Preferencias,
+		&cliente.Notas,
+		&cliente.FechaRegistro,
+		&cliente.FechaActualizacion,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cliente, nil
+}
+
+// GetClientes lista los clientes cuyo nombre o email contiene busqueda
+func (r *clienteRepository) GetClientes(busqueda string) ([]domain.Cliente, error) {
+	query := `SELECT` + columnasCliente + `
+		FROM client_profile
+		WHERE $1 = '' OR email ILIKE '%' || $1 || '%' OR full_name ILIKE '%' || $1 || '%'
+		ORDER BY full_name NULLS LAST, email`
+
+	rows, err := r.db.Query(query, strings.TrimSpace(busqueda))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener clientes: %w", err)
+	}
+	defer rows.Close()
+
+	clientes := []domain.Cliente{}
+	for rows.Next() {
+		cliente, err := scanCliente(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear cliente: %w", err)
+		}
+		clientes = append(clientes, *cliente)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer clientes: %w", err)
+	}
+
+	return clientes, nil
+}
+
+// GetClienteByID obtiene un cliente por su ID
+func (r *clienteRepository) GetClienteByID(id int) (*domain.Cliente, error) {
+	query := `SELECT` + columnasCliente + `
+		FROM client_profile
+		WHERE client_profile_id = $1`
+
+	cliente, err := scanCliente(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrClienteNoEncontrado
+		}
+		return nil, fmt.Errorf("error al obtener cliente: %w", err)
+	}
+
+	return cliente, nil
+}
+
+// GetClienteByEmail obtiene un cliente por su email
+func (r *clienteRepository) GetClienteByEmail(email string) (*domain.Cliente, error) {
+	query := `SELECT` + columnasCliente + `
+		FROM client_profile
+		WHERE email = $1`
+
+	cliente, err := scanCliente(r.db.QueryRow(query, strings.ToLower(strings.TrimSpace(email))))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrClienteNoEncontrado
+		}
+		return nil, fmt.Errorf("error al obtener cliente: %w", err)
+	}
+
+	return cliente, nil
+}
+
+// VincularCliente retorna el ID del cliente con el email, creándolo si no existe
+func (r *clienteRepository) VincularCliente(datos domain.Cliente) (int, error) {
+	return vincularCliente(r.db, datos)
+}
+
+// CreateCliente crea un cliente
+func (r *clienteRepository) CreateCliente(cliente *domain.Cliente) error {
+	query := `
+		INSERT INTO client_profile (
+			email, full_name, phone, document_type, document_number, nationality, preferences, notes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING client_profile_id, created_at, updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		cliente.Email,
+		nullString(cliente.Nombre),
+		nullString(cliente.Telefono),
+		nullString(cliente.TipoDocumento),
+		nullString(cliente.NumeroDocumento),
+		nullString(cliente.Nacionalidad),
+		nullString(cliente.Preferencias),
+		nullString(cliente.Notas),
+	).Scan(&cliente.ID, &cliente.FechaRegistro, &cliente.FechaActualizacion)
+	if err != nil {
+		if esEmailDuplicado(err) {
+			return domain.ErrClienteDuplicado
+		}
+		return fmt.Errorf("error al crear cliente: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCliente actualiza todos los datos de un cliente
+func (r *clienteRepository) UpdateCliente(cliente *domain.Cliente) error {
+	query := `
+		UPDATE client_profile
+		SET email = $1,
+			full_name = $2,
+			phone = $3,
+			document_type = $4,
+			document_number = $5,
+			nationality = $6,
+			preferences = $7,
+			notes = $8,
+			updated_at = NOW()
+		WHERE client_profile_id = $9
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		cliente.Email,
+		nullString(cliente.Nombre),
+		nullString(cliente.Telefono),
+		nullString(cliente.TipoDocumento),
+		nullString(cliente.NumeroDocumento),
+		nullString(cliente.Nacionalidad),
+		nullString(cliente.Preferencias),
+		nullString(cliente.Notas),
+		cliente.ID,
+	).Scan(&cliente.FechaRegistro, &cliente.FechaActualizacion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrClienteNoEncontrado
+		}
+		if esEmailDuplicado(err) {
+			return domain.ErrClienteDuplicado
+		}
+		return fmt.Errorf("error al actualizar cliente: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistorial obtiene las reservas del cliente con sus fechas y los totales de sus estadías
+// completadas. Las fechas de una reserva son las de sus habitaciones activas o, si ya no tiene
+// (cancelada, no-show), las que tuvo al reservarse.
+func (r *clienteRepository) GetHistorial(id int) (*domain.HistorialCliente, error) {
+	cliente, err := r.GetClienteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			r.reservation_id,
+			r.code,
+			r.status,
+			f.entrada,
+			f.salida,
+			(SELECT COUNT(*) FROM reservation_room rh
+				WHERE rh.reservation_id = r.reservation_id AND rh.status = 1)
+			+ (SELECT COALESCE(SUM(rt.quantity), 0) FROM reservation_room_type rt
+				WHERE rt.reservation_id = r.reservation_id AND rt.status = $2),
+			COALESCE(r.total, r.subtotal - r.discount)
+		FROM reservation r
+		CROSS JOIN LATERAL (
+			SELECT
+				COALESCE(MIN(check_in_date) FILTER (WHERE activa), MIN(check_in_date)) AS entrada,
+				COALESCE(MAX(check_out_date) FILTER (WHERE activa), MAX(check_out_date)) AS salida
+			FROM (
+				SELECT check_in_date, check_out_date, status = 1 AS activa
+				FROM reservation_room
+				WHERE reservation_id = r.reservation_id
+				UNION ALL
+				SELECT check_in_date, check_out_date, status = $2 AS activa
+				FROM reservation_room_type
+				WHERE reservation_id = r.reservation_id
+			) fechas
+		) f
+		WHERE r.client_profile_id = $1
+		ORDER BY f.entrada DESC NULLS LAST, r.reservation_id DESC`
+
+	rows, err := r.db.Query(query, id, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial del cliente: %w", err)
+	}
+	defer rows.Close()
+
+	historial := &domain.HistorialCliente{
+		Cliente:  *cliente,
+		Reservas: []domain.EstadiaCliente{},
+	}
+	for rows.Next() {
+		var estadia domain.EstadiaCliente
+		var entrada, salida sql.NullTime
+		if err := rows.Scan(
+			&estadia.ReservaID,
+			&estadia.CodigoReserva,
+			&estadia.Estado,
+			&entrada,
+			&salida,
+			&estadia.Habitaciones,
+			&estadia.Total,
+		); err != nil {
+			return nil, fmt.Errorf("error al escanear reserva del cliente: %w", err)
+		}
+
+		if entrada.Valid && salida.Valid {
+			estadia.FechaEntrada = &entrada.Time
+			estadia.FechaSalida = &salida.Time
+			estadia.Noches = int(salida.Time.Sub(entrada.Time).Hours() / 24)
+		}
+
+		if estadia.Estado == domain.ReservaCompletada {
+			historial.Estadias++
+			historial.Noches += estadia.Noches
+			historial.GastoTotal += estadia.Total
+			if estadia.FechaSalida != nil && (historial.UltimaEstadia == nil || estadia.FechaSalida.After(*historial.UltimaEstadia)) {
+				historial.UltimaEstadia = estadia.FechaSalida
+			}
+		}
+
+		historial.Reservas = append(historial.Reservas, estadia)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer historial del cliente: %w", err)
+	}
+
+	return historial, nil
+}
+
+// consultor es lo que vincularCliente necesita de *sql.DB o *sql.Tx
+type consultor interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// vincularCliente retorna el ID del perfil con el email de datos, creándolo si no existe. Los
+// datos solo completan los campos que el perfil aún no tiene; nunca sobrescriben los registrados.
+func vincularCliente(q consultor, datos domain.Cliente) (int, error) {
+	query := `
+		INSERT INTO client_profile (email, full_name, phone, document_type, document_number, nationality)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (email) DO UPDATE SET
+			full_name = COALESCE(client_profile.full_name, EXCLUDED.full_name),
+			phone = COALESCE(client_profile.phone, EXCLUDED.phone),
+			document_type = COALESCE(client_profile.document_type, EXCLUDED.document_type),
+			document_number = COALESCE(client_profile.document_number, EXCLUDED.document_number),
+			nationality = COALESCE(client_profile.nationality, EXCLUDED.nationality),
+			updated_at = NOW()
+		RETURNING client_profile_id`
+
+	var id int
+	err := q.QueryRow(
+		query,
+		strings.ToLower(strings.TrimSpace(datos.Email)),
+		nullString(datos.Nombre),
+		nullString(datos.Telefono),
+		nullString(datos.TipoDocumento),
+		nullString(datos.NumeroDocumento),
+		nullString(datos.Nacionalidad),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error al vincular perfil del cliente: %w", err)
+	}
+
+	return id, nil
+}
+
+// esEmailDuplicado indica si err es la violación de la restricción única del email del perfil
+func esEmailDuplicado(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == codigoUniqueViolation && pqErr.Constraint == "client_profile_email_key"
+}
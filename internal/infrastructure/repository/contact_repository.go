@@ -21,23 +21,43 @@ func NewContactRepository(db *sql.DB) ContactRepository {
 	return &contactRepository{db: db}
 }
 
+// Create registra el formulario vinculado al perfil del cliente con su email, creándolo si es
+// la primera vez que escribe
 func (r *contactRepository) Create(ctx context.Context, req domain.CreateContactRequest) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	datos := domain.Cliente{Email: req.Email, Nombre: req.Nombre}
+	if req.Telefono != nil {
+		datos.Telefono = *req.Telefono
+	}
+	clienteID, err := vincularCliente(tx, datos)
+	if err != nil {
+		return 0, err
+	}
+
 	query := `
-    INSERT INTO contact_form (name, email, phone, message, status)
-    VALUES ($1, $2, $3, $4, 'Nuevo')
+    INSERT INTO contact_form (name, email, phone, message, status, client_profile_id)
+    VALUES ($1, $2, $3, $4, 'Nuevo', $5)
     RETURNING form_id
 `
 
 	var id int64
-	err := r.db.QueryRowContext(ctx, query,
-		req.Nombre, req.Email, req.Telefono, req.Mensaje,
+	err = tx.QueryRowContext(ctx, query,
+		req.Nombre, req.Email, req.Telefono, req.Mensaje, clienteID,
 	).Scan(&id)
-	return id, err
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
 }
 
 func (r *contactRepository) List(ctx context.Context) ([]domain.Contact, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT form_id, name, email, phone, message, status, sent_date, response_date
+		SELECT form_id, name, email, phone, message, status, sent_date, response_date, client_profile_id
 		FROM contact_form ORDER BY sent_date DESC`)
 	if err != nil {
 		return nil, err
@@ -49,7 +69,7 @@ func (r *contactRepository) List(ctx context.Context) ([]domain.Contact, error)
 		var c domain.Contact
 		if err := rows.Scan(
 			&c.ID, &c.Nombre, &c.Email, &c.Telefono,
-			&c.Mensaje, &c.Estado, &c.FechaEnvio, &c.FechaRespuesta,
+			&c.Mensaje, &c.Estado, &c.FechaEnvio, &c.FechaRespuesta, &c.ClienteID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type grupoRepository struct {
+	db *sql.DB
+}
+
+// NewGrupoRepository crea una nueva instancia del repositorio de grupos
+func NewGrupoRepository(db *sql.DB) domain.GrupoRepository {
+	return &grupoRepository{db: db}
+}
+
+const columnasGrupo = `
+			group_id,
+			name,
+			contact_email,
+			reservation_id,
+			release_date,
+			released_at,
+			created_at`
+
+// scanGrupo escanea una fila seleccionada con columnasGrupo
+func scanGrupo(row rowScanner) (*domain.Grupo, error) {
+	var grupo domain.Grupo
+	var liberado sql.NullTime
+
+	err := row.Scan(
+		&grupo.ID,
+		&grupo.Nombre,
+		&grupo.EmailContacto,
+		&grupo.ReservaID,
+		&grupo.FechaLiberacion,
+		&liberado,
+		&grupo.FechaCreacion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if liberado.Valid {
+		grupo.FechaLiberado = &liberado.Time
+	}
+	return &grupo, nil
+}
+
+// CreateGrupo registra el grupo de una reserva ya creada
+func (r *grupoRepository) CreateGrupo(grupo *domain.Grupo) error {
+	query := `
+		INSERT INTO reservation_group (name, contact_email, reservation_id, release_date)
+		VALUES ($1, $2, $3, $4)
+		RETURNING group_id, created_at`
+
+	err := r.db.QueryRow(query, grupo.Nombre, grupo.EmailContacto, grupo.ReservaID, grupo.FechaLiberacion).
+		Scan(&grupo.ID, &grupo.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error al crear grupo: %w", err)
+	}
+
+	return nil
+}
+
+// GetGrupos lista los grupos por fecha de liberación
+func (r *grupoRepository) GetGrupos() ([]domain.Grupo, error) {
+	return r.listarGrupos(`SELECT` + columnasGrupo + `
+		FROM reservation_group
+		ORDER BY release_date, group_id`)
+}
+
+// GetGrupoByID obtiene un grupo por su ID
+func (r *grupoRepository) GetGrupoByID(id int) (*domain.Grupo, error) {
+	query := `SELECT` + columnasGrupo + `
+		FROM reservation_group
+		WHERE group_id = $1`
+
+	grupo, err := scanGrupo(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrGrupoNoEncontrado
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener grupo: %w", err)
+	}
+
+	return grupo, nil
+}
+
+// GetGruposPorLiberar obtiene los grupos con el cupo aún retenido cuya fecha de liberación es a
+// más tardar hoy
+func (r *grupoRepository) GetGruposPorLiberar(hoy time.Time) ([]domain.Grupo, error) {
+	return r.listarGrupos(`SELECT`+columnasGrupo+`
+		FROM reservation_group
+		WHERE released_at IS NULL
+		AND release_date <= cast($1 as date)
+		ORDER BY release_date, group_id`, hoy.Format("2006-01-02"))
+}
+
+// MarcarLiberado registra que el cupo no asignado del grupo se devolvió al inventario
+func (r *grupoRepository) MarcarLiberado(id int, fecha time.Time) error {
+	_, err := r.db.Exec(`UPDATE reservation_group SET released_at = $1 WHERE group_id = $2`, fecha, id)
+	if err != nil {
+		return fmt.Errorf("error al marcar grupo como liberado: %w", err)
+	}
+
+	return nil
+}
+
+// listarGrupos ejecuta una consulta que selecciona columnasGrupo
+func (r *grupoRepository) listarGrupos(query string, args ...interface{}) ([]domain.Grupo, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener grupos: %w", err)
+	}
+	defer rows.Close()
+
+	grupos := make([]domain.Grupo, 0)
+	for rows.Next() {
+		grupo, err := scanGrupo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear grupo: %w", err)
+		}
+		grupos = append(grupos, *grupo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar grupos: %w", err)
+	}
+
+	return grupos, nil
+}
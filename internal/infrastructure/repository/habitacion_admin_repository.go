@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+// GetRoomTypeByID implementa domain.HabitacionRepository
+func (r *habitacionRepository) GetRoomTypeByID(id int) (*domain.TipoHabitacion, error) {
+	query := `
+		SELECT 
+			room_type_id,
+			title,
+			description,
+			adult_capacity,
+			children_capacity,
+			beds_count,
+			price,
+			min_price,
+			max_price,
+			amenities
+		FROM 
+			room_type
+		WHERE 
+			room_type_id = $1
+			AND active = true`
+
+	var t domain.TipoHabitacion
+	err := r.db.QueryRow(query, id).Scan(
+		&t.ID,
+		&t.Titulo,
+		&t.Descripcion,
+		&t.CapacidadAdultos,
+		&t.CapacidadNinhos,
+		&t.CantidadCamas,
+		&t.Precio,
+		&t.PrecioMinimo,
+		&t.PrecioMaximo,
+		pq.Array(&t.Amenidades),
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tipo de habitación con ID %d no encontrado", id)
+		}
+		return nil, fmt.Errorf("error al obtener tipo de habitación: %w", err)
+	}
+
+	return &t, nil
+}
+
+// CreateRoom implementa domain.HabitacionRepository
+func (r *habitacionRepository) CreateRoom(habitacion *domain.Habitacion) error {
+	query := `
+		INSERT INTO room (name, number, capacity, status, general_description, room_type_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING room_id`
+
+	err := r.db.QueryRow(
+		query,
+		habitacion.Nombre,
+		habitacion.Numero,
+		habitacion.Capacidad,
+		habitacion.Estado,
+		habitacion.DescripcionGeneral,
+		habitacion.TipoHabitacion.ID,
+	).Scan(&habitacion.ID)
+	if err != nil {
+		return errorHabitacion(err, "crear")
+	}
+
+	return nil
+}
+
+// UpdateRoom implementa domain.HabitacionRepository.
+// La habitación se bloquea para que no se reserve mientras se verifica si puede salir de servicio.
+func (r *habitacionRepository) UpdateRoom(habitacion *domain.Habitacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearHabitacion(tx, habitacion.ID); err != nil {
+		return err
+	}
+
+	if habitacion.Estado != domain.HabitacionDisponible {
+		if err := verificarSinReservasFuturas(tx, habitacion.ID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE room
+		SET name = $1,
+			number = $2,
+			capacity = $3,
+			status = $4,
+			general_description = $5,
+			room_type_id = $6
+		WHERE room_id = $7`
+
+	_, err = tx.Exec(
+		query,
+		habitacion.Nombre,
+		habitacion.Numero,
+		habitacion.Capacidad,
+		habitacion.Estado,
+		habitacion.DescripcionGeneral,
+		habitacion.TipoHabitacion.ID,
+		habitacion.ID,
+	)
+	if err != nil {
+		return errorHabitacion(err, "actualizar")
+	}
+
+	return tx.Commit()
+}
+
+// DeactivateRoom implementa domain.HabitacionRepository
+func (r *habitacionRepository) DeactivateRoom(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearHabitacion(tx, id); err != nil {
+		return err
+	}
+
+	if err := verificarSinReservasFuturas(tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE room SET status = $1 WHERE room_id = $2`, domain.HabitacionFueraDeServicio, id)
+	if err != nil {
+		return fmt.Errorf("error al desactivar habitación: %w", err)
+	}
+
+	// Sin esta habitación deben seguir alcanzando las del tipo para sus reservas por tipo pendientes
+	if err := verificarCapacidadHabitacion(tx, id, time.Now(), nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateEstadoLimpieza implementa domain.HabitacionRepository
+func (r *habitacionRepository) UpdateEstadoLimpieza(id int, estado string) error {
+	result, err := r.db.Exec(`UPDATE room SET housekeeping_status = $1 WHERE room_id = $2`, estado, id)
+	if err != nil {
+		return fmt.Errorf("error al actualizar estado de limpieza: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("habitación con ID %d no encontrada", id)
+	}
+
+	return nil
+}
+
+// CreateRoomType implementa domain.HabitacionRepository
+func (r *habitacionRepository) CreateRoomType(tipo *domain.TipoHabitacion) error {
+	query := `
+		INSERT INTO room_type (title, description, adult_capacity, children_capacity, beds_count, price, min_price, max_price, amenities)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING room_type_id`
+
+	err := r.db.QueryRow(
+		query,
+		tipo.Titulo,
+		tipo.Descripcion,
+		tipo.CapacidadAdultos,
+		tipo.CapacidadNinhos,
+		tipo.CantidadCamas,
+		tipo.Precio,
+		tipo.PrecioMinimo,
+		tipo.PrecioMaximo,
+		pq.Array(tipo.Amenidades),
+	).Scan(&tipo.ID)
+	if err != nil {
+		return fmt.Errorf("error al crear tipo de habitación: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRoomType implementa domain.HabitacionRepository.
+// Las habitaciones del tipo se bloquean para que su capacidad no cambie durante la verificación.
+func (r *habitacionRepository) UpdateRoomType(tipo *domain.TipoHabitacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var capacidadMaxima int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(capacity), 0)
+		FROM (SELECT capacity FROM room WHERE room_type_id = $1 FOR UPDATE) habitaciones`,
+		tipo.ID,
+	).Scan(&capacidadMaxima)
+	if err != nil {
+		return fmt.Errorf("error al verificar capacidad de las habitaciones: %w", err)
+	}
+
+	if capacidadMaxima > tipo.CapacidadAdultos+tipo.CapacidadNinhos {
+		return fmt.Errorf("la capacidad del tipo (%d) es menor que la de sus habitaciones (%d)",
+			tipo.CapacidadAdultos+tipo.CapacidadNinhos, capacidadMaxima)
+	}
+
+	query := `
+		UPDATE room_type
+		SET title = $1,
+			description = $2,
+			adult_capacity = $3,
+			children_capacity = $4,
+			beds_count = $5,
+			price = $6,
+			min_price = $7,
+			max_price = $8,
+			amenities = $9
+		WHERE room_type_id = $10
+			AND active = true`
+
+	result, err := tx.Exec(
+		query,
+		tipo.Titulo,
+		tipo.Descripcion,
+		tipo.CapacidadAdultos,
+		tipo.CapacidadNinhos,
+		tipo.CantidadCamas,
+		tipo.Precio,
+		tipo.PrecioMinimo,
+		tipo.PrecioMaximo,
+		pq.Array(tipo.Amenidades),
+		tipo.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error al actualizar tipo de habitación: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("tipo de habitación con ID %d no encontrado", tipo.ID)
+	}
+
+	return tx.Commit()
+}
+
+// DeactivateRoomType implementa domain.HabitacionRepository
+func (r *habitacionRepository) DeactivateRoomType(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	var enServicio int
+	err = tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM (SELECT room_id FROM room WHERE room_type_id = $1 AND status = $2 FOR UPDATE) habitaciones`,
+		id, domain.HabitacionDisponible,
+	).Scan(&enServicio)
+	if err != nil {
+		return fmt.Errorf("error al verificar habitaciones del tipo: %w", err)
+	}
+
+	if enServicio > 0 {
+		return &domain.TipoHabitacionEnUsoError{TipoHabitacionID: id, Habitaciones: enServicio}
+	}
+
+	result, err := tx.Exec(`UPDATE room_type SET active = false WHERE room_type_id = $1 AND active = true`, id)
+	if err != nil {
+		return fmt.Errorf("error al desactivar tipo de habitación: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("tipo de habitación con ID %d no encontrado", id)
+	}
+
+	return tx.Commit()
+}
+
+// bloquearHabitacion bloquea la fila de la habitación hasta el fin de la transacción
+func bloquearHabitacion(tx *sql.Tx, id int) error {
+	var roomID int
+	err := tx.QueryRow(`SELECT room_id FROM room WHERE room_id = $1 FOR UPDATE`, id).Scan(&roomID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("habitación con ID %d no encontrada", id)
+		}
+		return fmt.Errorf("error al bloquear habitación %d: %w", id, err)
+	}
+	return nil
+}
+
+// verificarSinReservasFuturas retorna *domain.HabitacionConReservasError si la habitación tiene
+// reservas activas que aún no terminan
+func verificarSinReservasFuturas(q queryRower, habitacionID int) error {
+	query := `
+		SELECT COUNT(DISTINCT rh.reservation_id)
+		FROM reservation_room rh
+		INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+		WHERE rh.room_id = $1
+		AND rh.status = 1
+		AND ` + condicionReservaVigente + `
+		AND r.status NOT IN ('Completada', 'NoShow')
+		AND rh.check_out_date > NOW()`
+
+	var reservas int
+	if err := q.QueryRow(query, habitacionID).Scan(&reservas); err != nil {
+		return fmt.Errorf("error al verificar reservas de la habitación: %w", err)
+	}
+
+	if reservas > 0 {
+		return &domain.HabitacionConReservasError{HabitacionID: habitacionID, Reservas: reservas}
+	}
+	return nil
+}
+
+// errorHabitacion traduce los errores de escritura de habitaciones
+func errorHabitacion(err error, operacion string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == codigoUniqueViolation && pqErr.Constraint == "idx_room_number" {
+		return domain.ErrNumeroHabitacionDuplicado
+	}
+	return fmt.Errorf("error al %s habitación: %w", operacion, err)
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
 )
 
 type habitacionRepository struct {
@@ -29,13 +31,16 @@ func (r *habitacionRepository) GetAllRooms() ([]domain.Habitacion, error) {
 			h.capacity,
 			h.status,
 			h.general_description,
+			h.housekeeping_status,
 			t.room_type_id,
 			t.title,
 			t.description,
 			t.adult_capacity,
 			t.children_capacity,
 			t.beds_count,
-			t.price
+			t.price,
+			t.min_price,
+			t.max_price
 		FROM 
 			room h
 		INNER JOIN 
@@ -59,6 +64,7 @@ func (r *habitacionRepository) GetAllRooms() ([]domain.Habitacion, error) {
 			&h.Capacidad,
 			&h.Estado,
 			&h.DescripcionGeneral,
+			&h.EstadoLimpieza,
 			&h.TipoHabitacion.ID,
 			&h.TipoHabitacion.Titulo,
 			&h.TipoHabitacion.Descripcion,
@@ -66,6 +72,8 @@ func (r *habitacionRepository) GetAllRooms() ([]domain.Habitacion, error) {
 			&h.TipoHabitacion.CapacidadNinhos,
 			&h.TipoHabitacion.CantidadCamas,
 			&h.TipoHabitacion.Precio,
+			&h.TipoHabitacion.PrecioMinimo,
+			&h.TipoHabitacion.PrecioMaximo,
 		)
 		if err != nil {
 			return nil, err
@@ -80,45 +88,136 @@ func (r *habitacionRepository) GetAllRooms() ([]domain.Habitacion, error) {
 	return habitaciones, nil
 }
 
-// GetDisponibilidadFechas implementa domain.HabitacionRepository
-func (r *habitacionRepository) GetDisponibilidadFechas(desde, hasta time.Time) ([]domain.DisponibilidadFecha, error) {
+// GetRoomByID implements domain.HabitacionRepository
+func (r *habitacionRepository) GetRoomByID(id int) (*domain.Habitacion, error) {
+	query := `
+		SELECT 
+			h.room_id,
+			h.name,
+			h.number,
+			h.capacity,
+			h.status,
+			h.general_description,
+			h.housekeeping_status,
+			t.room_type_id,
+			t.title,
+			t.description,
+			t.adult_capacity,
+			t.children_capacity,
+			t.beds_count,
+			t.price,
+			t.min_price,
+			t.max_price
+		FROM 
+			room h
+		INNER JOIN 
+			room_type t ON h.room_type_id = t.room_type_id
+		WHERE 
+			h.room_id = $1;`
+
+	var h domain.Habitacion
+	err := r.db.QueryRow(query, id).Scan(
+		&h.ID,
+		&h.Nombre,
+		&h.Numero,
+		&h.Capacidad,
+		&h.Estado,
+		&h.DescripcionGeneral,
+		&h.EstadoLimpieza,
+		&h.TipoHabitacion.ID,
+		&h.TipoHabitacion.Titulo,
+		&h.TipoHabitacion.Descripcion,
+		&h.TipoHabitacion.CapacidadAdultos,
+		&h.TipoHabitacion.CapacidadNinhos,
+		&h.TipoHabitacion.CantidadCamas,
+		&h.TipoHabitacion.Precio,
+		&h.TipoHabitacion.PrecioMinimo,
+		&h.TipoHabitacion.PrecioMaximo,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("habitación con ID %d no encontrada", id)
+		}
+		return nil, fmt.Errorf("error querying room: %w", err)
+	}
+
+	return &h, nil
+}
+
+// GetDisponibilidadFechas implementa domain.HabitacionRepository.
+// Una habitación cuenta como reservada (o bloqueada) la noche de una fecha si la estadía o el
+// bloqueo la incluye (inicio <= fecha < fin), el mismo criterio que usa la verificación de disponibilidad.
+// Las habitaciones reservadas por tipo y aún sin asignar también cuentan como reservadas.
+func (r *habitacionRepository) GetDisponibilidadFechas(desde, hasta time.Time, tipoHabitacionID int) ([]domain.DisponibilidadFecha, error) {
 	query := `
 		WITH RECURSIVE fechas AS (
-			SELECT date(cast($1 as timestamp)) as fecha
+			SELECT cast($1 as date) as fecha
 			UNION ALL
-			SELECT fecha + interval '1 day'
+			SELECT (fecha + interval '1 day')::date
 			FROM fechas
-			WHERE fecha < date(cast($2 as timestamp))
+			WHERE fecha < cast($2 as date)
 		),
-		habitaciones_totales AS (
-			SELECT COUNT(*) as total
-			FROM room
-			WHERE status = 'Disponible'
+		tipos AS (
+			SELECT t.room_type_id, COUNT(h.room_id) as total
+			FROM room_type t
+			LEFT JOIN room h ON h.room_type_id = t.room_type_id
+				AND h.status = 'Disponible'
+			WHERE ($3::int = 0 OR t.room_type_id = $3::int)
+			GROUP BY t.room_type_id
 		),
-		habitaciones_ocupadas AS (
-			SELECT date(f.fecha) as fecha, 
-				   COUNT(DISTINCT rh.room_id) as ocupadas
+		habitaciones_reservadas AS (
+			SELECT f.fecha,
+				   h.room_type_id,
+				   COUNT(DISTINCT rh.room_id) as reservadas
 			FROM fechas f
-			LEFT JOIN reservation_room rh ON 
-				date(f.fecha) BETWEEN date(rh.check_in_date) AND date(rh.check_out_date)
-			LEFT JOIN reservation r ON r.reservation_id = rh.reservation_id
-				AND rh.status = 1
-				AND r.status = 'Confirmada'
-			GROUP BY date(f.fecha)
+			INNER JOIN reservation_room rh ON
+				cast(rh.check_in_date as date) <= f.fecha AND cast(rh.check_out_date as date) > f.fecha
+			INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+			INNER JOIN room h ON h.room_id = rh.room_id
+				AND h.status = 'Disponible'
+			WHERE rh.status = 1
+				AND ` + condicionReservaVigente + `
+			GROUP BY f.fecha, h.room_type_id
+		),
+		habitaciones_bloqueadas AS (
+			SELECT f.fecha,
+				   h.room_type_id,
+				   COUNT(DISTINCT b.room_id) as bloqueadas
+			FROM fechas f
+			INNER JOIN room_block b ON b.start_date <= f.fecha AND b.end_date > f.fecha
+			INNER JOIN room h ON h.room_id = b.room_id
+				AND h.status = 'Disponible'
+			GROUP BY f.fecha, h.room_type_id
+		),
+		habitaciones_por_asignar AS (
+			SELECT f.fecha,
+				   rt.room_type_id,
+				   SUM(rt.quantity) as por_asignar
+			FROM fechas f
+			INNER JOIN reservation_room_type rt ON
+				cast(rt.check_in_date as date) <= f.fecha AND cast(rt.check_out_date as date) > f.fecha
+			INNER JOIN reservation r ON r.reservation_id = rt.reservation_id
+			WHERE rt.status = 1
+				AND ` + condicionReservaVigente + `
+			GROUP BY f.fecha, rt.room_type_id
 		)
 		SELECT 
 			f.fecha,
-			CASE 
-				WHEN (ht.total - COALESCE(ho.ocupadas, 0)) > 0 THEN true 
-				ELSE false 
-			END as disponible,
-			(ht.total - COALESCE(ho.ocupadas, 0)) as habitaciones_disponibles
+			t.room_type_id,
+			t.total,
+			COALESCE(hr.reservadas, 0) + COALESCE(hp.por_asignar, 0) as reservadas,
+			COALESCE(hb.bloqueadas, 0) as bloqueadas
 		FROM fechas f
-		CROSS JOIN habitaciones_totales ht
-		LEFT JOIN habitaciones_ocupadas ho ON date(f.fecha) = date(ho.fecha)
-		ORDER BY f.fecha;`
+		CROSS JOIN tipos t
+		LEFT JOIN habitaciones_reservadas hr ON hr.fecha = f.fecha
+			AND hr.room_type_id = t.room_type_id
+		LEFT JOIN habitaciones_bloqueadas hb ON hb.fecha = f.fecha
+			AND hb.room_type_id = t.room_type_id
+		LEFT JOIN habitaciones_por_asignar hp ON hp.fecha = f.fecha
+			AND hp.room_type_id = t.room_type_id
+		ORDER BY f.fecha, t.room_type_id;`
 
-	rows, err := r.db.Query(query, desde, hasta)
+	rows, err := r.db.Query(query, desde, hasta, tipoHabitacionID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying disponibilidad: %w", err)
 	}
@@ -127,10 +226,12 @@ func (r *habitacionRepository) GetDisponibilidadFechas(desde, hasta time.Time) (
 	var disponibilidades []domain.DisponibilidadFecha
 	for rows.Next() {
 		var d domain.DisponibilidadFecha
-		err := rows.Scan(&d.Fecha, &d.Disponible, &d.Habitaciones)
+		err := rows.Scan(&d.Fecha, &d.TipoHabitacionID, &d.Total, &d.Reservadas, &d.Bloqueadas)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning disponibilidad: %w", err)
 		}
+		d.Habitaciones = max(0, d.Total-d.Reservadas-d.Bloqueadas)
+		d.Disponible = d.Habitaciones > 0
 		disponibilidades = append(disponibilidades, d)
 	}
 
@@ -157,20 +258,42 @@ func (r *habitacionRepository) GetFechasBloqueadas(desde, hasta time.Time) (*dom
 			WHERE h.status = 'Disponible'
 		),
 		habitaciones_ocupadas AS (
-			SELECT date(f.fecha) as fecha, 
-				   COUNT(DISTINCT rh.room_id) as habitaciones_ocupadas
+			SELECT fecha, 
+				   COUNT(DISTINCT room_id) as habitaciones_ocupadas
+			FROM (
+				SELECT f.fecha, rh.room_id
+				FROM fechas f
+				INNER JOIN reservation_room rh ON 
+					f.fecha BETWEEN cast(rh.check_in_date as date) AND cast(rh.check_out_date as date)
+				INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+				WHERE rh.status = 1
+					AND ` + condicionReservaVigente + `
+				UNION
+				SELECT f.fecha, b.room_id
+				FROM fechas f
+				INNER JOIN room_block b ON b.start_date <= f.fecha AND b.end_date > f.fecha
+				INNER JOIN room h ON h.room_id = b.room_id
+					AND h.status = 'Disponible'
+			) ocupacion
+			GROUP BY fecha
+		),
+		habitaciones_por_asignar AS (
+			SELECT f.fecha,
+				   SUM(rt.quantity) as por_asignar
 			FROM fechas f
-			LEFT JOIN reservation_room rh ON 
-				f.fecha BETWEEN cast(rh.check_in_date as date) AND cast(rh.check_out_date as date)
-			LEFT JOIN reservation r ON r.reservation_id = rh.reservation_id
-				AND rh.status = 1
-				AND r.status = 'Confirmada'
+			INNER JOIN reservation_room_type rt ON
+				cast(rt.check_in_date as date) <= f.fecha AND cast(rt.check_out_date as date) > f.fecha
+			INNER JOIN reservation r ON r.reservation_id = rt.reservation_id
+			WHERE rt.status = 1
+				AND ` + condicionReservaVigente + `
 			GROUP BY f.fecha
-			HAVING COUNT(DISTINCT rh.room_id) >= (SELECT total FROM habitaciones_totales)
 		)
-		SELECT fecha::date
-		FROM habitaciones_ocupadas
-		ORDER BY fecha;`
+		SELECT f.fecha::date
+		FROM fechas f
+		LEFT JOIN habitaciones_ocupadas o ON o.fecha = f.fecha
+		LEFT JOIN habitaciones_por_asignar p ON p.fecha = f.fecha
+		WHERE COALESCE(o.habitaciones_ocupadas, 0) + COALESCE(p.por_asignar, 0) >= (SELECT total FROM habitaciones_totales)
+		ORDER BY f.fecha;`
 
 	rows, err := r.db.Query(query, desde, hasta)
 	if err != nil {
@@ -207,13 +330,16 @@ func (r *habitacionRepository) GetAvailableRooms(fechaEntrada, fechaSalida time.
 			h.capacity,
 			h.status,
 			h.general_description,
+			h.housekeeping_status,
 			t.room_type_id,
 			t.title,
 			t.description,
 			t.adult_capacity,
 			t.children_capacity,
 			t.beds_count,
-			t.price
+			t.price,
+			t.min_price,
+			t.max_price
 		FROM 
 			room h
 		INNER JOIN 
@@ -225,13 +351,14 @@ func (r *habitacionRepository) GetAvailableRooms(fechaEntrada, fechaSalida time.
 				JOIN reservation r ON r.reservation_id = rh.reservation_id
 				WHERE rh.room_id = h.room_id
 				AND rh.status = 1
-				AND r.status = 'Confirmada'
+				AND ` + condicionReservaVigente + `
 				AND (
 					(rh.check_in_date <= $1 AND rh.check_out_date >= $1)
 					OR (rh.check_in_date <= $2 AND rh.check_out_date >= $2)
 					OR (rh.check_in_date >= $1 AND rh.check_out_date <= $2)
 				)
 			)
+			AND ` + condicionSinBloqueo("h.room_id", "$1", "$2") + `
 		ORDER BY 
 			h.room_id;`
 
@@ -251,6 +378,7 @@ func (r *habitacionRepository) GetAvailableRooms(fechaEntrada, fechaSalida time.
 			&h.Capacidad,
 			&h.Estado,
 			&h.DescripcionGeneral,
+			&h.EstadoLimpieza,
 			&h.TipoHabitacion.ID,
 			&h.TipoHabitacion.Titulo,
 			&h.TipoHabitacion.Descripcion,
@@ -258,6 +386,8 @@ func (r *habitacionRepository) GetAvailableRooms(fechaEntrada, fechaSalida time.
 			&h.TipoHabitacion.CapacidadNinhos,
 			&h.TipoHabitacion.CantidadCamas,
 			&h.TipoHabitacion.Precio,
+			&h.TipoHabitacion.PrecioMinimo,
+			&h.TipoHabitacion.PrecioMaximo,
 		)
 		if err != nil {
 			return nil, err
@@ -272,6 +402,149 @@ func (r *habitacionRepository) GetAvailableRooms(fechaEntrada, fechaSalida time.
 	return habitaciones, nil
 }
 
+// ordenBusqueda traduce los criterios de orden de la búsqueda a SQL
+var ordenBusqueda = map[string]string{
+	domain.OrdenPrecioAsc:  "t.price ASC, h.room_id",
+	domain.OrdenPrecioDesc: "t.price DESC, h.room_id",
+	domain.OrdenCapacidad:  "(t.adult_capacity + t.children_capacity) DESC, t.price ASC, h.room_id",
+	domain.OrdenNumero:     "h.number, h.room_id",
+}
+
+// BuscarHabitaciones implementa domain.HabitacionRepository.
+// Una habitación aloja al grupo si el tipo admite a todos los adultos y la capacidad total
+// (adultos + niños) alcanza para todo el grupo: los niños pueden ocupar plazas de adulto.
+func (r *habitacionRepository) BuscarHabitaciones(filtro domain.FiltroBusquedaHabitaciones) ([]domain.Habitacion, int, error) {
+	args := []interface{}{filtro.FechaEntrada, filtro.FechaSalida}
+	condiciones := []string{
+		"h.status = 'Disponible'",
+		`NOT EXISTS (
+				SELECT 1 FROM reservation_room rh
+				JOIN reservation r ON r.reservation_id = rh.reservation_id
+				WHERE rh.room_id = h.room_id
+				AND rh.status = 1
+				AND ` + condicionReservaVigente + `
+				AND rh.check_in_date < $2 AND rh.check_out_date > $1
+			)`,
+		condicionSinBloqueo("h.room_id", "$1", "$2"),
+	}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.Adultos > 0 {
+		agregar("t.adult_capacity >= $%d", filtro.Adultos)
+	}
+	if filtro.Adultos+filtro.Ninhos > 0 {
+		agregar("t.adult_capacity + t.children_capacity >= $%d", filtro.Adultos+filtro.Ninhos)
+	}
+	if filtro.TipoHabitacionID > 0 {
+		agregar("t.room_type_id = $%d", filtro.TipoHabitacionID)
+	}
+	if filtro.PrecioMin > 0 {
+		agregar("t.price >= $%d", filtro.PrecioMin)
+	}
+	if filtro.PrecioMax > 0 {
+		agregar("t.price <= $%d", filtro.PrecioMax)
+	}
+	if filtro.MinCamas > 0 {
+		agregar("t.beds_count >= $%d", filtro.MinCamas)
+	}
+	if len(filtro.Amenidades) > 0 {
+		agregar("t.amenities @> $%d", pq.Array(filtro.Amenidades))
+	}
+
+	orden, ok := ordenBusqueda[filtro.Orden]
+	if !ok {
+		orden = ordenBusqueda[domain.OrdenPrecioAsc]
+	}
+
+	desde := `
+		FROM 
+			room h
+		INNER JOIN 
+			room_type t ON h.room_type_id = t.room_type_id
+		WHERE 
+			` + strings.Join(condiciones, "\n\t\t\tAND ")
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*)"+desde, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error al contar habitaciones: %w", err)
+	}
+
+	query := `
+		SELECT 
+			h.room_id,
+			h.name,
+			h.number,
+			h.capacity,
+			h.status,
+			h.general_description,
+			h.housekeeping_status,
+			t.room_type_id,
+			t.title,
+			t.description,
+			t.adult_capacity,
+			t.children_capacity,
+			t.beds_count,
+			t.price,
+			t.min_price,
+			t.max_price,
+			t.amenities` + desde + `
+		ORDER BY 
+			` + orden
+
+	if filtro.PorPagina > 0 {
+		pagina := filtro.Pagina
+		if pagina < 1 {
+			pagina = 1
+		}
+		args = append(args, filtro.PorPagina, (pagina-1)*filtro.PorPagina)
+		query += fmt.Sprintf("\n\t\tLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error al buscar habitaciones: %w", err)
+	}
+	defer rows.Close()
+
+	habitaciones := make([]domain.Habitacion, 0)
+	for rows.Next() {
+		var h domain.Habitacion
+		err := rows.Scan(
+			&h.ID,
+			&h.Nombre,
+			&h.Numero,
+			&h.Capacidad,
+			&h.Estado,
+			&h.DescripcionGeneral,
+			&h.EstadoLimpieza,
+			&h.TipoHabitacion.ID,
+			&h.TipoHabitacion.Titulo,
+			&h.TipoHabitacion.Descripcion,
+			&h.TipoHabitacion.CapacidadAdultos,
+			&h.TipoHabitacion.CapacidadNinhos,
+			&h.TipoHabitacion.CantidadCamas,
+			&h.TipoHabitacion.Precio,
+			&h.TipoHabitacion.PrecioMinimo,
+			&h.TipoHabitacion.PrecioMaximo,
+			pq.Array(&h.TipoHabitacion.Amenidades),
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error al escanear habitación: %w", err)
+		}
+		habitaciones = append(habitaciones, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error al iterar habitaciones: %w", err)
+	}
+
+	return habitaciones, total, nil
+}
+
 // GetRoomTypes implements domain.HabitacionRepository
 func (r *habitacionRepository) GetRoomTypes() ([]domain.TipoHabitacion, error) {
 	query := `
@@ -282,9 +555,14 @@ func (r *habitacionRepository) GetRoomTypes() ([]domain.TipoHabitacion, error) {
 			adult_capacity,
 			children_capacity,
 			beds_count,
-			price
+			price,
+			min_price,
+			max_price,
+			amenities
 		FROM 
 			room_type
+		WHERE 
+			active = true
 		ORDER BY 
 			room_type_id;`
 
@@ -305,6 +583,9 @@ func (r *habitacionRepository) GetRoomTypes() ([]domain.TipoHabitacion, error) {
 			&rt.CapacidadNinhos,
 			&rt.CantidadCamas,
 			&rt.Precio,
+			&rt.PrecioMinimo,
+			&rt.PrecioMaximo,
+			pq.Array(&rt.Amenidades),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning room type: %w", err)
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type idempotenciaRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotenciaRepository crea una nueva instancia del repositorio de claves de idempotencia
+func NewIdempotenciaRepository(db *sql.DB) domain.IdempotenciaRepository {
+	return &idempotenciaRepository{db: db}
+}
+
+// Reservar registra la clave en proceso. Si ya existe vencida, o en proceso con el mismo cuerpo y
+// el plazo de procesamiento vencido, la reemplaza; si no retorna el registro guardado sin
+// modificarlo.
+func (r *idempotenciaRepository) Reservar(registro *domain.RegistroIdempotencia, ahora time.Time) (*domain.RegistroIdempotencia, error) {
+	query := `
+		INSERT INTO idempotency_key (idempotency_key, route, request_hash, expires_at, locked_until)
+		VALUES ($1, $2, $3, $4, $6)
+		ON CONFLICT (idempotency_key, route) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at,
+			locked_until = EXCLUDED.locked_until
+		WHERE idempotency_key.expires_at <= $5
+			OR (idempotency_key.status_code IS NULL
+				AND idempotency_key.locked_until <= $5
+				AND idempotency_key.request_hash = EXCLUDED.request_hash)
+		RETURNING idempotency_key`
+
+	var clave string
+	err := r.db.QueryRow(query,
+		registro.Clave, registro.Ruta, registro.HashSolicitud, registro.ExpiraEn, ahora, registro.BloqueadaHasta,
+	).Scan(&clave)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error al registrar clave de idempotencia: %w", err)
+	}
+
+	// La clave existe y está vigente
+	existente := &domain.RegistroIdempotencia{}
+	var status sql.NullInt64
+	var contentType sql.NullString
+	err = r.db.QueryRow(`
+		SELECT idempotency_key, route, request_hash, status_code, content_type, response_body, expires_at, locked_until
+		FROM idempotency_key
+		WHERE idempotency_key = $1 AND route = $2`, registro.Clave, registro.Ruta,
+	).Scan(
+		&existente.Clave,
+		&existente.Ruta,
+		&existente.HashSolicitud,
+		&status,
+		&contentType,
+		&existente.Cuerpo,
+		&existente.ExpiraEn,
+		&existente.BloqueadaHasta,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener clave de idempotencia: %w", err)
+	}
+
+	existente.Status = int(status.Int64)
+	existente.ContentType = contentType.String
+	return existente, nil
+}
+
+// GuardarRespuesta guarda la respuesta de la solicitud registrada con la clave
+func (r *idempotenciaRepository) GuardarRespuesta(registro *domain.RegistroIdempotencia) error {
+	_, err := r.db.Exec(`
+		UPDATE idempotency_key
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE idempotency_key = $4 AND route = $5`,
+		registro.Status, nullString(registro.ContentType), registro.Cuerpo, registro.Clave, registro.Ruta)
+	if err != nil {
+		return fmt.Errorf("error al guardar respuesta idempotente: %w", err)
+	}
+	return nil
+}
+
+// Liberar elimina la clave
+func (r *idempotenciaRepository) Liberar(clave, ruta string) error {
+	if _, err := r.db.Exec(`DELETE FROM idempotency_key WHERE idempotency_key = $1 AND route = $2`, clave, ruta); err != nil {
+		return fmt.Errorf("error al liberar clave de idempotencia: %w", err)
+	}
+	return nil
+}
+
+// EliminarVencidas elimina las claves vencidas
+func (r *idempotenciaRepository) EliminarVencidas(ahora time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_key WHERE expires_at <= $1`, ahora)
+	if err != nil {
+		return 0, fmt.Errorf("error al eliminar claves de idempotencia vencidas: %w", err)
+	}
+	return result.RowsAffected()
+}
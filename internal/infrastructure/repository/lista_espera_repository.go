@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type listaEsperaRepository struct {
+	db *sql.DB
+}
+
+// NewListaEsperaRepository crea una nueva instancia del repositorio de la lista de espera
+func NewListaEsperaRepository(db *sql.DB) domain.ListaEsperaRepository {
+	return &listaEsperaRepository{db: db}
+}
+
+const columnasSolicitudEspera = `
+			request_id,
+			email,
+			check_in_date,
+			check_out_date,
+			adults,
+			children,
+			room_type_ids,
+			status,
+			reservation_id,
+			COALESCE(offer_token, ''),
+			offer_expires_at,
+			created_at`
+
+// scanSolicitudEspera lee una solicitud seleccionada con columnasSolicitudEspera
+func scanSolicitudEspera(row rowScanner) (*domain.SolicitudEspera, error) {
+	var solicitud domain.SolicitudEspera
+	var tipos pq.Int64Array
+	var reservaID sql.NullInt64
+	var expiraEn sql.NullTime
+
+	err := row.Scan(
+		&solicitud.ID,
+		&solicitud.Email,
+		&solicitud.FechaEntrada,
+		&solicitud.FechaSalida,
+		&solicitud.CantidadAdultos,
+		&solicitud.CantidadNinhos,
+		&tipos,
+		&solicitud.Estado,
+		&reservaID,
+		&solicitud.TokenOferta,
+		&expiraEn,
+		&solicitud.FechaRegistro,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	solicitud.TiposHabitacion = make([]int, len(tipos))
+	for i, tipo := range tipos {
+		solicitud.TiposHabitacion[i] = int(tipo)
+	}
+	if reservaID.Valid {
+		id := int(reservaID.Int64)
+		solicitud.ReservaID = &id
+	}
+	if expiraEn.Valid {
+		solicitud.OfertaExpiraEn = &expiraEn.Time
+	}
+
+	return &solicitud, nil
+}
+
+// CreateSolicitud registra una solicitud al final de la lista de espera
+func (r *listaEsperaRepository) CreateSolicitud(solicitud *domain.SolicitudEspera) error {
+	query := `
+		INSERT INTO waitlist_request (email, check_in_date, check_out_date, adults, children, room_type_ids, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING request_id, created_at`
+
+	err := r.db.QueryRow(
+		query,
+		solicitud.Email,
+		solicitud.FechaEntrada,
+		solicitud.FechaSalida,
+		solicitud.CantidadAdultos,
+		solicitud.CantidadNinhos,
+		pq.Array(solicitud.TiposHabitacion),
+		solicitud.Estado,
+	).Scan(&solicitud.ID, &solicitud.FechaRegistro)
+	if err != nil {
+		return fmt.Errorf("error al registrar solicitud de lista de espera: %w", err)
+	}
+
+	return nil
+}
+
+// GetSolicitudes lista las solicitudes en orden de registro; estado vacío incluye todas
+func (r *listaEsperaRepository) GetSolicitudes(estado string) ([]domain.SolicitudEspera, error) {
+	query := `SELECT` + columnasSolicitudEspera + `
+		FROM waitlist_request
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at, request_id`
+
+	rows, err := r.db.Query(query, estado)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener lista de espera: %w", err)
+	}
+	defer rows.Close()
+
+	solicitudes := make([]domain.SolicitudEspera, 0)
+	for rows.Next() {
+		solicitud, err := scanSolicitudEspera(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear solicitud de lista de espera: %w", err)
+		}
+		solicitudes = append(solicitudes, *solicitud)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar lista de espera: %w", err)
+	}
+
+	return solicitudes, nil
+}
+
+// GetSolicitudByToken obtiene la solicitud de una oferta
+func (r *listaEsperaRepository) GetSolicitudByToken(token string) (*domain.SolicitudEspera, error) {
+	query := `SELECT` + columnasSolicitudEspera + `
+		FROM waitlist_request
+		WHERE offer_token = $1`
+
+	solicitud, err := scanSolicitudEspera(r.db.QueryRow(query, token))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSolicitudEsperaNoEncontrada
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener oferta de lista de espera: %w", err)
+	}
+
+	return solicitud, nil
+}
+
+// RegistrarOferta asocia la reserva ofrecida a la solicitud si sigue esperando
+func (r *listaEsperaRepository) RegistrarOferta(id, reservaID int, token string, expiraEn time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE waitlist_request
+		SET status = $1, reservation_id = $2, offer_token = $3, offer_expires_at = $4
+		WHERE request_id = $5 AND status = $6`,
+		domain.EsperaOfertada, reservaID, token, expiraEn, id, domain.EsperaEnCola)
+	if err != nil {
+		return fmt.Errorf("error al registrar oferta de lista de espera: %w", err)
+	}
+
+	return verificarSolicitudActualizada(result)
+}
+
+// CambiarEstado cambia el estado de la solicitud si sigue en el estado desde
+func (r *listaEsperaRepository) CambiarEstado(id int, desde, hacia string) error {
+	result, err := r.db.Exec(`
+		UPDATE waitlist_request
+		SET status = $1
+		WHERE request_id = $2 AND status = $3`,
+		hacia, id, desde)
+	if err != nil {
+		return fmt.Errorf("error al actualizar solicitud de lista de espera: %w", err)
+	}
+
+	return verificarSolicitudActualizada(result)
+}
+
+// VencerSolicitudes marca como vencidas las ofertas cuyo hold venció y las solicitudes en
+// espera cuya fecha de entrada ya pasó
+func (r *listaEsperaRepository) VencerSolicitudes(ahora, hoy time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE waitlist_request
+		SET status = $1
+		WHERE (status = $2 AND offer_expires_at <= $3)
+		OR (status = $4 AND check_in_date < cast($5 as date))`,
+		domain.EsperaVencida, domain.EsperaOfertada, ahora, domain.EsperaEnCola, hoy.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("error al vencer solicitudes de lista de espera: %w", err)
+	}
+
+	return nil
+}
+
+// verificarSolicitudActualizada retorna ErrSolicitudEsperaNoEncontrada si la actualización no encontró
+// la solicitud en el estado esperado
+func verificarSolicitudActualizada(result sql.Result) error {
+	filas, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar actualización: %w", err)
+	}
+	if filas == 0 {
+		return domain.ErrSolicitudEsperaNoEncontrada
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type politicaCancelacionRepository struct {
+	db *sql.DB
+}
+
+// NewPoliticaCancelacionRepository crea una nueva instancia del repositorio de políticas de cancelación
+func NewPoliticaCancelacionRepository(db *sql.DB) domain.PoliticaCancelacionRepository {
+	return &politicaCancelacionRepository{db: db}
+}
+
+const columnasPoliticaCancelacion = `
+			cancellation_policy_id,
+			name,
+			free_hours,
+			penalty_type,
+			percentage,
+			room_type_id,
+			rate_plan_id,
+			active`
+
+// GetPoliticas obtiene todas las políticas de cancelación
+func (r *politicaCancelacionRepository) GetPoliticas() ([]domain.PoliticaCancelacion, error) {
+	query := `SELECT` + columnasPoliticaCancelacion + `
+		FROM cancellation_policy
+		ORDER BY cancellation_policy_id`
+
+	return r.queryPoliticas(query)
+}
+
+// GetPoliticasActivas obtiene las políticas de cancelación activas
+func (r *politicaCancelacionRepository) GetPoliticasActivas() ([]domain.PoliticaCancelacion, error) {
+	query := `SELECT` + columnasPoliticaCancelacion + `
+		FROM cancellation_policy
+		WHERE active = true
+		ORDER BY cancellation_policy_id`
+
+	return r.queryPoliticas(query)
+}
+
+func (r *politicaCancelacionRepository) queryPoliticas(query string, args ...interface{}) ([]domain.PoliticaCancelacion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener políticas de cancelación: %w", err)
+	}
+	defer rows.Close()
+
+	var politicas []domain.PoliticaCancelacion
+	for rows.Next() {
+		var politica domain.PoliticaCancelacion
+		var tipoHabitacionID, planTarifaID sql.NullInt64
+
+		err := rows.Scan(
+			&politica.ID,
+			&politica.Nombre,
+			&politica.HorasLibres,
+			&politica.TipoPenalidad,
+			&politica.Porcentaje,
+			&tipoHabitacionID,
+			&planTarifaID,
+			&politica.Activo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear política de cancelación: %w", err)
+		}
+
+		if tipoHabitacionID.Valid {
+			id := int(tipoHabitacionID.Int64)
+			politica.TipoHabitacionID = &id
+		}
+		if planTarifaID.Valid {
+			id := int(planTarifaID.Int64)
+			politica.PlanTarifaID = &id
+		}
+
+		politicas = append(politicas, politica)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer políticas de cancelación: %w", err)
+	}
+
+	return politicas, nil
+}
+
+// CreatePolitica crea una nueva política de cancelación
+func (r *politicaCancelacionRepository) CreatePolitica(politica *domain.PoliticaCancelacion) error {
+	query := `
+		INSERT INTO cancellation_policy (
+			name,
+			free_hours,
+			penalty_type,
+			percentage,
+			room_type_id,
+			rate_plan_id,
+			active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING cancellation_policy_id
+	`
+
+	err := r.db.QueryRow(
+		query,
+		politica.Nombre,
+		politica.HorasLibres,
+		politica.TipoPenalidad,
+		politica.Porcentaje,
+		politica.TipoHabitacionID,
+		politica.PlanTarifaID,
+		politica.Activo,
+	).Scan(&politica.ID)
+	if err != nil {
+		return fmt.Errorf("error al crear política de cancelación: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePolitica actualiza una política de cancelación existente
+func (r *politicaCancelacionRepository) UpdatePolitica(politica *domain.PoliticaCancelacion) error {
+	query := `
+		UPDATE cancellation_policy
+		SET name = $1,
+			free_hours = $2,
+			penalty_type = $3,
+			percentage = $4,
+			room_type_id = $5,
+			rate_plan_id = $6,
+			active = $7
+		WHERE cancellation_policy_id = $8
+	`
+
+	result, err := r.db.Exec(
+		query,
+		politica.Nombre,
+		politica.HorasLibres,
+		politica.TipoPenalidad,
+		politica.Porcentaje,
+		politica.TipoHabitacionID,
+		politica.PlanTarifaID,
+		politica.Activo,
+		politica.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error al actualizar política de cancelación: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("política de cancelación con ID %d no encontrada", politica.ID)
+	}
+
+	return nil
+}
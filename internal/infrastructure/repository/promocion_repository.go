@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type promocionRepository struct {
+	db *sql.DB
+}
+
+// NewPromocionRepository crea una nueva instancia del repositorio de promociones
+func NewPromocionRepository(db *sql.DB) domain.PromocionRepository {
+	return &promocionRepository{db: db}
+}
+
+// columnasPromocion incluye la cantidad de canjes de reservas no canceladas
+const columnasPromocion = `
+			p.promotion_id,
+			p.code,
+			p.description,
+			p.discount_type,
+			p.value,
+			p.start_date,
+			p.end_date,
+			p.room_type_ids,
+			p.min_nights,
+			p.max_uses,
+			p.max_uses_per_client,
+			p.active,
+			(
+				SELECT COUNT(*)
+				FROM promotion_redemption pr
+				INNER JOIN reservation r ON r.reservation_id = pr.reservation_id
+				WHERE pr.promotion_id = p.promotion_id
+				AND r.status <> 'Cancelada'
+			) AS usos`
+
+func scanPromocion(row rowScanner) (*domain.Promocion, error) {
+	var p domain.Promocion
+	var tipos pq.Int64Array
+	var maxUsos, maxUsosCliente sql.NullInt64
+
+	err := row.Scan(
+		&p.ID,
+		&p.Codigo,
+		&p.Descripcion,
+		&p.TipoDescuento,
+		&p.Valor,
+		&p.FechaInicio,
+		&p.FechaFin,
+		&tipos,
+		&p.MinNoches,
+		&maxUsos,
+		&maxUsosCliente,
+		&p.Activo,
+		&p.Usos,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.TiposHabitacion = make([]int, len(tipos))
+	for i, id := range tipos {
+		p.TiposHabitacion[i] = int(id)
+	}
+	if maxUsos.Valid {
+		v := int(maxUsos.Int64)
+		p.MaxUsos = &v
+	}
+	if maxUsosCliente.Valid {
+		v := int(maxUsosCliente.Int64)
+		p.MaxUsosPorCliente = &v
+	}
+
+	return &p, nil
+}
+
+// GetPromociones obtiene todas las promociones
+func (r *promocionRepository) GetPromociones() ([]domain.Promocion, error) {
+	query := `SELECT` + columnasPromocion + `
+		FROM promotion p
+		ORDER BY p.promotion_id DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener promociones: %w", err)
+	}
+	defer rows.Close()
+
+	var promociones []domain.Promocion
+	for rows.Next() {
+		p, err := scanPromocion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear promoción: %w", err)
+		}
+		promociones = append(promociones, *p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer promociones: %w", err)
+	}
+
+	return promociones, nil
+}
+
+// GetPromocionByID obtiene una promoción por su ID
+func (r *promocionRepository) GetPromocionByID(id int) (*domain.Promocion, error) {
+	query := `SELECT` + columnasPromocion + `
+		FROM promotion p
+		WHERE p.promotion_id = $1`
+
+	p, err := scanPromocion(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("promoción con ID %d no encontrada", id)
+		}
+		return nil, fmt.Errorf("error al obtener promoción: %w", err)
+	}
+
+	return p, nil
+}
+
+// GetPromocionByCodigo obtiene una promoción por su código (sin distinguir mayúsculas)
+func (r *promocionRepository) GetPromocionByCodigo(codigo string) (*domain.Promocion, error) {
+	query := `SELECT` + columnasPromocion + `
+		FROM promotion p
+		WHERE UPPER(p.code) = UPPER($1)`
+
+	p, err := scanPromocion(r.db.QueryRow(query, codigo))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("el código promocional %s no existe", codigo)
+		}
+		return nil, fmt.Errorf("error al obtener promoción: %w", err)
+	}
+
+	return p, nil
+}
+
+// CreatePromocion crea una nueva promoción
+func (r *promocionRepository) CreatePromocion(p *domain.Promocion) error {
+	query := `
+		INSERT INTO promotion (
+			code,
+			description,
+			discount_type,
+			value,
+			start_date,
+			end_date,
+			room_type_ids,
+			min_nights,
+			max_uses,
+			max_uses_per_client,
+			active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING promotion_id
+	`
+
+	err := r.db.QueryRow(
+		query,
+		p.Codigo,
+		p.Descripcion,
+		p.TipoDescuento,
+		p.Valor,
+		p.FechaInicio,
+		p.FechaFin,
+		pq.Array(p.TiposHabitacion),
+		p.MinNoches,
+		p.MaxUsos,
+		p.MaxUsosPorCliente,
+		p.Activo,
+	).Scan(&p.ID)
+	if err != nil {
+		return fmt.Errorf("error al crear promoción: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePromocion actualiza una promoción existente
+func (r *promocionRepository) UpdatePromocion(p *domain.Promocion) error {
+	query := `
+		UPDATE promotion
+		SET code = $1,
+			description = $2,
+			discount_type = $3,
+			value = $4,
+			start_date = $5,
+			end_date = $6,
+			room_type_ids = $7,
+			min_nights = $8,
+			max_uses = $9,
+			max_uses_per_client = $10,
+			active = $11
+		WHERE promotion_id = $12
+	`
+
+	result, err := r.db.Exec(
+		query,
+		p.Codigo,
+		p.Descripcion,
+		p.TipoDescuento,
+		p.Valor,
+		p.FechaInicio,
+		p.FechaFin,
+		pq.Array(p.TiposHabitacion),
+		p.MinNoches,
+		p.MaxUsos,
+		p.MaxUsosPorCliente,
+		p.Activo,
+		p.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error al actualizar promoción: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("promoción con ID %d no encontrada", p.ID)
+	}
+
+	return nil
+}
+
+// DesactivarPromocion desactiva una promoción sin borrar su historial de canjes
+func (r *promocionRepository) DesactivarPromocion(id int) error {
+	result, err := r.db.Exec(`UPDATE promotion SET active = false WHERE promotion_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error al desactivar promoción: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("promoción con ID %d no encontrada", id)
+	}
+
+	return nil
+}
+
+// registrarCanje registra el uso de la promoción de la reserva dentro de la transacción de
+// creación. El lock sobre la promoción serializa los canjes concurrentes para que los límites
+// de uso globales y por cliente se respeten.
+func registrarCanje(tx *sql.Tx, reserva *domain.Reserva) error {
+	var maxUsos, maxUsosCliente sql.NullInt64
+	err := tx.QueryRow(`
+		SELECT max_uses, max_uses_per_client
+		FROM promotion
+		WHERE promotion_id = $1 AND active = true
+		FOR UPDATE`, *reserva.PromocionID).Scan(&maxUsos, &maxUsosCliente)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("el código promocional ya no está disponible")
+		}
+		return fmt.Errorf("error al bloquear promoción: %w", err)
+	}
+
+	conteoQuery := `
+		SELECT COUNT(*)
+		FROM promotion_redemption pr
+		INNER JOIN reservation r ON r.reservation_id = pr.reservation_id
+		WHERE pr.promotion_id = $1
+		AND r.status <> 'Cancelada'
+	`
+
+	if maxUsos.Valid {
+		var usos int64
+		if err := tx.QueryRow(conteoQuery, *reserva.PromocionID).Scan(&usos); err != nil {
+			return fmt.Errorf("error al contar usos de la promoción: %w", err)
+		}
+		if usos >= maxUsos.Int64 {
+			return domain.ErrPromocionAgotada
+		}
+	}
+
+	if maxUsosCliente.Valid {
+		var usos int64
+		if err := tx.QueryRow(conteoQuery+` AND pr.client_id = $2`, *reserva.PromocionID, reserva.ClienteID).Scan(&usos); err != nil {
+			return fmt.Errorf("error al contar usos de la promoción: %w", err)
+		}
+		if usos >= maxUsosCliente.Int64 {
+			return domain.ErrPromocionLimiteCliente
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO promotion_redemption (promotion_id, reservation_id, client_id, discount)
+		VALUES ($1, $2, $3, $4)`,
+		*reserva.PromocionID, reserva.ID, reserva.ClienteID, reserva.Descuento)
+	if err != nil {
+		return fmt.Errorf("error al registrar canje de la promoción: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// RegistrarCheckIn pasa la reserva a CheckIn registrando la llegada real, sus huéspedes y el
+// estado de limpieza de sus habitaciones en una sola transacción
+func (r *reservaRepository) RegistrarCheckIn(cambio *domain.CambioEstadoReserva, huespedes []domain.HuespedRegistrado) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE reservation SET check_in_at = $1 WHERE reservation_id = $2`, cambio.Fecha, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al registrar llegada: %w", err)
+	}
+
+	query := `
+		INSERT INTO reservation_guest (
+			reservation_id,
+			room_id,
+			full_name,
+			document_type,
+			document_number,
+			nationality
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING guest_id`
+
+	for i := range huespedes {
+		huespedes[i].ReservaID = cambio.ReservaID
+		err := tx.QueryRow(
+			query,
+			huespedes[i].ReservaID,
+			huespedes[i].HabitacionID,
+			huespedes[i].Nombre,
+			huespedes[i].TipoDocumento,
+			huespedes[i].NumeroDocumento,
+			nullString(huespedes[i].Nacionalidad),
+		).Scan(&huespedes[i].ID)
+		if err != nil {
+			return fmt.Errorf("error al registrar huésped: %w", err)
+		}
+	}
+
+	if err := actualizarLimpiezaReserva(tx, cambio.ReservaID, domain.LimpiezaOcupada); err != nil {
+		return err
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// RegistrarCheckOut pasa la reserva a Completada registrando la salida real, los cargos nuevos
+// (los que aún no tienen ID) y los montos finales, devuelve al inventario las noches que no se
+// usarán y deja sus habitaciones para limpieza
+func (r *reservaRepository) RegistrarCheckOut(cambio *domain.CambioEstadoReserva, reserva *domain.Reserva, fechaSalida time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO reservation_charge (reservation_id, description, amount, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING charge_id`
+
+	for i := range reserva.Cargos {
+		if reserva.Cargos[i].ID != 0 {
+			continue
+		}
+
+		reserva.Cargos[i].ReservaID = reserva.ID
+		err := tx.QueryRow(
+			query,
+			reserva.ID,
+			reserva.Cargos[i].Concepto,
+			reserva.Cargos[i].Monto,
+			reserva.Cargos[i].Fecha,
+		).Scan(&reserva.Cargos[i].ID)
+		if err != nil {
+			return fmt.Errorf("error al registrar cargo adicional: %w", err)
+		}
+	}
+
+	if err := actualizarMontos(tx, reserva); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE reservation SET check_out_at = $1 WHERE reservation_id = $2`, cambio.Fecha, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al registrar salida: %w", err)
+	}
+
+	// Las habitaciones se marcan antes de liberar las noches, que puede cancelar alguna
+	if err := actualizarLimpiezaReserva(tx, cambio.ReservaID, domain.LimpiezaSucia); err != nil {
+		return err
+	}
+
+	if err := liberarNochesDesde(tx, cambio.ReservaID, fechaSalida, cambio.Fecha); err != nil {
+		return err
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// actualizarLimpiezaReserva cambia el estado de limpieza de las habitaciones activas de la reserva
+func actualizarLimpiezaReserva(tx *sql.Tx, reservaID int, estado string) error {
+	_, err := tx.Exec(`
+		UPDATE room
+		SET housekeeping_status = $1
+		WHERE room_id IN (
+			SELECT room_id FROM reservation_room WHERE reservation_id = $2 AND status = 1
+		)`,
+		estado, reservaID)
+	if err != nil {
+		return fmt.Errorf("error al actualizar estado de limpieza de las habitaciones: %w", err)
+	}
+
+	return nil
+}
+
+// liberarNochesDesde devuelve al inventario las noches desde la fecha dada de las habitaciones
+// activas y reservas por tipo pendientes de la reserva: acorta las estadías que siguen después de
+// esa fecha y cancela las que aún no comenzaban. El importe ya calculado no cambia.
+func liberarNochesDesde(tx *sql.Tx, reservaID int, fecha, canceladaEn time.Time) error {
+	dia := fecha.Format("2006-01-02")
+
+	_, err := tx.Exec(`
+		UPDATE reservation_room
+		SET status = 0, cancelled_at = $3
+		WHERE reservation_id = $1 AND status = 1
+			AND cast(check_in_date as date) >= cast($2 as date)`,
+		reservaID, dia, canceladaEn)
+	if err != nil {
+		return fmt.Errorf("error al liberar habitaciones de la reserva: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room
+		SET check_out_date = cast($2 as date)
+		WHERE reservation_id = $1 AND status = 1
+			AND cast(check_out_date as date) > cast($2 as date)`,
+		reservaID, dia)
+	if err != nil {
+		return fmt.Errorf("error al liberar noches de la reserva: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room_type
+		SET status = $3, cancelled_at = $4
+		WHERE reservation_id = $1 AND status = $5
+			AND cast(check_in_date as date) >= cast($2 as date)`,
+		reservaID, dia, domain.TipoReservaCancelada, canceladaEn, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return fmt.Errorf("error al liberar tipos de habitación de la reserva: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room_type
+		SET check_out_date = cast($2 as date)
+		WHERE reservation_id = $1 AND status = $3
+			AND cast(check_out_date as date) > cast($2 as date)`,
+		reservaID, dia, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return fmt.Errorf("error al liberar noches de los tipos de habitación: %w", err)
+	}
+
+	return nil
+}
+
+// cargarRecepcion completa la reserva con los huéspedes registrados en el check-in y sus cargos
+func (r *reservaRepository) cargarRecepcion(reserva *domain.Reserva) error {
+	rows, err := r.db.Query(`
+		SELECT guest_id, room_id, full_name, document_type, document_number, COALESCE(nationality, '')
+		FROM reservation_guest
+		WHERE reservation_id = $1
+		ORDER BY guest_id`,
+		reserva.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener huéspedes de la reserva: %w", err)
+	}
+	defer rows.Close()
+
+	reserva.Huespedes = nil
+	for rows.Next() {
+		huesped := domain.HuespedRegistrado{ReservaID: reserva.ID}
+		var habitacionID sql.NullInt64
+
+		err := rows.Scan(
+			&huesped.ID,
+			&habitacionID,
+			&huesped.Nombre,
+			&huesped.TipoDocumento,
+			&huesped.NumeroDocumento,
+			&huesped.Nacionalidad,
+		)
+		if err != nil {
+			return fmt.Errorf("error al escanear huésped: %w", err)
+		}
+
+		if habitacionID.Valid {
+			id := int(habitacionID.Int64)
+			huesped.HabitacionID = &id
+		}
+		reserva.Huespedes = append(reserva.Huespedes, huesped)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error al iterar huéspedes: %w", err)
+	}
+
+	cargos, err := r.db.Query(`
+		SELECT charge_id, description, amount, created_at
+		FROM reservation_charge
+		WHERE reservation_id = $1
+		ORDER BY charge_id`,
+		reserva.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener cargos de la reserva: %w", err)
+	}
+	defer cargos.Close()
+
+	reserva.Cargos = nil
+	for cargos.Next() {
+		cargo := domain.CargoAdicional{ReservaID: reserva.ID}
+		if err := cargos.Scan(&cargo.ID, &cargo.Concepto, &cargo.Monto, &cargo.Fecha); err != nil {
+			return fmt.Errorf("error al escanear cargo adicional: %w", err)
+		}
+		reserva.Cargos = append(reserva.Cargos, cargo)
+	}
+
+	if err = cargos.Err(); err != nil {
+		return fmt.Errorf("error al iterar cargos: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// CambiarEstado aplica el cambio de estado si la reserva sigue en el estado de origen y lo
+// registra en el historial
+func (r *reservaRepository) CambiarEstado(cambio *domain.CambioEstadoReserva) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// CancelarReserva cancela la reserva y sus habitaciones activas registrando la penalidad, el
+// reembolso y el cambio de estado. Las habitaciones liberadas quedan marcadas con la misma fecha
+// de cancelación que la reserva para poder restaurarlas si se reactiva.
+func (r *reservaRepository) CancelarReserva(cambio *domain.CambioEstadoReserva, penalidad *domain.PenalidadCancelacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation
+		SET cancellation_penalty = $1,
+			refund_amount = $2,
+			cancelled_at = $3
+		WHERE reservation_id = $4`,
+		penalidad.Penalidad, penalidad.Reembolso, cambio.Fecha, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al registrar penalidad de cancelación: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room
+		SET status = 0, cancelled_at = $1
+		WHERE reservation_id = $2 AND status = 1`,
+		cambio.Fecha, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al cancelar habitaciones de la reserva: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room_type
+		SET status = $1, cancelled_at = $2
+		WHERE reservation_id = $3 AND status = $4`,
+		domain.TipoReservaCancelada, cambio.Fecha, cambio.ReservaID, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return fmt.Errorf("error al cancelar tipos de habitación de la reserva: %w", err)
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// MarcarNoShow pasa la reserva a NoShow con la penalidad y el reembolso de su política de
+// cancelación y devuelve al inventario las noches desde fechaLiberacion
+func (r *reservaRepository) MarcarNoShow(cambio *domain.CambioEstadoReserva, penalidad *domain.PenalidadCancelacion, fechaLiberacion time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation
+		SET cancellation_penalty = $1,
+			refund_amount = $2
+		WHERE reservation_id = $3`,
+		penalidad.Penalidad, penalidad.Reembolso, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al registrar penalidad por no presentarse: %w", err)
+	}
+
+	if err := liberarNochesDesde(tx, cambio.ReservaID, fechaLiberacion, cambio.Fecha); err != nil {
+		return err
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// ReactivarReserva restaura las habitaciones y reservas por tipo liberadas por la cancelación de la
+// reserva verificando con los locks tomados que nadie las haya ocupado, y limpia la penalidad registrada
+func (r *reservaRepository) ReactivarReserva(cambio *domain.CambioEstadoReserva, expiraEn *time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT rh.room_id, rh.check_in_date, rh.check_out_date
+		FROM reservation_room rh
+		INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+		WHERE rh.reservation_id = $1
+		AND rh.status = 0
+		AND rh.cancelled_at = r.cancelled_at`, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al obtener habitaciones de la reserva: %w", err)
+	}
+
+	var habitaciones []domain.ReservaHabitacion
+	for rows.Next() {
+		var hab domain.ReservaHabitacion
+		if err := rows.Scan(&hab.HabitacionID, &hab.FechaEntrada, &hab.FechaSalida); err != nil {
+			rows.Close()
+			return fmt.Errorf("error al escanear habitación: %w", err)
+		}
+		habitaciones = append(habitaciones, hab)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error al recorrer habitaciones: %w", err)
+	}
+
+	var tiposCancelados int
+	err = tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM reservation_room_type rt
+		INNER JOIN reservation r ON r.reservation_id = rt.reservation_id
+		WHERE rt.reservation_id = $1
+		AND rt.status = $2
+		AND rt.cancelled_at = r.cancelled_at`, cambio.ReservaID, domain.TipoReservaCancelada).Scan(&tiposCancelados)
+	if err != nil {
+		return fmt.Errorf("error al obtener tipos de habitación de la reserva: %w", err)
+	}
+
+	if len(habitaciones) == 0 && tiposCancelados == 0 {
+		return fmt.Errorf("la reserva %d no tiene habitaciones para reactivar", cambio.ReservaID)
+	}
+
+	if err := bloquearHabitaciones(tx, habitaciones); err != nil {
+		return err
+	}
+
+	for _, hab := range habitaciones {
+		ocupada, err := habitacionOcupada(tx, hab.HabitacionID, hab.FechaEntrada, hab.FechaSalida, cambio.ReservaID)
+		if err != nil {
+			return err
+		}
+		if ocupada {
+			return &domain.HabitacionNoDisponibleError{HabitacionID: hab.HabitacionID}
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room rh
+		SET status = 1, cancelled_at = NULL
+		FROM reservation r
+		WHERE r.reservation_id = rh.reservation_id
+		AND rh.reservation_id = $1
+		AND rh.status = 0
+		AND rh.cancelled_at = r.cancelled_at`, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al reactivar habitaciones de la reserva: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room_type rt
+		SET status = $1, cancelled_at = NULL
+		FROM reservation r
+		WHERE r.reservation_id = rt.reservation_id
+		AND rt.reservation_id = $2
+		AND rt.status = $3
+		AND rt.cancelled_at = r.cancelled_at`,
+		domain.TipoReservaPorAsignar, cambio.ReservaID, domain.TipoReservaCancelada)
+	if err != nil {
+		return fmt.Errorf("error al reactivar tipos de habitación de la reserva: %w", err)
+	}
+
+	if err := actualizarEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation
+		SET cancellation_penalty = NULL,
+			refund_amount = NULL,
+			cancelled_at = NULL,
+			expires_at = $1
+		WHERE reservation_id = $2`, expiraEn, cambio.ReservaID)
+	if err != nil {
+		return fmt.Errorf("error al limpiar datos de cancelación: %w", err)
+	}
+
+	// Con la reserva vigente otra vez, sus tipos de habitación no deben quedar sobrevendidos
+	if err := verificarCapacidadReserva(tx, cambio.ReservaID); err != nil {
+		return err
+	}
+
+	if err := registrarCambioEstado(tx, cambio); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistorialEstados obtiene los cambios de estado de la reserva en orden cronológico
+func (r *reservaRepository) GetHistorialEstados(reservaID int) ([]domain.CambioEstadoReserva, error) {
+	query := `
+		SELECT
+			history_id,
+			reservation_id,
+			COALESCE(from_status, ''),
+			to_status,
+			actor,
+			changed_at
+		FROM reservation_status_history
+		WHERE reservation_id = $1
+		ORDER BY changed_at, history_id
+	`
+
+	rows, err := r.db.Query(query, reservaID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de la reserva: %w", err)
+	}
+	defer rows.Close()
+
+	historial := []domain.CambioEstadoReserva{}
+	for rows.Next() {
+		var cambio domain.CambioEstadoReserva
+		err := rows.Scan(
+			&cambio.ID,
+			&cambio.ReservaID,
+			&cambio.Desde,
+			&cambio.Hacia,
+			&cambio.Actor,
+			&cambio.Fecha,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear historial: %w", err)
+		}
+		historial = append(historial, cambio)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer historial: %w", err)
+	}
+
+	return historial, nil
+}
+
+// actualizarEstado cambia el estado de la reserva solo si sigue en el estado de origen, de modo
+// que dos transiciones concurrentes no se pisen. Al salir de Pendiente se descarta el hold.
+func actualizarEstado(tx *sql.Tx, cambio *domain.CambioEstadoReserva) error {
+	result, err := tx.Exec(`
+		UPDATE reservation
+		SET status = $1,
+			expires_at = CASE WHEN $4 THEN NULL ELSE expires_at END
+		WHERE reservation_id = $2 AND status = $3`,
+		cambio.Hacia, cambio.ReservaID, cambio.Desde, cambio.Hacia != domain.ReservaPendiente)
+	if err != nil {
+		return fmt.Errorf("error al actualizar status de reserva: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var actual domain.EstadoReserva
+	err = tx.QueryRow(`SELECT status FROM reservation WHERE reservation_id = $1`, cambio.ReservaID).Scan(&actual)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("reserva con ID %d no encontrada", cambio.ReservaID)
+	}
+	if err != nil {
+		return fmt.Errorf("error al obtener status de reserva: %w", err)
+	}
+
+	return &domain.TransicionInvalidaError{Desde: actual, Hacia: cambio.Hacia}
+}
+
+// registrarCambioEstado agrega la transición al historial de la reserva
+func registrarCambioEstado(tx *sql.Tx, cambio *domain.CambioEstadoReserva) error {
+	if cambio.Fecha.IsZero() {
+		cambio.Fecha = time.Now()
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO reservation_status_history (reservation_id, from_status, to_status, actor, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING history_id`,
+		cambio.ReservaID, nullString(string(cambio.Desde)), cambio.Hacia, cambio.Actor, cambio.Fecha,
+	).Scan(&cambio.ID)
+	if err != nil {
+		return fmt.Errorf("error al registrar historial de estado: %w", err)
+	}
+
+	return nil
+}
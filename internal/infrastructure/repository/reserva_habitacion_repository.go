@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -26,11 +27,17 @@ func (r *reservaHabitacionRepository) CreateReservaHabitacion(reservaHabitacion
 			price,
 			check_in_date,
 			check_out_date,
-			status
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			status,
+			price_breakdown
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := r.db.Exec(
+	desglose, err := json.Marshal(reservaHabitacion.Desglose)
+	if err != nil {
+		return fmt.Errorf("error al serializar desglose de precios: %w", err)
+	}
+
+	_, err = r.db.Exec(
 		query,
 		reservaHabitacion.ReservaID,
 		reservaHabitacion.HabitacionID,
@@ -38,6 +45,7 @@ func (r *reservaHabitacionRepository) CreateReservaHabitacion(reservaHabitacion
 		reservaHabitacion.FechaEntrada,
 		reservaHabitacion.FechaSalida,
 		reservaHabitacion.Estado,
+		desglose,
 	)
 
 	if err != nil {
@@ -50,16 +58,7 @@ func (r *reservaHabitacionRepository) CreateReservaHabitacion(reservaHabitacion
 // GetReservaHabitacionesByReservaID obtiene todas las habitaciones de una reserva
 func (r *reservaHabitacionRepository) GetReservaHabitacionesByReservaID(reservaID int) ([]domain.ReservaHabitacion, error) {
 	query := `
-		SELECT 
-			rh.reservation_id,
-			rh.room_id,
-			rh.price,
-			rh.check_in_date,
-			rh.check_out_date,
-			rh.status,
-			h.name,
-			h.capacity,
-			h.number
+		SELECT` + columnasReservaHabitacion + `
 		FROM reservation_room rh
 		INNER JOIN room h ON h.room_id = rh.room_id
 		WHERE rh.reservation_id = $1
@@ -73,26 +72,10 @@ func (r *reservaHabitacionRepository) GetReservaHabitacionesByReservaID(reservaI
 
 	var reservasHabitacion []domain.ReservaHabitacion
 	for rows.Next() {
-		var rh domain.ReservaHabitacion
-		var habitacion domain.Habitacion
-
-		err := rows.Scan(
-			&rh.ReservaID,
-			&rh.HabitacionID,
-			&rh.Precio,
-			&rh.FechaEntrada,
-			&rh.FechaSalida,
-			&rh.Estado,
-			&habitacion.Nombre,
-			&habitacion.Capacidad,
-			&habitacion.Numero,
-		)
+		rh, err := scanReservaHabitacion(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error al escanear reserva de habitación: %w", err)
 		}
-
-		habitacion.ID = rh.HabitacionID
-		rh.Habitacion = &habitacion
 		reservasHabitacion = append(reservasHabitacion, rh)
 	}
 
@@ -126,46 +109,151 @@ func (r *reservaHabitacionRepository) UpdateReservaHabitacionEstado(reservaID, h
 
 // VerificarDisponibilidad verifica si una habitación está disponible para las fechas dadas
 func (r *reservaHabitacionRepository) VerificarDisponibilidad(habitacionID int, fechaEntrada, fechaSalida time.Time) (bool, error) {
+	return r.VerificarDisponibilidadExcluyendo(habitacionID, fechaEntrada, fechaSalida, 0)
+}
+
+// VerificarDisponibilidadExcluyendo verifica la disponibilidad sin considerar las habitaciones de la reserva dada
+func (r *reservaHabitacionRepository) VerificarDisponibilidadExcluyendo(habitacionID int, fechaEntrada, fechaSalida time.Time, reservaID int) (bool, error) {
+	ocupada, err := habitacionOcupada(r.db, habitacionID, fechaEntrada, fechaSalida, reservaID)
+	if err != nil {
+		return false, err
+	}
+
+	return !ocupada, nil
+}
+
+// queryRower permite ejecutar la misma consulta sobre *sql.DB o dentro de una *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// condicionReservaVigente filtra las reservas que bloquean sus habitaciones: las no canceladas y,
+// si están pendientes, solo mientras su hold no haya vencido. Requiere el alias r (reservation).
+const condicionReservaVigente = `r.status NOT IN ('Cancelada')
+		AND (r.status <> 'Pendiente' OR r.expires_at IS NULL OR r.expires_at > NOW())`
+
+// habitacionOcupada indica si existe alguna reserva activa o bloqueo de la habitación que se solape con
+// el rango dado. Las habitaciones de excluirReservaID no cuentan (0 no excluye ninguna).
+func habitacionOcupada(q queryRower, habitacionID int, fechaEntrada, fechaSalida time.Time, excluirReservaID int) (bool, error) {
 	query := `
 		SELECT COUNT(*) 
 		FROM reservation_room rh
 		INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
 		WHERE rh.room_id = $1 
 		AND rh.status = 1
-		AND r.status NOT IN ('Cancelada')
+		AND ` + condicionReservaVigente + `
+		AND rh.reservation_id <> $4
 		AND (
 			(rh.check_in_date < $3 AND rh.check_out_date > $2)
 		)
 	`
+	query = `SELECT (` + query + `) + (
+		SELECT COUNT(*)
+		FROM room h
+		WHERE h.room_id = $1
+		AND NOT ` + condicionSinBloqueo("h.room_id", "$2", "$3") + `
+	)`
 
 	var count int
-	err := r.db.QueryRow(query, habitacionID, fechaEntrada, fechaSalida).Scan(&count)
+	err := q.QueryRow(query, habitacionID, fechaEntrada, fechaSalida, excluirReservaID).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("error al verificar disponibilidad: %w", err)
 	}
 
 	// Si count es 0, la habitación está disponible
-	return count == 0, nil
+	return count > 0, nil
+}
+
+// GetHabitacionesCandidatas obtiene las habitaciones en servicio del tipo libres durante todo el
+// rango, con el fin de la ocupación (reserva o bloqueo) anterior y el inicio de la siguiente
+func (r *reservaHabitacionRepository) GetHabitacionesCandidatas(tipoHabitacionID int, fechaEntrada, fechaSalida time.Time) ([]domain.HabitacionCandidata, error) {
+	query := `
+		SELECT
+			h.room_id,
+			(
+				SELECT MAX(fin) FROM (
+					SELECT rh.check_out_date AS fin
+					FROM reservation_room rh
+					INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+					WHERE rh.room_id = h.room_id
+					AND rh.status = 1
+					AND ` + condicionReservaVigente + `
+					AND rh.check_out_date <= $2
+					UNION ALL
+					SELECT b.end_date
+					FROM room_block b
+					WHERE b.room_id = h.room_id AND b.end_date <= cast($2 as date)
+				) anteriores
+			),
+			(
+				SELECT MIN(inicio) FROM (
+					SELECT rh.check_in_date AS inicio
+					FROM reservation_room rh
+					INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+					WHERE rh.room_id = h.room_id
+					AND rh.status = 1
+					AND ` + condicionReservaVigente + `
+					AND rh.check_in_date >= $3
+					UNION ALL
+					SELECT b.start_date
+					FROM room_block b
+					WHERE b.room_id = h.room_id AND b.start_date >= cast($3 as date)
+				) siguientes
+			)
+		FROM room h
+		WHERE h.room_type_id = $1
+		AND h.status = 'Disponible'
+		AND NOT EXISTS (
+			SELECT 1
+			FROM reservation_room rh
+			INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+			WHERE rh.room_id = h.room_id
+			AND rh.status = 1
+			AND ` + condicionReservaVigente + `
+			AND rh.check_in_date < $3 AND rh.check_out_date > $2
+		)
+		AND ` + condicionSinBloqueo("h.room_id", "$2", "$3") + `
+		ORDER BY h.room_id
+	`
+
+	rows, err := r.db.Query(query, tipoHabitacionID, fechaEntrada, fechaSalida)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener habitaciones candidatas: %w", err)
+	}
+	defer rows.Close()
+
+	var candidatas []domain.HabitacionCandidata
+	for rows.Next() {
+		var candidata domain.HabitacionCandidata
+		var finAnterior, inicioSiguiente sql.NullTime
+		if err := rows.Scan(&candidata.HabitacionID, &finAnterior, &inicioSiguiente); err != nil {
+			return nil, fmt.Errorf("error al escanear habitación candidata: %w", err)
+		}
+		if finAnterior.Valid {
+			candidata.FinAnterior = &finAnterior.Time
+		}
+		if inicioSiguiente.Valid {
+			candidata.InicioSiguiente = &inicioSiguiente.Time
+		}
+		candidatas = append(candidatas, candidata)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer habitaciones candidatas: %w", err)
+	}
+
+	return candidatas, nil
 }
 
 // GetReservasEnRango obtiene todas las reservas activas en un rango de fechas
 func (r *reservaHabitacionRepository) GetReservasEnRango(fechaInicio, fechaFin time.Time) ([]domain.ReservaHabitacion, error) {
 	query := `
-		SELECT 
-			rh.reservaid,
-			rh.habitacionid,
-			rh.precio,
-			rh.fechaentrada,
-			rh.fechasalida,
-			rh.status,
-			h.name,
-			h.capacity,
-			h.number
+		SELECT` + columnasReservaHabitacion + `
 		FROM reservation_room rh
 		INNER JOIN room h ON h.room_id = rh.room_id
 		INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
 		WHERE rh.status = 1
-		AND r.status NOT IN ('Cancelada')
+		AND ` + condicionReservaVigente + `
 		AND (
 			(rh.check_in_date BETWEEN $1 AND $2)
 			OR (rh.check_out_date BETWEEN $1 AND $2)
@@ -182,28 +270,64 @@ func (r *reservaHabitacionRepository) GetReservasEnRango(fechaInicio, fechaFin t
 
 	var reservasHabitacion []domain.ReservaHabitacion
 	for rows.Next() {
-		var rh domain.ReservaHabitacion
-		var habitacion domain.Habitacion
-
-		err := rows.Scan(
-			&rh.ReservaID,
-			&rh.HabitacionID,
-			&rh.Precio,
-			&rh.FechaEntrada,
-			&rh.FechaSalida,
-			&rh.Estado,
-			&habitacion.Nombre,
-			&habitacion.Capacidad,
-			&habitacion.Numero,
-		)
+		rh, err := scanReservaHabitacion(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error al escanear reserva: %w", err)
 		}
-
-		habitacion.ID = rh.HabitacionID
-		rh.Habitacion = &habitacion
 		reservasHabitacion = append(reservasHabitacion, rh)
 	}
 
 	return reservasHabitacion, nil
 }
+
+// columnasReservaHabitacion es la lista de columnas que espera scanReservaHabitacion.
+// Requiere los alias rh (reservation_room) y h (room).
+const columnasReservaHabitacion = `
+			rh.reservation_id,
+			rh.room_id,
+			rh.price,
+			rh.check_in_date,
+			rh.check_out_date,
+			rh.status,
+			COALESCE(rh.price_breakdown, '[]'),
+			COALESCE(rh.guest_name, ''),
+			h.name,
+			h.capacity,
+			h.number`
+
+// rowScanner abstrae *sql.Row y *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanReservaHabitacion escanea una fila seleccionada con columnasReservaHabitacion
+func scanReservaHabitacion(row rowScanner) (domain.ReservaHabitacion, error) {
+	var rh domain.ReservaHabitacion
+	var habitacion domain.Habitacion
+	var desglose []byte
+
+	err := row.Scan(
+		&rh.ReservaID,
+		&rh.HabitacionID,
+		&rh.Precio,
+		&rh.FechaEntrada,
+		&rh.FechaSalida,
+		&rh.Estado,
+		&desglose,
+		&rh.NombreHuesped,
+		&habitacion.Nombre,
+		&habitacion.Capacidad,
+		&habitacion.Numero,
+	)
+	if err != nil {
+		return rh, err
+	}
+
+	if err := json.Unmarshal(desglose, &rh.Desglose); err != nil {
+		return rh, fmt.Errorf("error al leer desglose de precios: %w", err)
+	}
+
+	habitacion.ID = rh.HabitacionID
+	rh.Habitacion = &habitacion
+	return rh, nil
+}
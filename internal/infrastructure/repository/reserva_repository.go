@@ -2,11 +2,19 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
 )
 
+// codigoUniqueViolation es el código de error de PostgreSQL para una restricción única violada
+const codigoUniqueViolation = "23505"
+
 type reservaRepository struct {
 	db *sql.DB
 }
@@ -19,56 +27,186 @@ func NewReservaRepository(db *sql.DB) domain.ReservaRepository {
 // GetReservaByID obtiene una reserva por su ID con sus habitaciones
 func (r *reservaRepository) GetReservaByID(id int) (*domain.Reserva, error) {
 	query := `
-		SELECT 
+		SELECT` + columnasReserva + `
+		FROM reservation r
+		LEFT JOIN promotion p ON p.promotion_id = r.promotion_id
+		WHERE r.reservation_id = $1
+	`
+
+	reserva, err := scanReserva(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: ID %d", domain.ErrReservaNoEncontrada, id)
+		}
+		return nil, fmt.Errorf("error al obtener reserva: %w", err)
+	}
+
+	// Obtener las habitaciones de la reserva
+	if err := r.cargarHabitaciones(reserva); err != nil {
+		return nil, err
+	}
+
+	return reserva, nil
+}
+
+// GetReservaByCodigo obtiene una reserva por su código de confirmación con sus habitaciones
+func (r *reservaRepository) GetReservaByCodigo(codigo string) (*domain.Reserva, error) {
+	query := `
+		SELECT` + columnasReserva + `
+		FROM reservation r
+		LEFT JOIN promotion p ON p.promotion_id = r.promotion_id
+		WHERE r.code = $1
+	`
+
+	reserva, err := scanReserva(r.db.QueryRow(query, codigo))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReservaNoEncontrada
+		}
+		return nil, fmt.Errorf("error al obtener reserva: %w", err)
+	}
+
+	if err := r.cargarHabitaciones(reserva); err != nil {
+		return nil, err
+	}
+
+	return reserva, nil
+}
+
+// columnasReserva es la lista de columnas que espera scanReserva.
+// Requiere los alias r (reservation) y p (promotion, con LEFT JOIN).
+const columnasReserva = `
 			r.reservation_id,
+			r.code,
 			r.adults_count,
 			r.children_count,
 			r.status,
 			r.client_id,
+			r.client_profile_id,
 			r.subtotal,
 			r.discount,
-			r.confirmation_date
-		FROM reservation r
-		WHERE r.reservation_id = $1
-	`
-
+			r.promotion_id,
+			COALESCE(p.code, ''),
+			r.service_charge,
+			r.igv,
+			r.igv_exempt,
+			COALESCE(r.total, r.subtotal - r.discount),
+			COALESCE(r.guest_document_type, ''),
+			COALESCE(r.guest_document_number, ''),
+			COALESCE(r.guest_nationality, ''),
+			r.confirmation_date,
+			r.expires_at,
+			r.cancellation_penalty,
+			r.refund_amount,
+			r.cancelled_at,
+			r.check_in_at,
+			r.check_out_at`
+
+// scanReserva escanea una fila seleccionada con columnasReserva
+func scanReserva(row rowScanner) (*domain.Reserva, error) {
 	reserva := &domain.Reserva{}
-	err := r.db.QueryRow(query, id).Scan(
+	var perfilClienteID, promocionID sql.NullInt64
+	var penalidad, reembolso sql.NullFloat64
+	var expiraEn, fechaCancelacion, fechaCheckIn, fechaCheckOut sql.NullTime
+
+	err := row.Scan(
 		&reserva.ID,
+		&reserva.CodigoReserva,
 		&reserva.CantidadAdultos,
 		&reserva.CantidadNinhos,
 		&reserva.Estado,
 		&reserva.ClienteID,
+		&perfilClienteID,
 		&reserva.Subtotal,
 		&reserva.Descuento,
+		&promocionID,
+		&reserva.CodigoPromocion,
+		&reserva.CargoServicio,
+		&reserva.IGV,
+		&reserva.ExoneradoIGV,
+		&reserva.Total,
+		&reserva.TipoDocumento,
+		&reserva.NumeroDocumento,
+		&reserva.Nacionalidad,
 		&reserva.FechaConfirmacion,
+		&expiraEn,
+		&penalidad,
+		&reembolso,
+		&fechaCancelacion,
+		&fechaCheckIn,
+		&fechaCheckOut,
 	)
+	if err != nil {
+		return nil, err
+	}
 
+	if perfilClienteID.Valid {
+		id := int(perfilClienteID.Int64)
+		reserva.PerfilClienteID = &id
+	}
+	if promocionID.Valid {
+		id := int(promocionID.Int64)
+		reserva.PromocionID = &id
+	}
+	if expiraEn.Valid {
+		reserva.ExpiraEn = &expiraEn.Time
+	}
+	if penalidad.Valid {
+		reserva.Penalidad = &penalidad.Float64
+	}
+	if reembolso.Valid {
+		reserva.Reembolso = &reembolso.Float64
+	}
+	if fechaCancelacion.Valid {
+		reserva.FechaCancelacion = &fechaCancelacion.Time
+	}
+	if fechaCheckIn.Valid {
+		reserva.FechaCheckIn = &fechaCheckIn.Time
+	}
+	if fechaCheckOut.Valid {
+		reserva.FechaCheckOut = &fechaCheckOut.Time
+	}
+
+	return reserva, nil
+}
+
+// nullString convierte un texto vacío en NULL
+func nullString(valor string) sql.NullString {
+	return sql.NullString{String: valor, Valid: valor != ""}
+}
+
+// cargarHabitaciones completa la reserva con sus habitaciones activas, las reservas por tipo
+// que aún no tienen habitación asignada y, si ya hizo check-in, sus huéspedes y cargos
+func (r *reservaRepository) cargarHabitaciones(reserva *domain.Reserva) error {
+	habitaciones, err := r.getHabitacionesActivas(reserva.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("reserva con ID %d no encontrada", id)
-		}
-		return nil, fmt.Errorf("error al obtener reserva: %w", err)
+		return err
 	}
 
-	// Obtener las habitaciones de la reserva
+	tipos, err := r.getTiposPorAsignar(reserva.ID)
+	if err != nil {
+		return err
+	}
+
+	reserva.Habitaciones = habitaciones
+	reserva.TiposHabitacion = tipos
+
+	if reserva.FechaCheckIn != nil {
+		return r.cargarRecepcion(reserva)
+	}
+	return nil
+}
+
+// getHabitacionesActivas obtiene las habitaciones activas de una reserva
+func (r *reservaRepository) getHabitacionesActivas(reservaID int) ([]domain.ReservaHabitacion, error) {
 	habitacionesQuery := `
-		SELECT 
-			rh.reservation_id,
-			rh.room_id,
-			rh.price,
-			rh.check_in_date,
-			rh.check_out_date,
-			rh.status,
-			h.name,
-			h.capacity,
-			h.number
+		SELECT` + columnasReservaHabitacion + `
 		FROM reservation_room rh
 		INNER JOIN room h ON h.room_id = rh.room_id
 		WHERE rh.reservation_id = $1 AND rh.status = 1
 	`
 
-	rows, err := r.db.Query(habitacionesQuery, id)
+	rows, err := r.db.Query(habitacionesQuery, reservaID)
 	if err != nil {
 		return nil, fmt.Errorf("error al obtener habitaciones de la reserva: %w", err)
 	}
@@ -76,72 +214,308 @@ func (r *reservaRepository) GetReservaByID(id int) (*domain.Reserva, error) {
 
 	var habitaciones []domain.ReservaHabitacion
 	for rows.Next() {
-		var rh domain.ReservaHabitacion
-		var habitacion domain.Habitacion
-
-		err := rows.Scan(
-			&rh.ReservaID,
-			&rh.HabitacionID,
-			&rh.Precio,
-			&rh.FechaEntrada,
-			&rh.FechaSalida,
-			&rh.Estado,
-			&habitacion.Nombre,
-			&habitacion.Capacidad,
-			&habitacion.Numero,
-		)
+		rh, err := scanReservaHabitacion(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error al escanear habitación: %w", err)
 		}
-
-		habitacion.ID = rh.HabitacionID
-		rh.Habitacion = &habitacion
 		habitaciones = append(habitaciones, rh)
 	}
 
-	reserva.Habitaciones = habitaciones
-	return reserva, nil
+	return habitaciones, nil
 }
 
 // CreateReserva crea una nueva reserva
-func (r *reservaRepository) CreateReserva(reserva *domain.Reserva) error {
+func (r *reservaRepository) CreateReserva(reserva *domain.Reserva, actor string) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return fmt.Errorf("error al iniciar transacción: %w", err)
 	}
 	defer tx.Rollback()
 
+	// Bloquear las habitaciones para serializar reservas concurrentes sobre ellas
+	if err := bloquearHabitaciones(tx, reserva.Habitaciones); err != nil {
+		return err
+	}
+
+	// Vincular la reserva al perfil del cliente, creándolo si es su primera reserva
+	perfilID, err := vincularCliente(tx, domain.Cliente{
+		Email:           reserva.ClienteID,
+		TipoDocumento:   reserva.TipoDocumento,
+		NumeroDocumento: reserva.NumeroDocumento,
+		Nacionalidad:    reserva.Nacionalidad,
+	})
+	if err != nil {
+		return err
+	}
+	reserva.PerfilClienteID = &perfilID
+
 	// Insertar la reserva principal
 	query := `
 		INSERT INTO reservation (
+			code,
 			adults_count,
 			children_count,
 			status,
 			client_id,
+			client_profile_id,
 			subtotal,
 			discount,
-			confirmation_date
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			promotion_id,
+			service_charge,
+			igv,
+			igv_exempt,
+			total,
+			guest_document_type,
+			guest_document_number,
+			guest_nationality,
+			confirmation_date,
+			expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING reservation_id
 	`
 
 	err = tx.QueryRow(
 		query,
+		reserva.CodigoReserva,
 		reserva.CantidadAdultos,
 		reserva.CantidadNinhos,
 		reserva.Estado,
 		reserva.ClienteID,
+		reserva.PerfilClienteID,
 		reserva.Subtotal,
 		reserva.Descuento,
+		reserva.PromocionID,
+		reserva.CargoServicio,
+		reserva.IGV,
+		reserva.ExoneradoIGV,
+		reserva.Total,
+		nullString(reserva.TipoDocumento),
+		nullString(reserva.NumeroDocumento),
+		nullString(reserva.Nacionalidad),
 		reserva.FechaConfirmacion,
+		reserva.ExpiraEn,
 	).Scan(&reserva.ID)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == codigoUniqueViolation && pqErr.Constraint == "idx_reservation_code" {
+			return domain.ErrCodigoReservaDuplicado
+		}
 		return fmt.Errorf("error al crear reserva: %w", err)
 	}
 
+	// El historial comienza con el estado inicial, sin estado anterior
+	if err := registrarCambioEstado(tx, &domain.CambioEstadoReserva{
+		ReservaID: reserva.ID,
+		Hacia:     reserva.Estado,
+		Actor:     actor,
+	}); err != nil {
+		return err
+	}
+
+	// Registrar el canje de la promoción respetando sus límites de uso
+	if reserva.PromocionID != nil {
+		if err := registrarCanje(tx, reserva); err != nil {
+			return err
+		}
+	}
+
 	// Insertar las habitaciones de la reserva
+	if err := insertarHabitaciones(tx, reserva); err != nil {
+		return err
+	}
+
+	if err := insertarTiposHabitacion(tx, reserva); err != nil {
+		return err
+	}
+
+	// Con todo insertado, ningún tipo de habitación debe quedar sobrevendido
+	if err := verificarCapacidadReserva(tx, reserva.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// ModificarReserva actualiza la reserva y reemplaza sus habitaciones activas en una sola transacción
+func (r *reservaRepository) ModificarReserva(reserva *domain.Reserva) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearReservaModificable(tx, reserva.ID); err != nil {
+		return err
+	}
+
+	// Bloquear las habitaciones nuevas antes de liberar las actuales
+	if err := bloquearHabitaciones(tx, reserva.Habitaciones); err != nil {
+		return err
+	}
+
+	if err := actualizarMontos(tx, reserva); err != nil {
+		return err
+	}
+
+	// Reemplazar las habitaciones activas; las canceladas se conservan como historial
+	if _, err := tx.Exec(`DELETE FROM reservation_room WHERE reservation_id = $1 AND status = 1`, reserva.ID); err != nil {
+		return fmt.Errorf("error al liberar habitaciones de la reserva: %w", err)
+	}
+
+	if err := insertarHabitaciones(tx, reserva); err != nil {
+		return err
+	}
+
+	if err := verificarCapacidadReserva(tx, reserva.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// CancelarHabitacion cancela una habitación de la reserva con su penalidad y reembolso y guarda
+// los montos recalculados con las habitaciones restantes en una sola transacción
+func (r *reservaRepository) CancelarHabitacion(reserva *domain.Reserva, habitacionID int, penalidad *domain.PenalidadCancelacion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := bloquearReservaModificable(tx, reserva.ID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE reservation_room
+		SET status = 0,
+			cancelled_at = $3,
+			cancellation_penalty = $4,
+			refund_amount = $5
+		WHERE reservation_id = $1 AND room_id = $2 AND status = 1`,
+		reserva.ID, habitacionID, penalidad.FechaCalculo, penalidad.Penalidad, penalidad.Reembolso)
+	if err != nil {
+		return fmt.Errorf("error al cancelar habitación: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("la habitación %d no está activa en la reserva %d", habitacionID, reserva.ID)
+	}
+
+	if err := actualizarMontos(tx, reserva); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// bloquearReservaModificable toma el lock de fila de la reserva y verifica que siga Pendiente o
+// Confirmada, para que una cancelación en paralelo no deje habitaciones activas en una reserva
+// cancelada
+func bloquearReservaModificable(tx *sql.Tx, id int) error {
+	var estado domain.EstadoReserva
+	err := tx.QueryRow(`SELECT status FROM reservation WHERE reservation_id = $1 FOR UPDATE`, id).Scan(&estado)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: ID %d", domain.ErrReservaNoEncontrada, id)
+	}
+	if err != nil {
+		return fmt.Errorf("error al bloquear reserva: %w", err)
+	}
+
+	if estado != domain.ReservaPendiente && estado != domain.ReservaConfirmada {
+		return fmt.Errorf("no se puede modificar una reserva en estado %s", estado)
+	}
+	return nil
+}
+
+// actualizarMontos guarda los huéspedes y montos de la reserva y mantiene el canje de su
+// promoción alineado con el descuento
+func actualizarMontos(tx *sql.Tx, reserva *domain.Reserva) error {
+	query := `
+		UPDATE reservation
+		SET adults_count = $1,
+			children_count = $2,
+			subtotal = $3,
+			discount = $4,
+			promotion_id = $5,
+			service_charge = $6,
+			igv = $7,
+			igv_exempt = $8,
+			total = $9
+		WHERE reservation_id = $10
+	`
+
+	result, err := tx.Exec(
+		query,
+		reserva.CantidadAdultos,
+		reserva.CantidadNinhos,
+		reserva.Subtotal,
+		reserva.Descuento,
+		reserva.PromocionID,
+		reserva.CargoServicio,
+		reserva.IGV,
+		reserva.ExoneradoIGV,
+		reserva.Total,
+		reserva.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error al actualizar reserva: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reserva con ID %d no encontrada", reserva.ID)
+	}
+
+	// Mantener el canje de la promoción alineado con el nuevo descuento
+	if reserva.PromocionID == nil {
+		_, err = tx.Exec(`DELETE FROM promotion_redemption WHERE reservation_id = $1`, reserva.ID)
+	} else {
+		_, err = tx.Exec(`UPDATE promotion_redemption SET discount = $1 WHERE reservation_id = $2`, reserva.Descuento, reserva.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("error al actualizar canje de la promoción: %w", err)
+	}
+
+	return nil
+}
+
+// insertarHabitaciones inserta las habitaciones de la reserva verificando su disponibilidad.
+// Debe llamarse con los locks de bloquearHabitaciones ya tomados.
+func insertarHabitaciones(tx *sql.Tx, reserva *domain.Reserva) error {
 	for i := range reserva.Habitaciones {
+		// Verificar la disponibilidad ya con el lock tomado. Las filas insertadas en esta misma
+		// transacción también cuentan, lo que cubre habitaciones repetidas en la solicitud.
+		ocupada, err := habitacionOcupada(tx, reserva.Habitaciones[i].HabitacionID, reserva.Habitaciones[i].FechaEntrada, reserva.Habitaciones[i].FechaSalida, 0)
+		if err != nil {
+			return err
+		}
+		if ocupada {
+			return &domain.HabitacionNoDisponibleError{HabitacionID: reserva.Habitaciones[i].HabitacionID}
+		}
+
 		habitacionQuery := `
 			INSERT INTO reservation_room (
 				reservation_id,
@@ -149,10 +523,16 @@ func (r *reservaRepository) CreateReserva(reserva *domain.Reserva) error {
 				price,
 				check_in_date,
 				check_out_date,
-				status
-			) VALUES ($1, $2, $3, $4, $5, $6)
+				status,
+				price_breakdown
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
 		`
 
+		desglose, err := json.Marshal(reserva.Habitaciones[i].Desglose)
+		if err != nil {
+			return fmt.Errorf("error al serializar desglose de precios: %w", err)
+		}
+
 		_, err = tx.Exec(
 			habitacionQuery,
 			reserva.ID,
@@ -161,6 +541,7 @@ func (r *reservaRepository) CreateReserva(reserva *domain.Reserva) error {
 			reserva.Habitaciones[i].FechaEntrada,
 			reserva.Habitaciones[i].FechaSalida,
 			1, // status activo
+			desglose,
 		)
 
 		if err != nil {
@@ -171,52 +552,121 @@ func (r *reservaRepository) CreateReserva(reserva *domain.Reserva) error {
 		reserva.Habitaciones[i].Estado = 1
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("error al confirmar transacción: %w", err)
+	return nil
+}
+
+// bloquearHabitaciones toma un lock de fila sobre cada habitación involucrada. Los ids se
+// recorren en orden ascendente para que dos transacciones nunca se bloqueen mutuamente.
+// Retorna *domain.HabitacionNoDisponibleError si alguna habitación está fuera de servicio.
+func bloquearHabitaciones(tx *sql.Tx, habitaciones []domain.ReservaHabitacion) error {
+	vistos := make(map[int]bool, len(habitaciones))
+	ids := make([]int, 0, len(habitaciones))
+	for _, hab := range habitaciones {
+		if !vistos[hab.HabitacionID] {
+			vistos[hab.HabitacionID] = true
+			ids = append(ids, hab.HabitacionID)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		var estado string
+		err := tx.QueryRow(`SELECT status FROM room WHERE room_id = $1 FOR UPDATE`, id).Scan(&estado)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("habitación %d no encontrada", id)
+			}
+			return fmt.Errorf("error al bloquear habitación %d: %w", id, err)
+		}
+
+		// Una habitación fuera de servicio no se puede reservar
+		if estado != domain.HabitacionDisponible {
+			return &domain.HabitacionNoDisponibleError{HabitacionID: id}
+		}
 	}
 
 	return nil
 }
 
-// UpdateReservastatus actualiza el status de una reserva
-func (r *reservaRepository) UpdateReservaEstado(id int, status domain.EstadoReserva) error {
+// GetReservasExpiradas obtiene las reservas pendientes cuyo hold venció, sin sus habitaciones
+func (r *reservaRepository) GetReservasExpiradas(ahora time.Time) ([]domain.Reserva, error) {
 	query := `
-		UPDATE reservation 
-		SET status = $1 
-		WHERE reservation_id = $2
+		SELECT` + columnasReserva + `
+		FROM reservation r
+		LEFT JOIN promotion p ON p.promotion_id = r.promotion_id
+		WHERE r.status = $1
+		AND r.expires_at <= $2
+		ORDER BY r.expires_at
 	`
 
-	result, err := r.db.Exec(query, status, id)
+	rows, err := r.db.Query(query, domain.ReservaPendiente, ahora)
 	if err != nil {
-		return fmt.Errorf("error al actualizar status de reserva: %w", err)
+		return nil, fmt.Errorf("error al obtener reservas expiradas: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	var reservas []domain.Reserva
+	for rows.Next() {
+		reserva, err := scanReserva(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear reserva: %w", err)
+		}
+		reservas = append(reservas, *reserva)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer reservas expiradas: %w", err)
+	}
+
+	return reservas, nil
+}
+
+// GetReservasNoShow obtiene las reservas confirmadas cuya primera fecha de entrada, entre sus
+// habitaciones activas y reservas por tipo pendientes, está entre desde y hasta
+func (r *reservaRepository) GetReservasNoShow(desde, hasta time.Time) ([]domain.Reserva, error) {
+	query := `
+		SELECT` + columnasReserva + `
+		FROM reservation r
+		LEFT JOIN promotion p ON p.promotion_id = r.promotion_id
+		WHERE r.status = $1
+		AND cast(LEAST(
+			(SELECT MIN(rh.check_in_date) FROM reservation_room rh
+			 WHERE rh.reservation_id = r.reservation_id AND rh.status = 1),
+			(SELECT MIN(rt.check_in_date) FROM reservation_room_type rt
+			 WHERE rt.reservation_id = r.reservation_id AND rt.status = $4)
+		) as date) BETWEEN cast($2 as date) AND cast($3 as date)
+		ORDER BY r.reservation_id
+	`
+
+	rows, err := r.db.Query(query, domain.ReservaConfirmada, desde.Format("2006-01-02"), hasta.Format("2006-01-02"), domain.TipoReservaPorAsignar)
 	if err != nil {
-		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+		return nil, fmt.Errorf("error al obtener reservas no presentadas: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("reserva con ID %d no encontrada", id)
+	var reservas []domain.Reserva
+	for rows.Next() {
+		reserva, err := scanReserva(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear reserva: %w", err)
+		}
+		reservas = append(reservas, *reserva)
 	}
 
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer reservas no presentadas: %w", err)
+	}
+
+	return reservas, nil
 }
 
 // GetReservasCliente obtiene todas las reservas de un cliente
 func (r *reservaRepository) GetReservasCliente(client_id string) ([]domain.Reserva, error) {
 	query := `
-		SELECT 
-			r.reservation_id,
-			r.adults_count,
-			r.cantidadniños,
-			r.status,
-			r.client_id,
-			r.subtotal,
-			r.discount,
-			r.confirmation_date
+		SELECT` + columnasReserva + `
 		FROM reservation r
-		WHERE r.client_id = $1
+		LEFT JOIN promotion p ON p.promotion_id = r.promotion_id
+		WHERE lower(r.client_id) = lower($1)
 		ORDER BY r.confirmation_date DESC
 	`
 
@@ -228,72 +678,17 @@ func (r *reservaRepository) GetReservasCliente(client_id string) ([]domain.Reser
 
 	var reservas []domain.Reserva
 	for rows.Next() {
-		var reserva domain.Reserva
-		err := rows.Scan(
-			&reserva.ID,
-			&reserva.CantidadAdultos,
-			&reserva.CantidadNinhos,
-			&reserva.Estado,
-			&reserva.ClienteID,
-			&reserva.Subtotal,
-			&reserva.Descuento,
-			&reserva.FechaConfirmacion,
-		)
+		reserva, err := scanReserva(rows)
 		if err != nil {
 			return nil, fmt.Errorf("error al escanear reserva: %w", err)
 		}
 
 		// Obtener las habitaciones de cada reserva
-		habitacionesQuery := `
-			SELECT 
-				rh.reservation_id,
-				rh.room_id,
-				rh.price,
-				rh.check_in_date,
-				rh.check_out_date,
-				rh.status,
-				h.name,
-				h.capacity,
-				h.number
-			FROM reservation_room rh
-			INNER JOIN room h ON h.room_id = rh.room_id
-			WHERE rh.reservation_id = $1 AND rh.status = 1
-		`
-
-		habRows, err := r.db.Query(habitacionesQuery, reserva.ID)
-		if err != nil {
-			return nil, fmt.Errorf("error al obtener habitaciones: %w", err)
-		}
-
-		var habitaciones []domain.ReservaHabitacion
-		for habRows.Next() {
-			var rh domain.ReservaHabitacion
-			var habitacion domain.Habitacion
-
-			err := habRows.Scan(
-				&rh.ReservaID,
-				&rh.HabitacionID,
-				&rh.Precio,
-				&rh.FechaEntrada,
-				&rh.FechaSalida,
-				&rh.Estado,
-				&habitacion.Nombre,
-				&habitacion.Capacidad,
-				&habitacion.Numero,
-			)
-			if err != nil {
-				habRows.Close()
-				return nil, fmt.Errorf("error al escanear habitación: %w", err)
-			}
-
-			habitacion.ID = rh.HabitacionID
-			rh.Habitacion = &habitacion
-			habitaciones = append(habitaciones, rh)
+		if err := r.cargarHabitaciones(reserva); err != nil {
+			return nil, err
 		}
-		habRows.Close()
 
-		reserva.Habitaciones = habitaciones
-		reservas = append(reservas, reserva)
+		reservas = append(reservas, *reserva)
 	}
 
 	return reservas, nil
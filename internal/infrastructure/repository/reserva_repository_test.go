@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// abrirBaseDePrueba conecta con la base de TEST_DATABASE_URL, que debe tener el esquema del hotel
+// y las migraciones aplicadas. Sin la variable la prueba se omite.
+func abrirBaseDePrueba(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL no está configurada")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("error al abrir la base de prueba: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("error al conectar con la base de prueba: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// crearHabitacionDePrueba crea un tipo de habitación y una habitación disponible que se eliminan
+// al terminar la prueba junto con sus reservas
+func crearHabitacionDePrueba(t *testing.T, db *sql.DB, sufijo string) *domain.Habitacion {
+	t.Helper()
+
+	habitacionRepo := NewHabitacionRepository(db)
+	tipo := domain.TipoHabitacion{
+		Titulo:           "Prueba " + sufijo,
+		Descripcion:      "Tipo de habitación de prueba",
+		CapacidadAdultos: 2,
+		CantidadCamas:    1,
+		Precio:           100,
+	}
+	if err := habitacionRepo.CreateRoomType(&tipo); err != nil {
+		t.Fatalf("error al crear tipo de habitación: %v", err)
+	}
+
+	habitacion := &domain.Habitacion{
+		Nombre:         "Prueba " + sufijo,
+		Numero:         "T" + sufijo,
+		Capacidad:      2,
+		Estado:         domain.HabitacionDisponible,
+		TipoHabitacion: tipo,
+	}
+	if err := habitacionRepo.CreateRoom(habitacion); err != nil {
+		t.Fatalf("error al crear habitación: %v", err)
+	}
+
+	t.Cleanup(func() {
+		var reservas []int
+		rows, err := db.Query(`SELECT DISTINCT reservation_id FROM reservation_room WHERE room_id = $1`, habitacion.ID)
+		if err == nil {
+			for rows.Next() {
+				var id int
+				if rows.Scan(&id) == nil {
+					reservas = append(reservas, id)
+				}
+			}
+			rows.Close()
+		}
+		for _, id := range reservas {
+			db.Exec(`DELETE FROM reservation_status_history WHERE reservation_id = $1`, id)
+			db.Exec(`DELETE FROM reservation_room WHERE reservation_id = $1`, id)
+			db.Exec(`DELETE FROM reservation WHERE reservation_id = $1`, id)
+		}
+		db.Exec(`DELETE FROM room WHERE room_id = $1`, habitacion.ID)
+		db.Exec(`DELETE FROM room_type WHERE room_type_id = $1`, tipo.ID)
+	})
+
+	return habitacion
+}
+
+// TestCreateReservaConcurrente verifica que de varias reservas simultáneas de la misma habitación
+// y fechas solo una se cree y las demás se rechacen por falta de disponibilidad
+func TestCreateReservaConcurrente(t *testing.T) {
+	db := abrirBaseDePrueba(t)
+
+	sufijo := fmt.Sprintf("%d", time.Now().UnixNano()%1_000_000_000)
+	habitacion := crearHabitacionDePrueba(t, db, sufijo)
+	email := "concurrencia-" + sufijo + "@example.com"
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM client_profile WHERE email = $1`, email)
+	})
+
+	repo := NewReservaRepository(db)
+	entrada := time.Date(2099, time.January, 10, 0, 0, 0, 0, time.UTC)
+	salida := entrada.AddDate(0, 0, 2)
+
+	const intentos = 8
+	var wg sync.WaitGroup
+	errores := make([]error, intentos)
+	inicio := make(chan struct{})
+
+	for i := range intentos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reserva := &domain.Reserva{
+				CodigoReserva:     fmt.Sprintf("T%s%d", sufijo, i),
+				CantidadAdultos:   1,
+				Estado:            domain.ReservaPendiente,
+				ClienteID:         email,
+				Subtotal:          200,
+				Total:             200,
+				FechaConfirmacion: time.Now(),
+				Habitaciones: []domain.ReservaHabitacion{{
+					HabitacionID: habitacion.ID,
+					Precio:       100,
+					FechaEntrada: entrada,
+					FechaSalida:  salida,
+				}},
+			}
+			<-inicio
+			errores[i] = repo.CreateReserva(reserva, "prueba")
+		}()
+	}
+
+	close(inicio)
+	wg.Wait()
+
+	exitosas := 0
+	for i, err := range errores {
+		var noDisponible *domain.HabitacionNoDisponibleError
+		switch {
+		case err == nil:
+			exitosas++
+		case errors.As(err, &noDisponible):
+			if noDisponible.HabitacionID != habitacion.ID {
+				t.Errorf("intento %d: habitación no disponible %d, se esperaba %d", i, noDisponible.HabitacionID, habitacion.ID)
+			}
+		default:
+			t.Errorf("intento %d: error inesperado: %v", i, err)
+		}
+	}
+
+	if exitosas != 1 {
+		t.Fatalf("se crearon %d reservas de la misma habitación y fechas, se esperaba 1", exitosas)
+	}
+}
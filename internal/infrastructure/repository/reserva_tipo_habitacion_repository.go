@@ -0,0 +1,518 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+// columnasReservaTipo es la lista de columnas que espera scanReservaTipo.
+// Requiere los alias rt (reservation_room_type) y t (room_type).
+const columnasReservaTipo = `
+			rt.reservation_room_type_id,
+			rt.reservation_id,
+			rt.room_type_id,
+			rt.quantity,
+			rt.price,
+			rt.check_in_date,
+			rt.check_out_date,
+			rt.status,
+			COALESCE(rt.price_breakdown, '[]'),
+			t.title,
+			t.adult_capacity,
+			t.children_capacity,
+			t.beds_count,
+			t.price`
+
+// scanReservaTipo escanea una fila seleccionada con columnasReservaTipo
+func scanReservaTipo(row rowScanner) (domain.ReservaTipoHabitacion, error) {
+	var rt domain.ReservaTipoHabitacion
+	var tipo domain.TipoHabitacion
+	var desglose []byte
+
+	err := row.Scan(
+		&rt.ID,
+		&rt.ReservaID,
+		&rt.TipoHabitacionID,
+		&rt.Cantidad,
+		&rt.Precio,
+		&rt.FechaEntrada,
+		&rt.FechaSalida,
+		&rt.Estado,
+		&desglose,
+		&tipo.Titulo,
+		&tipo.CapacidadAdultos,
+		&tipo.CapacidadNinhos,
+		&tipo.CantidadCamas,
+		&tipo.Precio,
+	)
+	if err != nil {
+		return rt, err
+	}
+
+	if err := json.Unmarshal(desglose, &rt.Desglose); err != nil {
+		return rt, fmt.Errorf("error al leer desglose de precios: %w", err)
+	}
+
+	tipo.ID = rt.TipoHabitacionID
+	rt.TipoHabitacion = &tipo
+	return rt, nil
+}
+
+// getTiposPorAsignar obtiene las reservas por tipo de la reserva que aún no tienen habitaciones
+func (r *reservaRepository) getTiposPorAsignar(reservaID int) ([]domain.ReservaTipoHabitacion, error) {
+	query := `
+		SELECT` + columnasReservaTipo + `
+		FROM reservation_room_type rt
+		INNER JOIN room_type t ON t.room_type_id = rt.room_type_id
+		WHERE rt.reservation_id = $1 AND rt.status = $2
+		ORDER BY rt.reservation_room_type_id
+	`
+
+	rows, err := r.db.Query(query, reservaID, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener tipos de habitación de la reserva: %w", err)
+	}
+	defer rows.Close()
+
+	var tipos []domain.ReservaTipoHabitacion
+	for rows.Next() {
+		rt, err := scanReservaTipo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear tipo de habitación: %w", err)
+		}
+		tipos = append(tipos, rt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer tipos de habitación: %w", err)
+	}
+
+	return tipos, nil
+}
+
+// insertarTiposHabitacion inserta las reservas por tipo de la reserva. La capacidad de cada tipo
+// se verifica después con verificarCapacidadReserva.
+func insertarTiposHabitacion(tx *sql.Tx, reserva *domain.Reserva) error {
+	for i := range reserva.TiposHabitacion {
+		tipo := &reserva.TiposHabitacion[i]
+
+		desglose, err := json.Marshal(tipo.Desglose)
+		if err != nil {
+			return fmt.Errorf("error al serializar desglose de precios: %w", err)
+		}
+
+		err = tx.QueryRow(`
+			INSERT INTO reservation_room_type (
+				reservation_id,
+				room_type_id,
+				quantity,
+				price,
+				check_in_date,
+				check_out_date,
+				status,
+				price_breakdown
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING reservation_room_type_id`,
+			reserva.ID,
+			tipo.TipoHabitacionID,
+			tipo.Cantidad,
+			tipo.Precio,
+			tipo.FechaEntrada,
+			tipo.FechaSalida,
+			domain.TipoReservaPorAsignar,
+			desglose,
+		).Scan(&tipo.ID)
+		if err != nil {
+			return fmt.Errorf("error al crear reserva por tipo de habitación: %w", err)
+		}
+
+		tipo.ReservaID = reserva.ID
+		tipo.Estado = domain.TipoReservaPorAsignar
+	}
+
+	return nil
+}
+
+// verificarCapacidadReserva verifica que los tipos de habitación de la reserva, asignados o por
+// asignar, no queden sobrevendidos en sus fechas. Debe llamarse con las filas de la reserva ya
+// escritas y después de tomar los locks de habitaciones.
+func verificarCapacidadReserva(tx *sql.Tx, reservaID int) error {
+	rows, err := tx.Query(`
+		SELECT tipo, MIN(entrada), MAX(salida)
+		FROM (
+			SELECT h.room_type_id AS tipo, rh.check_in_date AS entrada, rh.check_out_date AS salida
+			FROM reservation_room rh
+			INNER JOIN room h ON h.room_id = rh.room_id
+			WHERE rh.reservation_id = $1 AND rh.status = 1
+			UNION ALL
+			SELECT rt.room_type_id, rt.check_in_date, rt.check_out_date
+			FROM reservation_room_type rt
+			WHERE rt.reservation_id = $1 AND rt.status = $2
+		) unidades
+		GROUP BY tipo
+		ORDER BY tipo`, reservaID, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return fmt.Errorf("error al obtener tipos de habitación de la reserva: %w", err)
+	}
+
+	type rango struct {
+		tipoID       int
+		desde, hasta time.Time
+	}
+	var rangos []rango
+	for rows.Next() {
+		var r rango
+		if err := rows.Scan(&r.tipoID, &r.desde, &r.hasta); err != nil {
+			rows.Close()
+			return fmt.Errorf("error al escanear tipo de habitación: %w", err)
+		}
+		rangos = append(rangos, r)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error al recorrer tipos de habitación: %w", err)
+	}
+
+	// Los tipos ya vienen ordenados: se bloquean todos antes de contar
+	for _, r := range rangos {
+		if err := bloquearTipoHabitacion(tx, r.tipoID); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range rangos {
+		if err := verificarCapacidadTipo(tx, r.tipoID, r.desde, r.hasta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verificarCapacidadHabitacion verifica la capacidad del tipo de la habitación en el rango dado.
+// Se usa al retirar habitaciones de la venta (bloqueos, fuera de servicio); hasta nil revisa hasta
+// la última reserva por tipo pendiente.
+func verificarCapacidadHabitacion(tx *sql.Tx, habitacionID int, desde time.Time, hasta *time.Time) error {
+	var tipoID int
+	err := tx.QueryRow(`SELECT room_type_id FROM room WHERE room_id = $1`, habitacionID).Scan(&tipoID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el tipo de la habitación %d: %w", habitacionID, err)
+	}
+
+	if err := bloquearTipoHabitacion(tx, tipoID); err != nil {
+		return err
+	}
+
+	if hasta == nil {
+		var ultima sql.NullTime
+		err := tx.QueryRow(`
+			SELECT MAX(check_out_date)
+			FROM reservation_room_type
+			WHERE room_type_id = $1 AND status = $2`, tipoID, domain.TipoReservaPorAsignar).Scan(&ultima)
+		if err != nil {
+			return fmt.Errorf("error al obtener reservas por tipo pendientes: %w", err)
+		}
+		if !ultima.Valid || !ultima.Time.After(desde) {
+			return nil
+		}
+		hasta = &ultima.Time
+	}
+
+	return verificarCapacidadTipo(tx, tipoID, desde, *hasta)
+}
+
+// bloquearTipoHabitacion toma un lock de fila sobre el tipo de habitación para serializar las
+// reservas que consumen su capacidad. Se toma siempre después de los locks de habitaciones.
+func bloquearTipoHabitacion(tx *sql.Tx, tipoID int) error {
+	var id int
+	err := tx.QueryRow(`SELECT room_type_id FROM room_type WHERE room_type_id = $1 FOR UPDATE`, tipoID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("tipo de habitación con ID %d no encontrado", tipoID)
+		}
+		return fmt.Errorf("error al bloquear tipo de habitación %d: %w", tipoID, err)
+	}
+	return nil
+}
+
+// verificarCapacidadTipo retorna *domain.TipoHabitacionAgotadoError con la primera noche del rango
+// en la que las habitaciones del tipo en servicio, sin bloqueo y sin reserva no alcanzan para las
+// reservas por tipo pendientes. Requiere el lock de bloquearTipoHabitacion.
+func verificarCapacidadTipo(tx *sql.Tx, tipoID int, desde, hasta time.Time) error {
+	query := `
+		WITH RECURSIVE fechas AS (
+			SELECT cast($2 as date) as fecha
+			UNION ALL
+			SELECT (fecha + interval '1 day')::date
+			FROM fechas
+			WHERE fecha < cast($3 as date) - 1
+		),
+		noches AS (
+			SELECT f.fecha,
+				(
+					SELECT COUNT(*)
+					FROM room h
+					WHERE h.room_type_id = $1
+					AND h.status = 'Disponible'
+					AND NOT EXISTS (
+						SELECT 1 FROM room_block b
+						WHERE b.room_id = h.room_id
+						AND b.start_date <= f.fecha AND b.end_date > f.fecha
+					)
+					AND NOT EXISTS (
+						SELECT 1
+						FROM reservation_room rh
+						INNER JOIN reservation r ON r.reservation_id = rh.reservation_id
+						WHERE rh.room_id = h.room_id
+						AND rh.status = 1
+						AND ` + condicionReservaVigente + `
+						AND cast(rh.check_in_date as date) <= f.fecha AND cast(rh.check_out_date as date) > f.fecha
+					)
+				) - (
+					SELECT COALESCE(SUM(rt.quantity), 0)
+					FROM reservation_room_type rt
+					INNER JOIN reservation r ON r.reservation_id = rt.reservation_id
+					WHERE rt.room_type_id = $1
+					AND rt.status = $4
+					AND ` + condicionReservaVigente + `
+					AND cast(rt.check_in_date as date) <= f.fecha AND cast(rt.check_out_date as date) > f.fecha
+				) AS libres
+			FROM fechas f
+		)
+		SELECT fecha
+		FROM noches
+		WHERE libres < 0
+		ORDER BY fecha
+		LIMIT 1`
+
+	var fecha time.Time
+	err := tx.QueryRow(query, tipoID, desde, hasta, domain.TipoReservaPorAsignar).Scan(&fecha)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al verificar capacidad del tipo de habitación: %w", err)
+	}
+
+	return &domain.TipoHabitacionAgotadoError{TipoHabitacionID: tipoID, Fecha: fecha}
+}
+
+// AsignarHabitaciones asigna habitaciones concretas a las reservas por tipo pendientes de la reserva.
+// Las habitaciones asignadas heredan el precio y el desglose de su reserva por tipo.
+func (r *reservaRepository) AsignarHabitaciones(reservaID int, asignaciones []domain.AsignacionHabitacion) error {
+	return r.asignarHabitaciones(reservaID, asignaciones, false)
+}
+
+// AsignarRoomingList asigna parte de las habitaciones de las reservas por tipo pendientes con el
+// huésped de cada una; las que no se asignan siguen pendientes en su reserva por tipo
+func (r *reservaRepository) AsignarRoomingList(reservaID int, asignaciones []domain.AsignacionHabitacion) error {
+	return r.asignarHabitaciones(reservaID, asignaciones, true)
+}
+
+// asignarHabitaciones inserta las habitaciones asignadas y descuenta de sus reservas por tipo, que
+// pasan a asignadas cuando reciben todas sus habitaciones. Con parcial una reserva por tipo puede
+// recibir menos habitaciones que su cantidad.
+func (r *reservaRepository) asignarHabitaciones(reservaID int, asignaciones []domain.AsignacionHabitacion, parcial bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Bloquear las habitaciones antes que las reservas por tipo, como en el resto de transacciones
+	habitaciones := make([]domain.ReservaHabitacion, len(asignaciones))
+	for i, asignacion := range asignaciones {
+		habitaciones[i].HabitacionID = asignacion.HabitacionID
+	}
+	if err := bloquearHabitaciones(tx, habitaciones); err != nil {
+		return err
+	}
+
+	porTipo := make(map[int][]domain.AsignacionHabitacion)
+	for _, asignacion := range asignaciones {
+		porTipo[asignacion.TipoReservaID] = append(porTipo[asignacion.TipoReservaID], asignacion)
+	}
+
+	ids := make([]int, 0, len(porTipo))
+	for id := range porTipo {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		var tipo domain.ReservaTipoHabitacion
+		var desglose []byte
+		err := tx.QueryRow(`
+			SELECT room_type_id, quantity, price, check_in_date, check_out_date, COALESCE(price_breakdown, '[]')
+			FROM reservation_room_type
+			WHERE reservation_room_type_id = $1 AND reservation_id = $2 AND status = $3
+			FOR UPDATE`, id, reservaID, domain.TipoReservaPorAsignar,
+		).Scan(&tipo.TipoHabitacionID, &tipo.Cantidad, &tipo.Precio, &tipo.FechaEntrada, &tipo.FechaSalida, &desglose)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("la reserva %d no tiene habitaciones por asignar en la línea %d", reservaID, id)
+			}
+			return fmt.Errorf("error al obtener reserva por tipo: %w", err)
+		}
+
+		if len(porTipo[id]) > tipo.Cantidad || (!parcial && len(porTipo[id]) != tipo.Cantidad) {
+			return fmt.Errorf("la línea %d requiere %d habitación(es) y se indicaron %d", id, tipo.Cantidad, len(porTipo[id]))
+		}
+
+		for _, asignacion := range porTipo[id] {
+			habitacionID := asignacion.HabitacionID
+			var tipoHabitacion int
+			err := tx.QueryRow(`SELECT room_type_id FROM room WHERE room_id = $1`, habitacionID).Scan(&tipoHabitacion)
+			if err != nil {
+				return fmt.Errorf("error al obtener habitación %d: %w", habitacionID, err)
+			}
+			if tipoHabitacion != tipo.TipoHabitacionID {
+				return fmt.Errorf("la habitación %d no es del tipo reservado %d", habitacionID, tipo.TipoHabitacionID)
+			}
+
+			// Las habitaciones asignadas en esta misma transacción también cuentan como ocupadas
+			ocupada, err := habitacionOcupada(tx, habitacionID, tipo.FechaEntrada, tipo.FechaSalida, 0)
+			if err != nil {
+				return err
+			}
+			if ocupada {
+				return &domain.HabitacionNoDisponibleError{HabitacionID: habitacionID}
+			}
+
+			_, err = tx.Exec(`
+				INSERT INTO reservation_room (
+					reservation_id,
+					room_id,
+					price,
+					check_in_date,
+					check_out_date,
+					status,
+					price_breakdown,
+					reservation_room_type_id,
+					guest_name
+				) VALUES ($1, $2, $3, $4, $5, 1, $6, $7, $8)`,
+				reservaID, habitacionID, tipo.Precio, tipo.FechaEntrada, tipo.FechaSalida, desglose, id,
+				nullString(asignacion.NombreHuesped))
+			if err != nil {
+				return fmt.Errorf("error al asignar habitación %d: %w", habitacionID, err)
+			}
+		}
+
+		// La reserva por tipo conserva solo las habitaciones que aún no se asignan
+		if restantes := tipo.Cantidad - len(porTipo[id]); restantes > 0 {
+			_, err = tx.Exec(`UPDATE reservation_room_type SET quantity = $1 WHERE reservation_room_type_id = $2`, restantes, id)
+		} else {
+			_, err = tx.Exec(`UPDATE reservation_room_type SET status = $1 WHERE reservation_room_type_id = $2`, domain.TipoReservaAsignada, id)
+		}
+		if err != nil {
+			return fmt.Errorf("error al actualizar reservas por tipo: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// ReasignarHabitacion mueve la reserva de una habitación a otra para las mismas fechas conservando
+// su precio
+func (r *reservaRepository) ReasignarHabitacion(reservaID, habitacionActualID, habitacionNuevaID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	// La habitación actual puede estar fuera de servicio (es el motivo habitual para reasignar),
+	// así que se bloquean ambas sin validar su estado y luego se valida solo la nueva
+	for _, id := range []int{min(habitacionActualID, habitacionNuevaID), max(habitacionActualID, habitacionNuevaID)} {
+		if err := bloquearHabitacion(tx, id); err != nil {
+			return err
+		}
+	}
+
+	var estado string
+	if err := tx.QueryRow(`SELECT status FROM room WHERE room_id = $1`, habitacionNuevaID).Scan(&estado); err != nil {
+		return fmt.Errorf("error al obtener habitación %d: %w", habitacionNuevaID, err)
+	}
+	if estado != domain.HabitacionDisponible {
+		return &domain.HabitacionNoDisponibleError{HabitacionID: habitacionNuevaID}
+	}
+
+	var fechaEntrada, fechaSalida time.Time
+	err = tx.QueryRow(`
+		SELECT check_in_date, check_out_date
+		FROM reservation_room
+		WHERE reservation_id = $1 AND room_id = $2 AND status = 1`,
+		reservaID, habitacionActualID).Scan(&fechaEntrada, &fechaSalida)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("la habitación %d no está activa en la reserva %d", habitacionActualID, reservaID)
+		}
+		return fmt.Errorf("error al obtener habitación de la reserva: %w", err)
+	}
+
+	ocupada, err := habitacionOcupada(tx, habitacionNuevaID, fechaEntrada, fechaSalida, 0)
+	if err != nil {
+		return err
+	}
+	if ocupada {
+		return &domain.HabitacionNoDisponibleError{HabitacionID: habitacionNuevaID}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room
+		SET room_id = $1
+		WHERE reservation_id = $2 AND room_id = $3 AND status = 1`,
+		habitacionNuevaID, reservaID, habitacionActualID)
+	if err != nil {
+		return fmt.Errorf("error al reasignar habitación: %w", err)
+	}
+
+	// Pasar a una habitación de otro tipo consume capacidad de ese tipo
+	if err := verificarCapacidadReserva(tx, reservaID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// LiberarTiposPorAsignar cancela las reservas por tipo pendientes de la reserva y guarda los
+// montos ya recalculados sin ellas
+func (r *reservaRepository) LiberarTiposPorAsignar(reserva *domain.Reserva, canceladaEn time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE reservation_room_type
+		SET status = $1, cancelled_at = $2
+		WHERE reservation_id = $3 AND status = $4`,
+		domain.TipoReservaCancelada, canceladaEn, reserva.ID, domain.TipoReservaPorAsignar)
+	if err != nil {
+		return fmt.Errorf("error al liberar reservas por tipo: %w", err)
+	}
+
+	if err := actualizarMontos(tx, reserva); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type restriccionEstadiaRepository struct {
+	db *sql.DB
+}
+
+// NewRestriccionEstadiaRepository crea una nueva instancia del repositorio de restricciones de estadía
+func NewRestriccionEstadiaRepository(db *sql.DB) domain.RestriccionEstadiaRepository {
+	return &restriccionEstadiaRepository{db: db}
+}
+
+// GetRestricciones obtiene las restricciones entre ambas fechas. Con un tipo de habitación se
+// incluyen también las restricciones generales.
+func (r *restriccionEstadiaRepository) GetRestricciones(desde, hasta time.Time, tipoHabitacionID int) ([]domain.RestriccionEstadia, error) {
+	query := `
+		SELECT
+			restriction_id,
+			room_type_id,
+			restriction_date,
+			min_stay,
+			max_stay,
+			closed_to_arrival,
+			closed_to_departure,
+			COALESCE(reason, '')
+		FROM stay_restriction
+		WHERE restriction_date BETWEEN cast($1 as date) AND cast($2 as date)
+		AND ($3::int = 0 OR room_type_id IS NULL OR room_type_id = $3::int)
+		ORDER BY restriction_date, room_type_id NULLS FIRST`
+
+	rows, err := r.db.Query(query, desde, hasta, tipoHabitacionID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener restricciones de estadía: %w", err)
+	}
+	defer rows.Close()
+
+	restricciones := make([]domain.RestriccionEstadia, 0)
+	for rows.Next() {
+		var restriccion domain.RestriccionEstadia
+		var tipoID sql.NullInt64
+
+		err := rows.Scan(
+			&restriccion.ID,
+			&tipoID,
+			&restriccion.Fecha,
+			&restriccion.EstanciaMinima,
+			&restriccion.EstanciaMaxima,
+			&restriccion.CerradoLlegada,
+			&restriccion.CerradoSalida,
+			&restriccion.Motivo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear restricción de estadía: %w", err)
+		}
+
+		if tipoID.Valid {
+			id := int(tipoID.Int64)
+			restriccion.TipoHabitacionID = &id
+		}
+		restricciones = append(restricciones, restriccion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al iterar restricciones de estadía: %w", err)
+	}
+
+	return restricciones, nil
+}
+
+// GuardarRestricciones crea o reemplaza las restricciones en una sola transacción
+func (r *restriccionEstadiaRepository) GuardarRestricciones(restricciones []domain.RestriccionEstadia) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO stay_restriction (
+			room_type_id,
+			restriction_date,
+			min_stay,
+			max_stay,
+			closed_to_arrival,
+			closed_to_departure,
+			reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ((COALESCE(room_type_id, 0)), restriction_date) DO UPDATE
+		SET min_stay = EXCLUDED.min_stay,
+			max_stay = EXCLUDED.max_stay,
+			closed_to_arrival = EXCLUDED.closed_to_arrival,
+			closed_to_departure = EXCLUDED.closed_to_departure,
+			reason = EXCLUDED.reason
+		RETURNING restriction_id`
+
+	for i := range restricciones {
+		err := tx.QueryRow(
+			query,
+			restricciones[i].TipoHabitacionID,
+			restricciones[i].Fecha,
+			restricciones[i].EstanciaMinima,
+			restricciones[i].EstanciaMaxima,
+			restricciones[i].CerradoLlegada,
+			restricciones[i].CerradoSalida,
+			nullString(restricciones[i].Motivo),
+		).Scan(&restricciones[i].ID)
+		if err != nil {
+			return fmt.Errorf("error al guardar restricción de estadía: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRestriccion elimina una restricción de estadía
+func (r *restriccionEstadiaRepository) DeleteRestriccion(id int) error {
+	result, err := r.db.Exec(`DELETE FROM stay_restriction WHERE restriction_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error al eliminar restricción de estadía: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("restricción de estadía con ID %d no encontrada", id)
+	}
+
+	return nil
+}
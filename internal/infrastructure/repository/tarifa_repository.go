@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+)
+
+type tarifaRepository struct {
+	db *sql.DB
+}
+
+// NewTarifaRepository crea una nueva instancia del repositorio de planes de tarifa
+func NewTarifaRepository(db *sql.DB) domain.TarifaRepository {
+	return &tarifaRepository{db: db}
+}
+
+const columnasPlanTarifa = `
+			rate_plan_id,
+			name,
+			kind,
+			room_type_id,
+			percentage,
+			start_date,
+			end_date,
+			min_nights,
+			min_occupancy,
+			max_occupancy,
+			max_days_ahead,
+			active`
+
+// GetPlanes obtiene todos los planes de tarifa
+func (r *tarifaRepository) GetPlanes() ([]domain.PlanTarifa, error) {
+	query := `SELECT` + columnasPlanTarifa + `
+		FROM rate_plan
+		ORDER BY rate_plan_id`
+
+	return r.queryPlanes(query)
+}
+
+// GetPlanesActivos obtiene los planes activos que aplican al tipo de habitación (o a todos los tipos)
+func (r *tarifaRepository) GetPlanesActivos(tipoHabitacionID int) ([]domain.PlanTarifa, error) {
+	query := `SELECT` + columnasPlanTarifa + `
+		FROM rate_plan
+		WHERE active = true
+		AND (room_type_id IS NULL OR room_type_id = $1)
+		ORDER BY rate_plan_id`
+
+	return r.queryPlanes(query, tipoHabitacionID)
+}
+
+func (r *tarifaRepository) queryPlanes(query string, args ...interface{}) ([]domain.PlanTarifa, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener planes de tarifa: %w", err)
+	}
+	defer rows.Close()
+
+	var planes []domain.PlanTarifa
+	for rows.Next() {
+		var plan domain.PlanTarifa
+		var tipoHabitacionID sql.NullInt64
+		var fechaInicio, fechaFin sql.NullTime
+
+		err := rows.Scan(
+			&plan.ID,
+			&plan.Nombre,
+			&plan.Tipo,
+			&tipoHabitacionID,
+			&plan.Porcentaje,
+			&fechaInicio,
+			&fechaFin,
+			&plan.MinNoches,
+			&plan.OcupacionMin,
+			&plan.OcupacionMax,
+			&plan.DiasAnticipacion,
+			&plan.Activo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear plan de tarifa: %w", err)
+		}
+
+		if tipoHabitacionID.Valid {
+			id := int(tipoHabitacionID.Int64)
+			plan.TipoHabitacionID = &id
+		}
+		if fechaInicio.Valid {
+			plan.FechaInicio = &fechaInicio.Time
+		}
+		if fechaFin.Valid {
+			plan.FechaFin = &fechaFin.Time
+		}
+
+		planes = append(planes, plan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer planes de tarifa: %w", err)
+	}
+
+	return planes, nil
+}
+
+// CreatePlan crea un nuevo plan de tarifa
+func (r *tarifaRepository) CreatePlan(plan *domain.PlanTarifa) error {
+	query := `
+		INSERT INTO rate_plan (
+			name,
+			kind,
+			room_type_id,
+			percentage,
+			start_date,
+			end_date,
+			min_nights,
+			min_occupancy,
+			max_occupancy,
+			max_days_ahead,
+			active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING rate_plan_id
+	`
+
+	err := r.db.QueryRow(
+		query,
+		plan.Nombre,
+		plan.Tipo,
+		plan.TipoHabitacionID,
+		plan.Porcentaje,
+		plan.FechaInicio,
+		plan.FechaFin,
+		plan.MinNoches,
+		plan.OcupacionMin,
+		plan.OcupacionMax,
+		plan.DiasAnticipacion,
+		plan.Activo,
+	).Scan(&plan.ID)
+	if err != nil {
+		return fmt.Errorf("error al crear plan de tarifa: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePlan actualiza un plan de tarifa existente
+func (r *tarifaRepository) UpdatePlan(plan *domain.PlanTarifa) error {
+	query := `
+		UPDATE rate_plan
+		SET name = $1,
+			kind = $2,
+			room_type_id = $3,
+			percentage = $4,
+			start_date = $5,
+			end_date = $6,
+			min_nights = $7,
+			min_occupancy = $8,
+			max_occupancy = $9,
+			max_days_ahead = $10,
+			active = $11
+		WHERE rate_plan_id = $12
+	`
+
+	result, err := r.db.Exec(
+		query,
+		plan.Nombre,
+		plan.Tipo,
+		plan.TipoHabitacionID,
+		plan.Porcentaje,
+		plan.FechaInicio,
+		plan.FechaFin,
+		plan.MinNoches,
+		plan.OcupacionMin,
+		plan.OcupacionMax,
+		plan.DiasAnticipacion,
+		plan.Activo,
+		plan.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error al actualizar plan de tarifa: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("plan de tarifa con ID %d no encontrado", plan.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/lib/pq"
+)
+
+type usuarioRepository struct {
+	db *sql.DB
+}
+
+// NewUsuarioRepository crea una nueva instancia del repositorio de usuarios del personal
+func NewUsuarioRepository(db *sql.DB) domain.UsuarioRepository {
+	return &usuarioRepository{db: db}
+}
+
+const columnasUsuario = `
+			staff_user_id,
+			email,
+			full_name,
+			role,
+			active,
+			password_hash,
+			token_version,
+			last_login_at,
+			created_at,
+			updated_at`
+
+// scanUsuario escanea una fila seleccionada con columnasUsuario
+func scanUsuario(row rowScanner) (*domain.Usuario, error) {
+	var usuario domain.Usuario
+	var ultimoAcceso sql.NullTime
+
+	err := row.Scan(
+		&usuario.ID,
+		&usuario.Email,
+		&usuario.Nombre,
+		&usuario.Rol,
+		&usuario.Activo,
+		&usuario.HashPassword,
+		&usuario.VersionSesion,
+		&ultimoAcceso,
+		&usuario.FechaCreacion,
+		&usuario.FechaActualizacion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ultimoAcceso.Valid {
+		usuario.UltimoAcceso = &ultimoAcceso.Time
+	}
+	return &usuario, nil
+}
+
+// GetUsuarios lista los usuarios por nombre
+func (r *usuarioRepository) GetUsuarios() ([]domain.Usuario, error) {
+	rows, err := r.db.Query(`SELECT` + columnasUsuario + `
+		FROM staff_user
+		ORDER BY full_name, staff_user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener usuarios: %w", err)
+	}
+	defer rows.Close()
+
+	usuarios := []domain.Usuario{}
+	for rows.Next() {
+		usuario, err := scanUsuario(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear usuario: %w", err)
+		}
+		usuarios = append(usuarios, *usuario)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al recorrer usuarios: %w", err)
+	}
+
+	return usuarios, nil
+}
+
+// GetUsuarioByID obtiene un usuario por su ID
+func (r *usuarioRepository) GetUsuarioByID(id int) (*domain.Usuario, error) {
+	return r.getUsuario(`WHERE staff_user_id = $1`, id)
+}
+
+// GetUsuarioByEmail obtiene un usuario por su email
+func (r *usuarioRepository) GetUsuarioByEmail(email string) (*domain.Usuario, error) {
+	return r.getUsuario(`WHERE email = $1`, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// getUsuario obtiene el usuario que cumple la condición
+func (r *usuarioRepository) getUsuario(condicion string, valor any) (*domain.Usuario, error) {
+	query := `SELECT` + columnasUsuario + `
+		FROM staff_user
+		` + condicion
+
+	usuario, err := scanUsuario(r.db.QueryRow(query, valor))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUsuarioNoEncontrado
+		}
+		return nil, fmt.Errorf("error al obtener usuario: %w", err)
+	}
+
+	return usuario, nil
+}
+
+// CreateUsuario crea un usuario
+func (r *usuarioRepository) CreateUsuario(usuario *domain.Usuario) error {
+	query := `
+		INSERT INTO staff_user (email, full_name, role, password_hash, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING staff_user_id, created_at, updated_at`
+
+	err := r.db.QueryRow(query, usuario.Email, usuario.Nombre, usuario.Rol, usuario.HashPassword, usuario.Activo).
+		Scan(&usuario.ID, &usuario.FechaCreacion, &usuario.FechaActualizacion)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == codigoUniqueViolation {
+			return domain.ErrUsuarioDuplicado
+		}
+		return fmt.Errorf("error al crear usuario: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUsuario actualiza el nombre, el rol y si el usuario está activo
+func (r *usuarioRepository) UpdateUsuario(usuario *domain.Usuario) error {
+	query := `
+		UPDATE staff_user
+		SET full_name = $1,
+			role = $2,
+			active = $3,
+			token_version = token_version + CASE WHEN role <> $2 OR active <> $3 THEN 1 ELSE 0 END,
+			updated_at = NOW()
+		WHERE staff_user_id = $4
+		RETURNING email, token_version, last_login_at, created_at, updated_at`
+
+	var ultimoAcceso sql.NullTime
+	err := r.db.QueryRow(query, usuario.Nombre, usuario.Rol, usuario.Activo, usuario.ID).
+		Scan(&usuario.Email, &usuario.VersionSesion, &ultimoAcceso, &usuario.FechaCreacion, &usuario.FechaActualizacion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUsuarioNoEncontrado
+		}
+		return fmt.Errorf("error al actualizar usuario: %w", err)
+	}
+
+	if ultimoAcceso.Valid {
+		usuario.UltimoAcceso = &ultimoAcceso.Time
+	}
+	return nil
+}
+
+// CambiarPassword reemplaza el hash de la contraseña del usuario
+func (r *usuarioRepository) CambiarPassword(id int, hashPassword string) error {
+	result, err := r.db.Exec(`
+		UPDATE staff_user
+		SET password_hash = $1, token_version = token_version + 1, updated_at = NOW()
+		WHERE staff_user_id = $2`, hashPassword, id)
+	if err != nil {
+		return fmt.Errorf("error al cambiar contraseña: %w", err)
+	}
+
+	filas, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error al verificar cambio de contraseña: %w", err)
+	}
+	if filas == 0 {
+		return domain.ErrUsuarioNoEncontrado
+	}
+
+	return nil
+}
+
+// RegistrarAcceso guarda la fecha del último inicio de sesión
+func (r *usuarioRepository) RegistrarAcceso(id int, fecha time.Time) error {
+	if _, err := r.db.Exec(`UPDATE staff_user SET last_login_at = $1 WHERE staff_user_id = $2`, fecha, id); err != nil {
+		return fmt.Errorf("error al registrar acceso: %w", err)
+	}
+	return nil
+}
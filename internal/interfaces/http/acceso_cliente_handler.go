@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccesoClienteHandler struct {
+	service *application.AccesoClienteService
+}
+
+// NewAccesoClienteHandler crea una nueva instancia del handler de acceso de clientes
+func NewAccesoClienteHandler(service *application.AccesoClienteService) *AccesoClienteHandler {
+	return &AccesoClienteHandler{
+		service: service,
+	}
+}
+
+// SolicitarCodigoRequest representa la petición de un código de acceso
+type SolicitarCodigoRequest struct {
+	Email string `json:"email"`
+}
+
+// VerificarCodigoRequest representa la petición para iniciar sesión con un código de acceso
+type VerificarCodigoRequest struct {
+	Email  string `json:"email"`
+	Codigo string `json:"codigo"`
+}
+
+// SolicitarCodigo envía un código de acceso de un solo uso al email del cliente
+func (h *AccesoClienteHandler) SolicitarCodigo(c *fiber.Ctx) error {
+	var req SolicitarCodigoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	if err := h.service.SolicitarCodigo(req.Email, c.IP()); err != nil {
+		if errors.Is(err, application.ErrAccesoDeshabilitado) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrDemasiadasSolicitudesCodigo) {
+			log.Printf("Solicitudes de código de acceso limitadas para %q desde %s", req.Email, c.IP())
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Le enviamos un código de acceso a su email",
+	})
+}
+
+// VerificarCodigo inicia la sesión del cliente con su código de acceso y retorna el token
+func (h *AccesoClienteHandler) VerificarCodigo(c *fiber.Ctx) error {
+	var req VerificarCodigoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	sesion, err := h.service.VerificarCodigo(req.Email, req.Codigo)
+	if err != nil {
+		if errors.Is(err, application.ErrAccesoDeshabilitado) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, domain.ErrCodigoAccesoInvalido) {
+			log.Printf("Acceso denegado: código de acceso inválido de %q desde %s", req.Email, c.IP())
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al verificar el código de acceso",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Sesión iniciada exitosamente",
+		"data":    sesion,
+	})
+}
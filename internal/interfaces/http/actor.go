@@ -0,0 +1,17 @@
+package http
+
+import "github.com/gofiber/fiber/v2"
+
+// actorSolicitud identifica a quién se atribuyen los cambios hechos en la solicitud: la identidad
+// de la sesión verificada (su email o, con la clave de admin, su sujeto) o "api" si no tiene.
+// Nunca se toma de un header, que cualquiera podría falsificar.
+func actorSolicitud(c *fiber.Ctx) string {
+	sesion := sesionSolicitud(c)
+	if sesion == nil {
+		return "api"
+	}
+	if sesion.Email != "" {
+		return sesion.Email
+	}
+	return sesion.Sujeto
+}
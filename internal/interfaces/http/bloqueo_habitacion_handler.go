@@ -0,0 +1,203 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+	"github.com/gofiber/fiber/v2"
+)
+
+type BloqueoHabitacionHandler struct {
+	service *application.BloqueoHabitacionService
+}
+
+// NewBloqueoHabitacionHandler crea una nueva instancia del handler de bloqueos de habitaciones
+func NewBloqueoHabitacionHandler(service *application.BloqueoHabitacionService) *BloqueoHabitacionHandler {
+	return &BloqueoHabitacionHandler{
+		service: service,
+	}
+}
+
+// BloqueoHabitacionRequest representa la petición para crear o actualizar un bloqueo
+type BloqueoHabitacionRequest struct {
+	HabitacionID int    `json:"habitacionId"`
+	FechaInicio  string `json:"fechaInicio"` // YYYY-MM-DD
+	FechaFin     string `json:"fechaFin"`    // YYYY-MM-DD, no incluida
+	Motivo       string `json:"motivo"`
+}
+
+// toBloqueo convierte la petición en un bloqueo del dominio
+func (req BloqueoHabitacionRequest) toBloqueo() (*domain.BloqueoHabitacion, error) {
+	fechaInicio, err := parseDatePeru(req.FechaInicio)
+	if err != nil {
+		return nil, errors.New("formato de fechaInicio inválido. Use YYYY-MM-DD")
+	}
+
+	fechaFin, err := parseDatePeru(req.FechaFin)
+	if err != nil {
+		return nil, errors.New("formato de fechaFin inválido. Use YYYY-MM-DD")
+	}
+
+	return &domain.BloqueoHabitacion{
+		HabitacionID: req.HabitacionID,
+		FechaInicio:  fechaInicio,
+		FechaFin:     fechaFin,
+		Motivo:       req.Motivo,
+	}, nil
+}
+
+// responderErrorBloqueo traduce los errores de bloqueos a respuestas HTTP
+func responderErrorBloqueo(c *fiber.Ctx, err error) error {
+	var conReservas *domain.BloqueoConReservasError
+	if errors.As(err, &conReservas) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":    err.Error(),
+			"reservas": conReservas.Reservas,
+		})
+	}
+	var agotado *domain.TipoHabitacionAgotadoError
+	if errors.As(err, &agotado) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":            err.Error(),
+			"tipoHabitacionId": agotado.TipoHabitacionID,
+			"fecha":            agotado.Fecha.Format("2006-01-02"),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// GetBloqueos lista los bloqueos en un rango de fechas (?desde, ?hasta, ?habitacionId).
+// Por defecto muestra los próximos 3 meses.
+func (h *BloqueoHabitacionHandler) GetBloqueos(c *fiber.Ctx) error {
+	desde := horario.HoyPeru()
+	if desdeStr := c.Query("desde"); desdeStr != "" {
+		fecha, err := parseDatePeru(desdeStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de desde inválido. Use YYYY-MM-DD",
+			})
+		}
+		desde = fecha
+	}
+
+	hasta := desde.AddDate(0, 3, 0)
+	if hastaStr := c.Query("hasta"); hastaStr != "" {
+		fecha, err := parseDatePeru(hastaStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de hasta inválido. Use YYYY-MM-DD",
+			})
+		}
+		hasta = fecha
+	}
+
+	habitacionID := 0
+	if habitacionStr := c.Query("habitacionId"); habitacionStr != "" {
+		id, err := strconv.Atoi(habitacionStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "ID de habitación inválido",
+			})
+		}
+		habitacionID = id
+	}
+
+	// hasta es inclusiva en la consulta
+	bloqueos, err := h.service.GetBloqueos(habitacionID, desde, hasta.Add(24*time.Hour))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": bloqueos,
+	})
+}
+
+// CreateBloqueo crea un bloqueo de habitación. Si la habitación tiene reservas activas en el
+// rango responde 409 con los IDs de las reservas en conflicto.
+func (h *BloqueoHabitacionHandler) CreateBloqueo(c *fiber.Ctx) error {
+	var req BloqueoHabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	bloqueo, err := req.toBloqueo()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.service.CreateBloqueo(bloqueo); err != nil {
+		return responderErrorBloqueo(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Bloqueo creado exitosamente",
+		"data":    bloqueo,
+	})
+}
+
+// UpdateBloqueo actualiza un bloqueo de habitación
+func (h *BloqueoHabitacionHandler) UpdateBloqueo(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de bloqueo inválido",
+		})
+	}
+
+	var req BloqueoHabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	bloqueo, err := req.toBloqueo()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	bloqueo.ID = id
+
+	if err := h.service.UpdateBloqueo(bloqueo); err != nil {
+		return responderErrorBloqueo(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Bloqueo actualizado exitosamente",
+		"data":    bloqueo,
+	})
+}
+
+// DeleteBloqueo elimina un bloqueo de habitación
+func (h *BloqueoHabitacionHandler) DeleteBloqueo(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de bloqueo inválido",
+		})
+	}
+
+	if err := h.service.DeleteBloqueo(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Bloqueo eliminado exitosamente",
+	})
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"log"
 
 	"github.com/Maxito7/hotel_backend/internal/application"
@@ -41,7 +42,20 @@ func (h *ChatbotHandler) Chat(c *fiber.Ctx) error {
 		})
 	}
 
+	// El cliente de la conversación es el de la sesión
+	req.ClienteID = clienteSesion(c)
+
 	response, err := h.service.ProcessMessage(req)
+	if errors.Is(err, domain.ErrClienteNoEncontrado) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, domain.ErrConversacionAjena) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		log.Printf("Error processing message: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -73,6 +87,13 @@ func (h *ChatbotHandler) GetConversation(c *fiber.Ctx) error {
 		})
 	}
 
+	// Las conversaciones de un cliente identificado solo las ve él o recepción
+	if conversation.ClienteID != nil && !puedeVerCliente(sesionSolicitud(c), *conversation.ClienteID, domain.RolRecepcion) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": domain.ErrConversacionAjena.Error(),
+		})
+	}
+
 	return c.JSON(conversation)
 }
 
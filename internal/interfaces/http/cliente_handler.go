@@ -0,0 +1,144 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ClienteHandler struct {
+	service *application.ClienteService
+}
+
+// NewClienteHandler crea una nueva instancia del handler de clientes
+func NewClienteHandler(service *application.ClienteService) *ClienteHandler {
+	return &ClienteHandler{
+		service: service,
+	}
+}
+
+// ClienteRequest representa los datos del perfil de un cliente
+type ClienteRequest struct {
+	Email           string `json:"email"`
+	Nombre          string `json:"nombre"`
+	Telefono        string `json:"telefono"`
+	TipoDocumento   string `json:"tipoDocumento"` // DNI, CE o Pasaporte
+	NumeroDocumento string `json:"numeroDocumento"`
+	Nacionalidad    string `json:"nacionalidad"` // Código ISO del país, ej. PE
+	Preferencias    string `json:"preferencias"`
+	Notas           string `json:"notas"`
+}
+
+func (req ClienteRequest) cliente() *domain.Cliente {
+	return &domain.Cliente{
+		Email:           req.Email,
+		Nombre:          req.Nombre,
+		Telefono:        req.Telefono,
+		TipoDocumento:   req.TipoDocumento,
+		NumeroDocumento: req.NumeroDocumento,
+		Nacionalidad:    req.Nacionalidad,
+		Preferencias:    req.Preferencias,
+		Notas:           req.Notas,
+	}
+}
+
+// GetClientes lista los clientes (?q= filtra por nombre o email)
+func (h *ClienteHandler) GetClientes(c *fiber.Ctx) error {
+	clientes, err := h.service.GetClientes(c.Query("q"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al obtener los clientes",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": clientes,
+	})
+}
+
+// GetCliente obtiene el perfil del cliente con su historial de estadías y gasto
+func (h *ClienteHandler) GetCliente(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de cliente inválido",
+		})
+	}
+
+	historial, err := h.service.GetHistorial(id)
+	if err != nil {
+		return responderErrorCliente(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": historial,
+	})
+}
+
+// CreateCliente registra el perfil de un cliente
+func (h *ClienteHandler) CreateCliente(c *fiber.Ctx) error {
+	var req ClienteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	cliente := req.cliente()
+	if err := h.service.CreateCliente(cliente); err != nil {
+		return responderErrorCliente(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Cliente creado exitosamente",
+		"data":    cliente,
+	})
+}
+
+// UpdateCliente reemplaza los datos del perfil de un cliente
+func (h *ClienteHandler) UpdateCliente(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de cliente inválido",
+		})
+	}
+
+	var req ClienteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	cliente := req.cliente()
+	cliente.ID = id
+	if err := h.service.UpdateCliente(cliente); err != nil {
+		return responderErrorCliente(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Cliente actualizado exitosamente",
+		"data":    cliente,
+	})
+}
+
+// responderErrorCliente traduce los errores de los clientes a respuestas HTTP
+func responderErrorCliente(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrClienteNoEncontrado):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrClienteDuplicado):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type GrupoHandler struct {
+	service *application.GrupoService
+}
+
+// NewGrupoHandler crea una nueva instancia del handler de grupos
+func NewGrupoHandler(service *application.GrupoService) *GrupoHandler {
+	return &GrupoHandler{
+		service: service,
+	}
+}
+
+// GrupoRequest representa la petición para retener el cupo de un grupo
+type GrupoRequest struct {
+	Nombre          string             `json:"nombre"`
+	EmailContacto   string             `json:"emailContacto"`
+	FechaEntrada    string             `json:"fechaEntrada"`    // YYYY-MM-DD
+	FechaSalida     string             `json:"fechaSalida"`     // YYYY-MM-DD
+	FechaLiberacion string             `json:"fechaLiberacion"` // YYYY-MM-DD, fecha de corte del cupo
+	CantidadAdultos int                `json:"cantidadAdultos"` // omitido asume un adulto por habitación
+	CantidadNinhos  int                `json:"cantidadNinhos"`
+	Cupos           []domain.CupoGrupo `json:"cupos"`
+}
+
+// RoomingListRequest representa la rooming list de un grupo
+type RoomingListRequest struct {
+	Habitaciones []domain.HabitacionRooming `json:"habitaciones"`
+}
+
+// CreateGrupo retiene el cupo de habitaciones de un grupo
+func (h *GrupoHandler) CreateGrupo(c *fiber.Ctx) error {
+	var req GrupoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	fechaEntrada, err := time.Parse("2006-01-02", req.FechaEntrada)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaEntrada inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaSalida, err := time.Parse("2006-01-02", req.FechaSalida)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaSalida inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaLiberacion, err := time.Parse("2006-01-02", req.FechaLiberacion)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaLiberacion inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	grupo, err := h.service.CreateGrupo(application.NuevoGrupo{
+		Nombre:          req.Nombre,
+		EmailContacto:   req.EmailContacto,
+		FechaEntrada:    fechaEntrada,
+		FechaSalida:     fechaSalida,
+		FechaLiberacion: fechaLiberacion,
+		CantidadAdultos: req.CantidadAdultos,
+		CantidadNinhos:  req.CantidadNinhos,
+		Cupos:           req.Cupos,
+	}, actorSolicitud(c))
+	if err != nil {
+		return responderErrorGrupo(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Grupo creado exitosamente",
+		"data":    grupo,
+	})
+}
+
+// GetGrupos lista los grupos
+func (h *GrupoHandler) GetGrupos(c *fiber.Ctx) error {
+	grupos, err := h.service.GetGrupos()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al obtener los grupos",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": grupos,
+	})
+}
+
+// GetGrupoByID obtiene un grupo con su reserva
+func (h *GrupoHandler) GetGrupoByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de grupo inválido",
+		})
+	}
+
+	grupo, err := h.service.GetGrupoByID(id)
+	if err != nil {
+		return responderErrorGrupo(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": grupo,
+	})
+}
+
+// ImportarRoomingList asigna habitaciones del cupo a los huéspedes de la rooming list
+func (h *GrupoHandler) ImportarRoomingList(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de grupo inválido",
+		})
+	}
+
+	var req RoomingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	grupo, err := h.service.ImportarRoomingList(id, req.Habitaciones)
+	if err != nil {
+		return responderErrorGrupo(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Rooming list importada exitosamente",
+		"data":    grupo,
+	})
+}
+
+// responderErrorGrupo traduce los errores de los grupos a respuestas HTTP
+func responderErrorGrupo(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrGrupoNoEncontrado) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, domain.ErrCupoGrupoLiberado) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return responderErrorReserva(c, err)
+}
@@ -0,0 +1,257 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HabitacionRequest representa la petición para crear o actualizar una habitación
+type HabitacionRequest struct {
+	Nombre             string `json:"nombre"`
+	Numero             string `json:"numero"`
+	Capacidad          int    `json:"capacidad"`
+	Estado             string `json:"estado"` // Disponible o FueraDeServicio
+	DescripcionGeneral string `json:"descripcionGeneral"`
+	TipoHabitacionID   int    `json:"tipoHabitacionId"`
+}
+
+// toHabitacion convierte la petición en una habitación del dominio
+func (req HabitacionRequest) toHabitacion() *domain.Habitacion {
+	return &domain.Habitacion{
+		Nombre:             req.Nombre,
+		Numero:             req.Numero,
+		Capacidad:          req.Capacidad,
+		Estado:             req.Estado,
+		DescripcionGeneral: req.DescripcionGeneral,
+		TipoHabitacion:     domain.TipoHabitacion{ID: req.TipoHabitacionID},
+	}
+}
+
+// TipoHabitacionRequest representa la petición para crear o actualizar un tipo de habitación
+type TipoHabitacionRequest struct {
+	Titulo           string   `json:"titulo"`
+	Descripcion      string   `json:"descripcion"`
+	CapacidadAdultos int      `json:"capacidadAdultos"`
+	CapacidadNinhos  int      `json:"capacidadNinhos"`
+	CantidadCamas    int      `json:"cantidadCamas"`
+	Precio           float64  `json:"precio"`
+	PrecioMinimo     *float64 `json:"precioMinimo"` // límites del precio dinámico (opcionales)
+	PrecioMaximo     *float64 `json:"precioMaximo"`
+	Amenidades       []string `json:"amenidades"`
+}
+
+// toTipoHabitacion convierte la petición en un tipo de habitación del dominio
+func (req TipoHabitacionRequest) toTipoHabitacion() *domain.TipoHabitacion {
+	return &domain.TipoHabitacion{
+		Titulo:           req.Titulo,
+		Descripcion:      req.Descripcion,
+		CapacidadAdultos: req.CapacidadAdultos,
+		CapacidadNinhos:  req.CapacidadNinhos,
+		CantidadCamas:    req.CantidadCamas,
+		Precio:           req.Precio,
+		PrecioMinimo:     req.PrecioMinimo,
+		PrecioMaximo:     req.PrecioMaximo,
+		Amenidades:       req.Amenidades,
+	}
+}
+
+// responderErrorHabitacion traduce los errores de administración de habitaciones a respuestas HTTP
+func responderErrorHabitacion(c *fiber.Ctx, err error) error {
+	var conReservas *domain.HabitacionConReservasError
+	if errors.As(err, &conReservas) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":    err.Error(),
+			"reservas": conReservas.Reservas,
+		})
+	}
+	var enUso *domain.TipoHabitacionEnUsoError
+	if errors.As(err, &enUso) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":        err.Error(),
+			"habitaciones": enUso.Habitaciones,
+		})
+	}
+	if errors.Is(err, domain.ErrNumeroHabitacionDuplicado) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	var agotado *domain.TipoHabitacionAgotadoError
+	if errors.As(err, &agotado) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":            err.Error(),
+			"tipoHabitacionId": agotado.TipoHabitacionID,
+			"fecha":            agotado.Fecha.Format("2006-01-02"),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// CreateRoom crea una nueva habitación
+func (h *HabitacionHandler) CreateRoom(c *fiber.Ctx) error {
+	var req HabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	habitacion := req.toHabitacion()
+	if err := h.service.CreateRoom(habitacion); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Habitación creada exitosamente",
+		"data":    habitacion,
+	})
+}
+
+// UpdateRoom actualiza una habitación existente
+func (h *HabitacionHandler) UpdateRoom(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de habitación inválido",
+		})
+	}
+
+	var req HabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	habitacion := req.toHabitacion()
+	habitacion.ID = id
+
+	if err := h.service.UpdateRoom(habitacion); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Habitación actualizada exitosamente",
+		"data":    habitacion,
+	})
+}
+
+// DeactivateRoom retira una habitación de servicio
+func (h *HabitacionHandler) DeactivateRoom(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de habitación inválido",
+		})
+	}
+
+	if err := h.service.DeactivateRoom(id); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Habitación retirada de servicio exitosamente",
+	})
+}
+
+// EstadoLimpiezaRequest representa la petición para cambiar el estado de limpieza de una habitación
+type EstadoLimpiezaRequest struct {
+	Estado string `json:"estado"` // Limpia, Ocupada o Sucia
+}
+
+// UpdateEstadoLimpieza cambia el estado de limpieza de una habitación
+func (h *HabitacionHandler) UpdateEstadoLimpieza(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de habitación inválido",
+		})
+	}
+
+	var req EstadoLimpiezaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	if err := h.service.UpdateEstadoLimpieza(id, req.Estado); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Estado de limpieza actualizado exitosamente",
+	})
+}
+
+// CreateRoomType crea un nuevo tipo de habitación
+func (h *HabitacionHandler) CreateRoomType(c *fiber.Ctx) error {
+	var req TipoHabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	tipo := req.toTipoHabitacion()
+	if err := h.service.CreateRoomType(tipo); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Tipo de habitación creado exitosamente",
+		"data":    tipo,
+	})
+}
+
+// UpdateRoomType actualiza un tipo de habitación existente
+func (h *HabitacionHandler) UpdateRoomType(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de tipo de habitación inválido",
+		})
+	}
+
+	var req TipoHabitacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	tipo := req.toTipoHabitacion()
+	tipo.ID = id
+
+	if err := h.service.UpdateRoomType(tipo); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tipo de habitación actualizado exitosamente",
+		"data":    tipo,
+	})
+}
+
+// DeactivateRoomType desactiva un tipo de habitación
+func (h *HabitacionHandler) DeactivateRoomType(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de tipo de habitación inválido",
+		})
+	}
+
+	if err := h.service.DeactivateRoomType(id); err != nil {
+		return responderErrorHabitacion(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tipo de habitación desactivado exitosamente",
+	})
+}
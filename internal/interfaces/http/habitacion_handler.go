@@ -1,41 +1,26 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
 	"github.com/gofiber/fiber/v2"
 )
 
-// Zona horaria de Perú (UTC-5)
-var peruLocation *time.Location
-
-func init() {
-	var err error
-	peruLocation, err = time.LoadLocation("America/Lima")
-	if err != nil {
-		// Fallback a UTC-5 si no se puede cargar la zona horaria
-		peruLocation = time.FixedZone("PET", -5*60*60)
-	}
-}
-
 // parseDatePeru parsea una fecha en formato YYYY-MM-DD y la retorna en zona horaria de Perú
 func parseDatePeru(dateStr string) (time.Time, error) {
-	// Parsear en UTC primero y luego convertir a zona horaria de Perú
-	utcTime, err := time.Parse("2006-01-02", dateStr)
+	fecha, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return time.Time{}, err
 	}
-	// Crear la fecha en zona horaria de Perú a las 00:00:00
-	return time.Date(utcTime.Year(), utcTime.Month(), utcTime.Day(), 0, 0, 0, 0, peruLocation), nil
-}
-
-// getTodayPeru retorna la fecha de hoy a las 00:00:00 en zona horaria de Perú
-func getTodayPeru() time.Time {
-	now := time.Now().In(peruLocation)
-	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, peruLocation)
+	return horario.FechaPeru(fecha), nil
 }
 
 type HabitacionHandler struct {
@@ -113,7 +98,7 @@ func (h *HabitacionHandler) GetFechasBloqueadas(c *fiber.Ctx) error {
 	}
 
 	// Validar que desde no sea anterior a hoy
-	hoy := getTodayPeru()
+	hoy := horario.HoyPeru()
 	if desde.Before(hoy) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "desde cannot be before today",
@@ -132,6 +117,165 @@ func (h *HabitacionHandler) GetFechasBloqueadas(c *fiber.Ctx) error {
 	return c.JSON(fechasBloqueadas)
 }
 
+// GetCalendario retorna la disponibilidad y el precio por noche de cada tipo de habitación
+// para cada fecha del rango, para pintar el calendario del widget de reservas en una sola consulta
+func (h *HabitacionHandler) GetCalendario(c *fiber.Ctx) error {
+	desdeStr := c.Query("desde")
+	hastaStr := c.Query("hasta", "") // Si no se proporciona, se usa un mes
+
+	desde, err := parseDatePeru(desdeStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid desde format. Use YYYY-MM-DD",
+		})
+	}
+
+	var hasta time.Time
+	if hastaStr == "" {
+		hasta = desde.AddDate(0, 1, -1)
+	} else {
+		hasta, err = parseDatePeru(hastaStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid hasta format. Use YYYY-MM-DD",
+			})
+		}
+	}
+
+	tipoHabitacionID := 0
+	if tipoStr := c.Query("tipo"); tipoStr != "" {
+		tipoHabitacionID, err = strconv.Atoi(tipoStr)
+		if err != nil || tipoHabitacionID <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid tipo. Use the room type ID",
+			})
+		}
+	}
+
+	calendario, err := h.service.GetCalendario(desde, hasta, tipoHabitacionID)
+	if err != nil {
+		log.Printf("Error en GetCalendario: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Error al obtener el calendario: %v", err),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": calendario,
+	})
+}
+
+// BuscarHabitaciones busca habitaciones disponibles filtrando por huéspedes, tipo, precio, camas
+// y amenidades, con orden y paginación. Con modo=combinaciones sugiere el conjunto de habitaciones
+// más económico que en conjunto aloja a todo el grupo.
+func (h *HabitacionHandler) BuscarHabitaciones(c *fiber.Ctx) error {
+	fechaEntradaStr := c.Query("fechaEntrada")
+	fechaSalidaStr := c.Query("fechaSalida")
+
+	if fechaEntradaStr == "" || fechaSalidaStr == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "fechaEntrada and fechaSalida are required",
+		})
+	}
+
+	fechaEntrada, err := parseDatePeru(fechaEntradaStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid fechaEntrada format. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaSalida, err := parseDatePeru(fechaSalidaStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid fechaSalida format. Use YYYY-MM-DD",
+		})
+	}
+
+	if fechaEntrada.Before(horario.HoyPeru()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "fechaEntrada cannot be before today",
+		})
+	}
+
+	filtro := domain.FiltroBusquedaHabitaciones{
+		FechaEntrada: fechaEntrada,
+		FechaSalida:  fechaSalida,
+		Orden:        c.Query("orden"),
+	}
+
+	enteros := map[string]*int{
+		"adultos":   &filtro.Adultos,
+		"ninhos":    &filtro.Ninhos,
+		"tipo":      &filtro.TipoHabitacionID,
+		"camas":     &filtro.MinCamas,
+		"pagina":    &filtro.Pagina,
+		"porPagina": &filtro.PorPagina,
+	}
+	for nombre, destino := range enteros {
+		if valor := c.Query(nombre); valor != "" {
+			if *destino, err = strconv.Atoi(valor); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Invalid %s. Use an integer", nombre),
+				})
+			}
+		}
+	}
+
+	decimales := map[string]*float64{
+		"precioMin": &filtro.PrecioMin,
+		"precioMax": &filtro.PrecioMax,
+	}
+	for nombre, destino := range decimales {
+		if valor := c.Query(nombre); valor != "" {
+			if *destino, err = strconv.ParseFloat(valor, 64); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Invalid %s. Use a number", nombre),
+				})
+			}
+		}
+	}
+
+	if amenidades := c.Query("amenidades"); amenidades != "" {
+		for _, amenidad := range strings.Split(amenidades, ",") {
+			if amenidad = strings.ToLower(strings.TrimSpace(amenidad)); amenidad != "" {
+				filtro.Amenidades = append(filtro.Amenidades, amenidad)
+			}
+		}
+	}
+
+	if c.Query("modo") == "combinaciones" {
+		combinacion, err := h.service.CombinarHabitaciones(filtro)
+		if err != nil {
+			if errors.Is(err, domain.ErrSinCombinacionHabitaciones) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"data": combinacion,
+		})
+	}
+
+	habitaciones, paginacion, err := h.service.BuscarHabitaciones(filtro)
+	if err != nil {
+		log.Printf("Error en BuscarHabitaciones: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":       habitaciones,
+		"paginacion": paginacion,
+	})
+}
+
 func (h *HabitacionHandler) GetAvailableRooms(c *fiber.Ctx) error {
 	// Parse query parameters
 	fechaEntradaStr := c.Query("fechaEntrada")
@@ -166,7 +310,7 @@ func (h *HabitacionHandler) GetAvailableRooms(c *fiber.Ctx) error {
 	}
 
 	// Validar que fechaEntrada no sea anterior a hoy
-	hoy := getTodayPeru()
+	hoy := horario.HoyPeru()
 	if fechaEntrada.Before(hoy) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "fechaEntrada cannot be before today",
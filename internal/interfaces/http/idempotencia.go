@@ -0,0 +1,83 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// longitudMaximaClaveIdempotencia es el largo máximo del header Idempotency-Key
+const longitudMaximaClaveIdempotencia = 255
+
+// Idempotencia hace que los reintentos de una solicitud con el mismo header Idempotency-Key y el
+// mismo cuerpo repitan la respuesta original en lugar de procesarla otra vez. La misma clave con
+// otro cuerpo responde 422. Las respuestas 5xx no se guardan para que el reintento se procese.
+// Sin el header la solicitud se procesa normalmente.
+func Idempotencia(service *application.IdempotenciaService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clave := c.Get("Idempotency-Key")
+		if clave == "" {
+			return c.Next()
+		}
+
+		if len(clave) > longitudMaximaClaveIdempotencia {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Idempotency-Key no puede superar %d caracteres", longitudMaximaClaveIdempotencia),
+			})
+		}
+
+		ruta := c.Method() + " " + c.Path()
+		guardado, err := service.Iniciar(clave, ruta, c.Body())
+		switch {
+		case errors.Is(err, domain.ErrIdempotenciaConflicto):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrIdempotenciaEnProceso):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Error al verificar la clave de idempotencia",
+			})
+		}
+
+		if guardado != nil {
+			c.Set("Idempotent-Replayed", "true")
+			if guardado.ContentType != "" {
+				c.Set(fiber.HeaderContentType, guardado.ContentType)
+			}
+			return c.Status(guardado.Status).Send(guardado.Cuerpo)
+		}
+
+		if err := c.Next(); err != nil {
+			if errCancelar := service.Cancelar(clave, ruta); errCancelar != nil {
+				log.Printf("Error al liberar la clave de idempotencia %q: %v", clave, errCancelar)
+			}
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := service.Cancelar(clave, ruta); err != nil {
+				log.Printf("Error al liberar la clave de idempotencia %q: %v", clave, err)
+			}
+			return nil
+		}
+
+		// El cuerpo de la respuesta pertenece a fasthttp; se guarda una copia
+		cuerpo := append([]byte(nil), c.Response().Body()...)
+		contentType := string(c.Response().Header.ContentType())
+		if err := service.Completar(clave, ruta, status, contentType, cuerpo); err != nil {
+			// La respuesta ya se generó; un reintento la procesará de nuevo al vencer el plazo
+			log.Printf("Error al guardar la respuesta de la clave de idempotencia %q: %v", clave, err)
+		}
+
+		return nil
+	}
+}
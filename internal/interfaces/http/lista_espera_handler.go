@@ -0,0 +1,151 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListaEsperaHandler struct {
+	service *application.ListaEsperaService
+}
+
+// NewListaEsperaHandler crea una nueva instancia del handler de la lista de espera
+func NewListaEsperaHandler(service *application.ListaEsperaService) *ListaEsperaHandler {
+	return &ListaEsperaHandler{
+		service: service,
+	}
+}
+
+// SolicitudEsperaRequest representa la petición para anotarse en la lista de espera
+type SolicitudEsperaRequest struct {
+	Email           string `json:"email"`
+	FechaEntrada    string `json:"fechaEntrada"` // YYYY-MM-DD
+	FechaSalida     string `json:"fechaSalida"`  // YYYY-MM-DD
+	CantidadAdultos int    `json:"cantidadAdultos"`
+	CantidadNinhos  int    `json:"cantidadNinhos"`
+	TiposHabitacion []int  `json:"tiposHabitacion"` // en orden de preferencia; vacío acepta cualquier tipo
+}
+
+// CreateSolicitud anota al huésped en la lista de espera de las fechas solicitadas
+func (h *ListaEsperaHandler) CreateSolicitud(c *fiber.Ctx) error {
+	var req SolicitudEsperaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	fechaEntrada, err := time.Parse("2006-01-02", req.FechaEntrada)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaEntrada inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaSalida, err := time.Parse("2006-01-02", req.FechaSalida)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaSalida inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	solicitud := &domain.SolicitudEspera{
+		Email:           req.Email,
+		FechaEntrada:    fechaEntrada,
+		FechaSalida:     fechaSalida,
+		CantidadAdultos: req.CantidadAdultos,
+		CantidadNinhos:  req.CantidadNinhos,
+		TiposHabitacion: req.TiposHabitacion,
+	}
+
+	if err := h.service.RegistrarSolicitud(solicitud); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Te avisaremos por email si se liberan habitaciones para tus fechas",
+		"data":    solicitud,
+	})
+}
+
+// GetSolicitudes lista la lista de espera en orden de registro (?estado=)
+func (h *ListaEsperaHandler) GetSolicitudes(c *fiber.Ctx) error {
+	solicitudes, err := h.service.GetSolicitudes(c.Query("estado"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": solicitudes,
+	})
+}
+
+// CancelarSolicitud retira una solicitud que aún no recibió oferta
+func (h *ListaEsperaHandler) CancelarSolicitud(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de solicitud inválido",
+		})
+	}
+
+	if err := h.service.CancelarSolicitud(id); err != nil {
+		return responderErrorListaEspera(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Solicitud retirada de la lista de espera",
+	})
+}
+
+// GetOferta muestra la reserva retenida para una oferta de la lista de espera
+func (h *ListaEsperaHandler) GetOferta(c *fiber.Ctx) error {
+	solicitud, reserva, err := h.service.GetOferta(c.Params("token"))
+	if err != nil {
+		return responderErrorListaEspera(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fiber.Map{
+			"solicitud": solicitud,
+			"reserva":   reserva,
+		},
+	})
+}
+
+// ConfirmarOferta confirma la reserva retenida para una oferta de la lista de espera
+func (h *ListaEsperaHandler) ConfirmarOferta(c *fiber.Ctx) error {
+	reserva, err := h.service.ConfirmarOferta(c.Params("token"))
+	if err != nil {
+		return responderErrorListaEspera(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reserva confirmada exitosamente",
+		"data":    reserva,
+	})
+}
+
+// responderErrorListaEspera traduce los errores de la lista de espera a respuestas HTTP
+func responderErrorListaEspera(c *fiber.Ctx, err error) error {
+	if errors.Is(err, domain.ErrSolicitudEsperaNoEncontrada) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, domain.ErrOfertaVencida) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return responderErrorReserva(c, err)
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PoliticaCancelacionHandler struct {
+	service *application.PoliticaCancelacionService
+}
+
+// NewPoliticaCancelacionHandler crea una nueva instancia del handler de políticas de cancelación
+func NewPoliticaCancelacionHandler(service *application.PoliticaCancelacionService) *PoliticaCancelacionHandler {
+	return &PoliticaCancelacionHandler{
+		service: service,
+	}
+}
+
+// PoliticaCancelacionRequest representa la petición para crear o actualizar una política de cancelación
+type PoliticaCancelacionRequest struct {
+	Nombre           string  `json:"nombre"`
+	HorasLibres      int     `json:"horasLibres"`
+	TipoPenalidad    string  `json:"tipoPenalidad"` // Porcentaje o PrimeraNoche
+	Porcentaje       float64 `json:"porcentaje"`
+	TipoHabitacionID *int    `json:"tipoHabitacionId"`
+	PlanTarifaID     *int    `json:"planTarifaId"`
+	Activo           *bool   `json:"activo"`
+}
+
+// toPoliticaCancelacion convierte la petición en una política del dominio
+func (req PoliticaCancelacionRequest) toPoliticaCancelacion() *domain.PoliticaCancelacion {
+	politica := &domain.PoliticaCancelacion{
+		Nombre:           req.Nombre,
+		HorasLibres:      req.HorasLibres,
+		TipoPenalidad:    domain.TipoPenalidad(req.TipoPenalidad),
+		Porcentaje:       req.Porcentaje,
+		TipoHabitacionID: req.TipoHabitacionID,
+		PlanTarifaID:     req.PlanTarifaID,
+		Activo:           true,
+	}
+
+	if req.Activo != nil {
+		politica.Activo = *req.Activo
+	}
+
+	return politica
+}
+
+// GetPoliticas obtiene todas las políticas de cancelación
+func (h *PoliticaCancelacionHandler) GetPoliticas(c *fiber.Ctx) error {
+	politicas, err := h.service.GetPoliticas()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": politicas,
+	})
+}
+
+// CreatePolitica crea una nueva política de cancelación
+func (h *PoliticaCancelacionHandler) CreatePolitica(c *fiber.Ctx) error {
+	var req PoliticaCancelacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	politica := req.toPoliticaCancelacion()
+	if err := h.service.CreatePolitica(politica); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Política de cancelación creada exitosamente",
+		"data":    politica,
+	})
+}
+
+// UpdatePolitica actualiza una política de cancelación existente
+func (h *PoliticaCancelacionHandler) UpdatePolitica(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de política inválido",
+		})
+	}
+
+	var req PoliticaCancelacionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	politica := req.toPoliticaCancelacion()
+	politica.ID = id
+
+	if err := h.service.UpdatePolitica(politica); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Política de cancelación actualizada exitosamente",
+		"data":    politica,
+	})
+}
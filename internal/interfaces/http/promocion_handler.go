@@ -0,0 +1,192 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromocionHandler struct {
+	service *application.PromocionService
+}
+
+// NewPromocionHandler crea una nueva instancia del handler de promociones
+func NewPromocionHandler(service *application.PromocionService) *PromocionHandler {
+	return &PromocionHandler{
+		service: service,
+	}
+}
+
+// PromocionRequest representa la petición para crear o actualizar una promoción
+type PromocionRequest struct {
+	Codigo            string  `json:"codigo"`
+	Descripcion       string  `json:"descripcion"`
+	TipoDescuento     string  `json:"tipoDescuento"` // Porcentaje o Monto
+	Valor             float64 `json:"valor"`
+	FechaInicio       string  `json:"fechaInicio"` // Formato: YYYY-MM-DD
+	FechaFin          string  `json:"fechaFin"`    // Formato: YYYY-MM-DD
+	TiposHabitacion   []int   `json:"tiposHabitacion"`
+	MinNoches         int     `json:"minNoches"`
+	MaxUsos           *int    `json:"maxUsos"`
+	MaxUsosPorCliente *int    `json:"maxUsosPorCliente"`
+	Activo            *bool   `json:"activo"`
+}
+
+// toPromocion convierte la petición en una promoción del dominio
+func (req PromocionRequest) toPromocion() (*domain.Promocion, error) {
+	fechaInicio, err := time.Parse("2006-01-02", req.FechaInicio)
+	if err != nil {
+		return nil, fmt.Errorf("Formato de fechaInicio inválido. Use YYYY-MM-DD")
+	}
+
+	fechaFin, err := time.Parse("2006-01-02", req.FechaFin)
+	if err != nil {
+		return nil, fmt.Errorf("Formato de fechaFin inválido. Use YYYY-MM-DD")
+	}
+
+	promocion := &domain.Promocion{
+		Codigo:            req.Codigo,
+		Descripcion:       req.Descripcion,
+		TipoDescuento:     domain.TipoDescuento(req.TipoDescuento),
+		Valor:             req.Valor,
+		FechaInicio:       fechaInicio,
+		FechaFin:          fechaFin,
+		TiposHabitacion:   req.TiposHabitacion,
+		MinNoches:         req.MinNoches,
+		MaxUsos:           req.MaxUsos,
+		MaxUsosPorCliente: req.MaxUsosPorCliente,
+		Activo:            true,
+	}
+
+	if promocion.TiposHabitacion == nil {
+		promocion.TiposHabitacion = []int{}
+	}
+	if req.Activo != nil {
+		promocion.Activo = *req.Activo
+	}
+
+	return promocion, nil
+}
+
+// GetPromociones obtiene todas las promociones
+func (h *PromocionHandler) GetPromociones(c *fiber.Ctx) error {
+	promociones, err := h.service.GetPromociones()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": promociones,
+	})
+}
+
+// GetPromocionByID obtiene una promoción por su ID
+func (h *PromocionHandler) GetPromocionByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de promoción inválido",
+		})
+	}
+
+	promocion, err := h.service.GetPromocionByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": promocion,
+	})
+}
+
+// CreatePromocion crea una nueva promoción
+func (h *PromocionHandler) CreatePromocion(c *fiber.Ctx) error {
+	var req PromocionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	promocion, err := req.toPromocion()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.service.CreatePromocion(promocion); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Promoción creada exitosamente",
+		"data":    promocion,
+	})
+}
+
+// UpdatePromocion actualiza una promoción existente
+func (h *PromocionHandler) UpdatePromocion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de promoción inválido",
+		})
+	}
+
+	var req PromocionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	promocion, err := req.toPromocion()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	promocion.ID = id
+
+	if err := h.service.UpdatePromocion(promocion); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Promoción actualizada exitosamente",
+		"data":    promocion,
+	})
+}
+
+// DesactivarPromocion desactiva una promoción
+func (h *PromocionHandler) DesactivarPromocion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de promoción inválido",
+		})
+	}
+
+	if err := h.service.DesactivarPromocion(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Promoción desactivada exitosamente",
+	})
+}
@@ -1,11 +1,16 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/auth"
 	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/Maxito7/hotel_backend/internal/horario"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -25,18 +30,83 @@ type CreateReservaRequest struct {
 	CantidadAdultos int                       `json:"cantidadAdultos"`
 	CantidadNinhos  int                       `json:"cantidadNinhos"`
 	ClienteID       string                    `json:"clienteId"`
-	Descuento       float64                   `json:"descuento"`
+	CodigoPromocion string                    `json:"codigoPromocion"`
+	TipoDocumento   string                    `json:"tipoDocumento"` // DNI, CE o Pasaporte
+	NumeroDocumento string                    `json:"numeroDocumento"`
+	Nacionalidad    string                    `json:"nacionalidad"` // Código ISO del país, ej. PE
 	Habitaciones    []CreateHabitacionReserva `json:"habitaciones"`
+	// TiposHabitacion reserva habitaciones por tipo; se asignan al confirmar o al hacer check-in
+	TiposHabitacion []CreateTipoHabitacionReserva `json:"tiposHabitacion"`
+}
+
+// CreateTipoHabitacionReserva representa una cantidad de habitaciones de un tipo a reservar
+type CreateTipoHabitacionReserva struct {
+	TipoHabitacionID int     `json:"tipoHabitacionId"`
+	Cantidad         int     `json:"cantidad"`
+	Precio           float64 `json:"precio"`       // Precio por noche cotizado (opcional), se valida contra la tarifa vigente
+	FechaEntrada     string  `json:"fechaEntrada"` // Formato: YYYY-MM-DD
+	FechaSalida      string  `json:"fechaSalida"`  // Formato: YYYY-MM-DD
+}
+
+// AsignarHabitacionesRequest representa la petición para asignar habitaciones a las reservadas
+// por tipo. Sin asignaciones se eligen todas automáticamente.
+type AsignarHabitacionesRequest struct {
+	Asignaciones []domain.AsignacionHabitacion `json:"asignaciones"`
+}
+
+// ReasignarHabitacionRequest representa la petición para mover una habitación de la reserva a otra
+type ReasignarHabitacionRequest struct {
+	HabitacionID int `json:"habitacionId"`
+}
+
+// CheckInRequest representa la petición para registrar la llegada de los huéspedes
+type CheckInRequest struct {
+	Huespedes []HuespedRequest `json:"huespedes"`
+	// Asignaciones elige habitaciones para las reservadas por tipo (opcional)
+	Asignaciones []domain.AsignacionHabitacion `json:"asignaciones"`
+}
+
+// HuespedRequest representa un huésped identificado en el check-in
+type HuespedRequest struct {
+	Nombre          string `json:"nombre"`
+	TipoDocumento   string `json:"tipoDocumento"` // DNI, CE o Pasaporte
+	NumeroDocumento string `json:"numeroDocumento"`
+	Nacionalidad    string `json:"nacionalidad"`           // Código ISO del país, ej. PE
+	HabitacionID    *int   `json:"habitacionId,omitempty"` // habitación de la reserva donde se aloja (opcional)
+}
+
+// CheckOutRequest representa la petición para registrar la salida de los huéspedes
+type CheckOutRequest struct {
+	Cargos []CargoRequest `json:"cargos"`
+	// SalidaAnticipada confirma la salida antes de la fecha reservada
+	SalidaAnticipada bool `json:"salidaAnticipada"`
+}
+
+// CargoRequest representa un consumo o servicio de la estadía a cobrar en el check-out
+type CargoRequest struct {
+	Concepto string  `json:"concepto"`
+	Monto    float64 `json:"monto"`
 }
 
 // CreateHabitacionReserva representa una habitación a reservar
 type CreateHabitacionReserva struct {
 	HabitacionID int     `json:"habitacionId"`
-	Precio       float64 `json:"precio"`
+	Precio       float64 `json:"precio"`       // Precio por noche cotizado (opcional), se valida contra la tarifa vigente
 	FechaEntrada string  `json:"fechaEntrada"` // Formato: YYYY-MM-DD
 	FechaSalida  string  `json:"fechaSalida"`  // Formato: YYYY-MM-DD
 }
 
+// ModificarReservaRequest representa la petición para modificar una reserva. Los campos omitidos
+// se mantienen; fechaEntrada y fechaSalida mueven todas las habitaciones actuales y habitaciones
+// reemplaza por completo las habitaciones de la reserva.
+type ModificarReservaRequest struct {
+	CantidadAdultos *int                      `json:"cantidadAdultos"`
+	CantidadNinhos  *int                      `json:"cantidadNinhos"`
+	FechaEntrada    string                    `json:"fechaEntrada"` // Formato: YYYY-MM-DD
+	FechaSalida     string                    `json:"fechaSalida"`  // Formato: YYYY-MM-DD
+	Habitaciones    []CreateHabitacionReserva `json:"habitaciones"`
+}
+
 // UpdateEstadoRequest representa la petición para actualizar el estado de una reserva
 type UpdateEstadoRequest struct {
 	Estado string `json:"estado"`
@@ -65,28 +135,124 @@ func (h *ReservaHandler) CreateReserva(c *fiber.Ctx) error {
 		})
 	}
 
-	if len(req.Habitaciones) == 0 {
+	if len(req.Habitaciones) == 0 && len(req.TiposHabitacion) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Debe incluir al menos una habitación",
 		})
 	}
 
 	// Convertir habitaciones
-	habitaciones := make([]domain.ReservaHabitacion, len(req.Habitaciones))
-	for i, hab := range req.Habitaciones {
-		fechaEntrada, err := time.Parse("2006-01-02", hab.FechaEntrada)
+	habitaciones, err := toReservaHabitaciones(req.Habitaciones)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tipos, err := toReservaTipos(req.TiposHabitacion)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Crear la reserva
+	reserva := &domain.Reserva{
+		CantidadAdultos:   req.CantidadAdultos,
+		CantidadNinhos:    req.CantidadNinhos,
+		ClienteID:         req.ClienteID,
+		CodigoPromocion:   req.CodigoPromocion,
+		TipoDocumento:     req.TipoDocumento,
+		NumeroDocumento:   req.NumeroDocumento,
+		Nacionalidad:      req.Nacionalidad,
+		Estado:            domain.ReservaPendiente,
+		FechaConfirmacion: time.Now(),
+		Habitaciones:      habitaciones,
+		TiposHabitacion:   tipos,
+	}
+
+	if err := h.service.CreateReserva(reserva, actorSolicitud(c)); err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Reserva creada exitosamente",
+		"data":    reserva,
+	})
+}
+
+// ModificarReserva cambia las fechas, habitaciones o huéspedes de una reserva
+func (h *ReservaHandler) ModificarReserva(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	var req ModificarReservaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	cambios := application.ModificacionReserva{
+		CantidadAdultos: req.CantidadAdultos,
+		CantidadNinhos:  req.CantidadNinhos,
+	}
+
+	if req.FechaEntrada != "" {
+		fecha, err := time.Parse("2006-01-02", req.FechaEntrada)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error": "Formato de fechaEntrada inválido. Use YYYY-MM-DD",
 			})
 		}
+		cambios.FechaEntrada = &fecha
+	}
 
-		fechaSalida, err := time.Parse("2006-01-02", hab.FechaSalida)
+	if req.FechaSalida != "" {
+		fecha, err := time.Parse("2006-01-02", req.FechaSalida)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error": "Formato de fechaSalida inválido. Use YYYY-MM-DD",
 			})
 		}
+		cambios.FechaSalida = &fecha
+	}
+
+	cambios.Habitaciones, err = toReservaHabitaciones(req.Habitaciones)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	reserva, err := h.service.ModificarReserva(id, cambios)
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reserva modificada exitosamente",
+		"data":    reserva,
+	})
+}
+
+// toReservaHabitaciones convierte las habitaciones de la petición en habitaciones del dominio
+func toReservaHabitaciones(req []CreateHabitacionReserva) ([]domain.ReservaHabitacion, error) {
+	habitaciones := make([]domain.ReservaHabitacion, len(req))
+	for i, hab := range req {
+		fechaEntrada, err := time.Parse("2006-01-02", hab.FechaEntrada)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaEntrada inválido. Use YYYY-MM-DD")
+		}
+
+		fechaSalida, err := time.Parse("2006-01-02", hab.FechaSalida)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaSalida inválido. Use YYYY-MM-DD")
+		}
 
 		habitaciones[i] = domain.ReservaHabitacion{
 			HabitacionID: hab.HabitacionID,
@@ -97,26 +263,92 @@ func (h *ReservaHandler) CreateReserva(c *fiber.Ctx) error {
 		}
 	}
 
-	// Crear la reserva
-	reserva := &domain.Reserva{
-		CantidadAdultos:   req.CantidadAdultos,
-		CantidadNinhos:    req.CantidadNinhos,
-		ClienteID:         req.ClienteID,
-		Descuento:         req.Descuento,
-		Estado:            domain.ReservaPendiente,
-		FechaConfirmacion: time.Now(),
-		Habitaciones:      habitaciones,
+	return habitaciones, nil
+}
+
+// toReservaTipos convierte los tipos de habitación de la petición en reservas por tipo del dominio
+func toReservaTipos(req []CreateTipoHabitacionReserva) ([]domain.ReservaTipoHabitacion, error) {
+	tipos := make([]domain.ReservaTipoHabitacion, len(req))
+	for i, tipo := range req {
+		fechaEntrada, err := time.Parse("2006-01-02", tipo.FechaEntrada)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaEntrada inválido. Use YYYY-MM-DD")
+		}
+
+		fechaSalida, err := time.Parse("2006-01-02", tipo.FechaSalida)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaSalida inválido. Use YYYY-MM-DD")
+		}
+
+		tipos[i] = domain.ReservaTipoHabitacion{
+			TipoHabitacionID: tipo.TipoHabitacionID,
+			Cantidad:         tipo.Cantidad,
+			Precio:           tipo.Precio,
+			FechaEntrada:     fechaEntrada,
+			FechaSalida:      fechaSalida,
+			Estado:           domain.TipoReservaPorAsignar,
+		}
 	}
 
-	if err := h.service.CreateReserva(reserva); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	return tipos, nil
+}
+
+// responderErrorReserva traduce los errores de las operaciones sobre reservas a su código HTTP
+func responderErrorReserva(c *fiber.Ctx, err error) error {
+	var transicion *domain.TransicionInvalidaError
+	if errors.As(err, &transicion) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":        err.Error(),
+			"estadoActual": transicion.Desde,
+		})
+	}
+	var noDisponible *domain.HabitacionNoDisponibleError
+	if errors.As(err, &noDisponible) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":        err.Error(),
+			"habitacionId": noDisponible.HabitacionID,
+		})
+	}
+	if errors.Is(err, domain.ErrPromocionAgotada) || errors.Is(err, domain.ErrPromocionLimiteCliente) ||
+		errors.Is(err, domain.ErrReservaExpirada) || errors.Is(err, domain.ErrSinHabitacionAsignable) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
-
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Reserva creada exitosamente",
-		"data":    reserva,
+	var restriccion *domain.RestriccionEstadiaError
+	if errors.As(err, &restriccion) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":            err.Error(),
+			"regla":            restriccion.Regla,
+			"fecha":            restriccion.Fecha.Format("2006-01-02"),
+			"tipoHabitacionId": restriccion.TipoHabitacionID,
+		})
+	}
+	var precioNoCoincide *domain.PrecioNoCoincideError
+	if errors.As(err, &precioNoCoincide) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":           err.Error(),
+			"habitacionId":    precioNoCoincide.HabitacionID,
+			"precioCalculado": precioNoCoincide.Calculado,
+		})
+	}
+	var agotado *domain.TipoHabitacionAgotadoError
+	if errors.As(err, &agotado) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":            err.Error(),
+			"tipoHabitacionId": agotado.TipoHabitacionID,
+			"fecha":            agotado.Fecha.Format("2006-01-02"),
+		})
+	}
+	var fechaRecepcion *domain.FechaRecepcionError
+	if errors.As(err, &fechaRecepcion) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+			"fecha": fechaRecepcion.Fecha.Format("2006-01-02"),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": err.Error(),
 	})
 }
 
@@ -142,6 +374,54 @@ func (h *ReservaHandler) GetReservaByID(c *fiber.Ctx) error {
 	})
 }
 
+// EsPropietario indica si la reserva del parámetro id es del cliente de la sesión. Una reserva
+// inexistente se trata como ajena para no revelar qué IDs existen.
+func (h *ReservaHandler) EsPropietario(c *fiber.Ctx, sesion *auth.Claims) (bool, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return false, nil
+	}
+
+	reserva, err := h.service.GetReservaByID(id)
+	if errors.Is(err, domain.ErrReservaNoEncontrada) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	perfilClienteID, _ := strconv.Atoi(sesion.Sujeto)
+	return reserva.PerteneceACliente(perfilClienteID, sesion.Email), nil
+}
+
+// GetReservaPorCodigo permite al huésped consultar su reserva sin cuenta con el código de
+// confirmación y el email con el que reservó (?email=)
+func (h *ReservaHandler) GetReservaPorCodigo(c *fiber.Ctx) error {
+	codigo := c.Params("codigo")
+	emailHuesped := c.Query("email")
+	if strings.TrimSpace(codigo) == "" || strings.TrimSpace(emailHuesped) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "El código de reserva y el email son requeridos",
+		})
+	}
+
+	reserva, err := h.service.GetReservaPorCodigo(codigo, emailHuesped)
+	if err != nil {
+		if errors.Is(err, domain.ErrReservaNoEncontrada) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": reserva,
+	})
+}
+
 // GetReservasCliente obtiene todas las reservas de un cliente
 func (h *ReservaHandler) GetReservasCliente(c *fiber.Ctx) error {
 	clienteID := c.Params("clienteId")
@@ -183,10 +463,8 @@ func (h *ReservaHandler) UpdateReservaEstado(c *fiber.Ctx) error {
 	// Convertir el estado a EstadoReserva
 	estado := domain.EstadoReserva(req.Estado)
 
-	if err := h.service.UpdateReservaEstado(id, estado); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	if err := h.service.UpdateReservaEstado(id, estado, actorSolicitud(c)); err != nil {
+		return responderErrorReserva(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -204,14 +482,200 @@ func (h *ReservaHandler) CancelarReserva(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.service.CancelarReserva(id); err != nil {
+	penalidad, err := h.service.CancelarReserva(id, actorSolicitud(c))
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reserva cancelada exitosamente",
+		"data":    penalidad,
+	})
+}
+
+// PrevisualizarCancelacion muestra la penalidad y el reembolso de cancelar la reserva ahora
+func (h *ReservaHandler) PrevisualizarCancelacion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
+			"error": "ID de reserva inválido",
 		})
 	}
 
+	penalidad, err := h.service.PrevisualizarCancelacion(id)
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
 	return c.JSON(fiber.Map{
-		"message": "Reserva cancelada exitosamente",
+		"data": penalidad,
+	})
+}
+
+// CancelarHabitacion cancela una habitación de una reserva con varias habitaciones
+func (h *ReservaHandler) CancelarHabitacion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	habitacionID, err := strconv.Atoi(c.Params("habitacionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de habitación inválido",
+		})
+	}
+
+	penalidad, err := h.service.CancelarHabitacion(id, habitacionID, actorSolicitud(c))
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Habitación cancelada exitosamente",
+		"data":    penalidad,
+	})
+}
+
+// AsignarHabitaciones asigna habitaciones concretas a las habitaciones reservadas por tipo
+func (h *ReservaHandler) AsignarHabitaciones(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	var req AsignarHabitacionesRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de solicitud inválido",
+			})
+		}
+	}
+
+	reserva, err := h.service.AsignarHabitaciones(id, req.Asignaciones)
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Habitaciones asignadas exitosamente",
+		"data":    reserva,
+	})
+}
+
+// ReasignarHabitacion mueve una habitación de la reserva a otra para las mismas fechas
+func (h *ReservaHandler) ReasignarHabitacion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	habitacionID, err := strconv.Atoi(c.Params("habitacionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de habitación inválido",
+		})
+	}
+
+	var req ReasignarHabitacionRequest
+	if err := c.BodyParser(&req); err != nil || req.HabitacionID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "La nueva habitacionId es requerida",
+		})
+	}
+
+	reserva, err := h.service.ReasignarHabitacion(id, habitacionID, req.HabitacionID)
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Habitación reasignada exitosamente",
+		"data":    reserva,
+	})
+}
+
+// CheckIn registra la llegada de los huéspedes con sus documentos de identidad. La fecha se
+// valida contra la fecha de hoy en Perú.
+func (h *ReservaHandler) CheckIn(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	var req CheckInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	datos := application.CheckInReserva{Asignaciones: req.Asignaciones}
+	for _, huesped := range req.Huespedes {
+		datos.Huespedes = append(datos.Huespedes, domain.HuespedRegistrado{
+			HabitacionID:    huesped.HabitacionID,
+			Nombre:          huesped.Nombre,
+			TipoDocumento:   huesped.TipoDocumento,
+			NumeroDocumento: huesped.NumeroDocumento,
+			Nacionalidad:    huesped.Nacionalidad,
+		})
+	}
+
+	reserva, err := h.service.CheckIn(id, datos, horario.HoyPeru(), actorSolicitud(c))
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Check-in registrado exitosamente",
+		"data":    reserva,
+	})
+}
+
+// CheckOut registra la salida de los huéspedes con los cargos adicionales de la estadía y
+// completa la reserva. La fecha se valida contra la fecha de hoy en Perú.
+func (h *ReservaHandler) CheckOut(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	var req CheckOutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de solicitud inválido",
+			})
+		}
+	}
+
+	datos := application.CheckOutReserva{SalidaAnticipada: req.SalidaAnticipada}
+	for _, cargo := range req.Cargos {
+		datos.Cargos = append(datos.Cargos, domain.CargoAdicional{
+			Concepto: cargo.Concepto,
+			Monto:    cargo.Monto,
+		})
+	}
+
+	reserva, err := h.service.CheckOut(id, datos, horario.HoyPeru(), actorSolicitud(c))
+	if err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Check-out registrado exitosamente",
+		"data":    reserva,
 	})
 }
 
@@ -225,10 +689,8 @@ func (h *ReservaHandler) ConfirmarReserva(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.service.ConfirmarReserva(id); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	if err := h.service.ConfirmarReserva(id, actorSolicitud(c)); err != nil {
+		return responderErrorReserva(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -246,14 +708,33 @@ func (h *ReservaHandler) ConfirmarPago(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.service.ConfirmarReserva(id); err != nil {
+	if err := h.service.ConfirmarReserva(id, actorSolicitud(c)); err != nil {
+		return responderErrorReserva(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Pago confirmado y email enviado exitosamente",
+	})
+}
+
+// GetHistorialEstados obtiene el historial de cambios de estado de una reserva
+func (h *ReservaHandler) GetHistorialEstados(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de reserva inválido",
+		})
+	}
+
+	historial, err := h.service.GetHistorialEstados(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Pago confirmado y email enviado exitosamente",
+		"data": historial,
 	})
 }
 
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/horario"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RestriccionEstadiaHandler struct {
+	service *application.RestriccionEstadiaService
+}
+
+// NewRestriccionEstadiaHandler crea una nueva instancia del handler de restricciones de estadía
+func NewRestriccionEstadiaHandler(service *application.RestriccionEstadiaService) *RestriccionEstadiaHandler {
+	return &RestriccionEstadiaHandler{
+		service: service,
+	}
+}
+
+// RestriccionEstadiaRequest representa la petición para restringir un rango de fechas
+type RestriccionEstadiaRequest struct {
+	TipoHabitacionID *int   `json:"tipoHabitacionId"` // omitido aplica a todos los tipos
+	FechaInicio      string `json:"fechaInicio"`      // YYYY-MM-DD
+	FechaFin         string `json:"fechaFin"`         // YYYY-MM-DD, incluida
+	EstanciaMinima   int    `json:"estanciaMinima"`   // noches mínimas para llegar en la fecha
+	EstanciaMaxima   int    `json:"estanciaMaxima"`
+	CerradoLlegada   bool   `json:"cerradoLlegada"`
+	CerradoSalida    bool   `json:"cerradoSalida"`
+	Motivo           string `json:"motivo"`
+}
+
+// GetRestricciones lista las restricciones de estadía de un rango de fechas (?desde, ?hasta, ?tipo).
+// Por defecto muestra los próximos 3 meses.
+func (h *RestriccionEstadiaHandler) GetRestricciones(c *fiber.Ctx) error {
+	desde := horario.HoyPeru()
+	if desdeStr := c.Query("desde"); desdeStr != "" {
+		fecha, err := parseDatePeru(desdeStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de desde inválido. Use YYYY-MM-DD",
+			})
+		}
+		desde = fecha
+	}
+
+	hasta := desde.AddDate(0, 3, 0)
+	if hastaStr := c.Query("hasta"); hastaStr != "" {
+		fecha, err := parseDatePeru(hastaStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Formato de hasta inválido. Use YYYY-MM-DD",
+			})
+		}
+		hasta = fecha
+	}
+
+	tipoHabitacionID := 0
+	if tipoStr := c.Query("tipo"); tipoStr != "" {
+		id, err := strconv.Atoi(tipoStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "ID de tipo de habitación inválido",
+			})
+		}
+		tipoHabitacionID = id
+	}
+
+	restricciones, err := h.service.GetRestricciones(desde, hasta, tipoHabitacionID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": restricciones,
+	})
+}
+
+// GuardarRestricciones aplica la misma restricción a cada fecha del rango, reemplazando las que
+// ya existan para esas fechas y tipo
+func (h *RestriccionEstadiaHandler) GuardarRestricciones(c *fiber.Ctx) error {
+	var req RestriccionEstadiaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	fechaInicio, err := parseDatePeru(req.FechaInicio)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaInicio inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaFin, err := parseDatePeru(req.FechaFin)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaFin inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	restricciones, err := h.service.GuardarRango(application.RangoRestriccion{
+		TipoHabitacionID: req.TipoHabitacionID,
+		FechaInicio:      fechaInicio,
+		FechaFin:         fechaFin,
+		EstanciaMinima:   req.EstanciaMinima,
+		EstanciaMaxima:   req.EstanciaMaxima,
+		CerradoLlegada:   req.CerradoLlegada,
+		CerradoSalida:    req.CerradoSalida,
+		Motivo:           req.Motivo,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Restricciones guardadas exitosamente",
+		"data":    restricciones,
+	})
+}
+
+// DeleteRestriccion elimina la restricción de estadía de una fecha
+func (h *RestriccionEstadiaHandler) DeleteRestriccion(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de restricción inválido",
+		})
+	}
+
+	if err := h.service.DeleteRestriccion(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Restricción eliminada exitosamente",
+	})
+}
@@ -0,0 +1,258 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/auth"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// claveSesion es la clave de c.Locals donde queda la sesión verificada
+const claveSesion = "sesion"
+
+// Autorizador verifica las sesiones de las solicitudes y los permisos de cada ruta. Las sesiones
+// del personal se validan contra su usuario en cada solicitud, por lo que desactivarlo o cambiar
+// su rol o contraseña tiene efecto inmediato.
+type Autorizador struct {
+	firmador *auth.Firmador
+	// claveAdmin, si está configurada, da acceso como admin con el header X-Admin-Key
+	claveAdmin string
+	usuarios   *application.UsuarioService
+}
+
+// NewAutorizador crea el autorizador de las rutas. Sin firmador solo se acepta la clave de admin.
+func NewAutorizador(firmador *auth.Firmador, claveAdmin string, usuarios *application.UsuarioService) *Autorizador {
+	return &Autorizador{
+		firmador:   firmador,
+		claveAdmin: claveAdmin,
+		usuarios:   usuarios,
+	}
+}
+
+// RequireRol protege una ruta del personal: exige la sesión de un usuario con alguno de los
+// roles dados; admin siempre tiene acceso
+func (a *Autorizador) RequireRol(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sesion, err := a.autenticar(c)
+		if err != nil {
+			return denegarAcceso(c, err)
+		}
+
+		if !sesion.esPersonal(roles) {
+			return denegarAcceso(c, &accesoDenegado{status: fiber.StatusForbidden, motivo: "rol sin permiso para la ruta", sesion: sesion.Claims})
+		}
+
+		c.Locals(claveSesion, sesion.Claims)
+		return c.Next()
+	}
+}
+
+// RequireSesionCliente exige la sesión del cliente del parámetro de ruta indicado, identificado
+// por su ID o su email, o la de un usuario del personal con alguno de los roles dados
+func (a *Autorizador) RequireSesionCliente(parametro string, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sesion, err := a.autenticar(c)
+		if err != nil {
+			return denegarAcceso(c, err)
+		}
+
+		if sesion.Rol == domain.RolCliente {
+			clienteID, err := url.PathUnescape(c.Params(parametro))
+			if err != nil || (clienteID != sesion.Sujeto && !strings.EqualFold(clienteID, sesion.Email)) {
+				return denegarAcceso(c, &accesoDenegado{status: fiber.StatusForbidden, motivo: "datos de otro cliente", sesion: sesion.Claims})
+			}
+		} else if !sesion.esPersonal(roles) {
+			return denegarAcceso(c, &accesoDenegado{status: fiber.StatusForbidden, motivo: "rol sin permiso para la ruta", sesion: sesion.Claims})
+		}
+
+		c.Locals(claveSesion, sesion.Claims)
+		return c.Next()
+	}
+}
+
+// Propietario indica si los datos de la ruta pertenecen al cliente de la sesión
+type Propietario func(c *fiber.Ctx, sesion *auth.Claims) (bool, error)
+
+// RequirePropietario exige la sesión del cliente dueño de los datos de la ruta, según la función
+// propietario, o la de un usuario del personal con alguno de los roles dados
+func (a *Autorizador) RequirePropietario(propietario Propietario, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sesion, err := a.autenticar(c)
+		if err != nil {
+			return denegarAcceso(c, err)
+		}
+
+		if sesion.Rol == domain.RolCliente {
+			esPropietario, err := propietario(c, sesion.Claims)
+			if err != nil {
+				return denegarAcceso(c, err)
+			}
+			if !esPropietario {
+				return denegarAcceso(c, &accesoDenegado{status: fiber.StatusForbidden, motivo: "datos de otro cliente", sesion: sesion.Claims})
+			}
+		} else if !sesion.esPersonal(roles) {
+			return denegarAcceso(c, &accesoDenegado{status: fiber.StatusForbidden, motivo: "rol sin permiso para la ruta", sesion: sesion.Claims})
+		}
+
+		c.Locals(claveSesion, sesion.Claims)
+		return c.Next()
+	}
+}
+
+// SesionOpcional verifica la sesión de la solicitud si trae una, para las rutas públicas que
+// muestran más datos a los clientes identificados. Una sesión inválida se rechaza.
+func (a *Autorizador) SesionOpcional() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) == "" && c.Get("X-Admin-Key") == "" {
+			return c.Next()
+		}
+
+		sesion, err := a.autenticar(c)
+		if err != nil {
+			return denegarAcceso(c, err)
+		}
+
+		c.Locals(claveSesion, sesion.Claims)
+		return c.Next()
+	}
+}
+
+// sesionVerificada es una sesión cuyo token y, si es del personal, cuyo usuario ya se validaron
+type sesionVerificada struct {
+	*auth.Claims
+}
+
+// esPersonal indica si la sesión es de admin o de un usuario del personal con alguno de los roles
+func (s sesionVerificada) esPersonal(roles []string) bool {
+	return s.Rol == domain.RolAdmin || (s.Rol != domain.RolCliente && slices.Contains(roles, s.Rol))
+}
+
+// accesoDenegado es el motivo por el que se rechaza una solicitud, que se registra en el log, y
+// el status HTTP con que se responde
+type accesoDenegado struct {
+	status int
+	motivo string
+	sesion *auth.Claims
+	// mensaje reemplaza el mensaje de la respuesta propio del status
+	mensaje string
+}
+
+func (e *accesoDenegado) Error() string {
+	return e.motivo
+}
+
+// autenticar identifica a quién hace la solicitud: la clave de admin del header X-Admin-Key o el
+// token Bearer. En las sesiones del personal el rol es el actual del usuario.
+func (a *Autorizador) autenticar(c *fiber.Ctx) (sesionVerificada, error) {
+	if claveRecibida := c.Get("X-Admin-Key"); claveRecibida != "" {
+		if a.claveAdmin == "" || subtle.ConstantTimeCompare([]byte(claveRecibida), []byte(a.claveAdmin)) != 1 {
+			return sesionVerificada{}, &accesoDenegado{status: fiber.StatusUnauthorized, motivo: "clave de administración inválida"}
+		}
+		return sesionVerificada{&auth.Claims{Sujeto: "admin-key", Rol: domain.RolAdmin}}, nil
+	}
+
+	if a.firmador == nil {
+		return sesionVerificada{}, &accesoDenegado{status: fiber.StatusServiceUnavailable, motivo: "inicio de sesión no habilitado"}
+	}
+
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok {
+		return sesionVerificada{}, &accesoDenegado{status: fiber.StatusUnauthorized, motivo: "sin token de sesión"}
+	}
+
+	sesion, err := a.firmador.Verificar(strings.TrimSpace(token), time.Now())
+	if err != nil {
+		return sesionVerificada{}, &accesoDenegado{status: fiber.StatusUnauthorized, motivo: "token inválido o vencido"}
+	}
+
+	if sesion.Rol != domain.RolCliente {
+		usuario, err := a.usuarios.ValidarSesion(sesion)
+		if errors.Is(err, domain.ErrSesionRevocada) {
+			return sesionVerificada{}, &accesoDenegado{
+				status:  fiber.StatusUnauthorized,
+				motivo:  "sesión revocada",
+				sesion:  sesion,
+				mensaje: err.Error(),
+			}
+		}
+		if err != nil {
+			return sesionVerificada{}, err
+		}
+		sesion.Rol = usuario.Rol
+	}
+
+	return sesionVerificada{sesion}, nil
+}
+
+// denegarAcceso registra el intento rechazado para la revisión de seguridad y responde con el
+// status del motivo
+func denegarAcceso(c *fiber.Ctx, err error) error {
+	var denegado *accesoDenegado
+	if !errors.As(err, &denegado) {
+		log.Printf("Error al verificar la sesión de %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al verificar la sesión",
+		})
+	}
+
+	quien := "anónimo"
+	if denegado.sesion != nil {
+		quien = denegado.sesion.Rol + ":" + denegado.sesion.Sujeto
+	}
+	log.Printf("Acceso denegado: %s %s desde %s (%s): %s", c.Method(), c.Path(), c.IP(), quien, denegado.motivo)
+
+	mensaje := denegado.mensaje
+	if mensaje == "" {
+		switch denegado.status {
+		case fiber.StatusForbidden:
+			mensaje = "No tiene permiso para realizar esta acción"
+		case fiber.StatusServiceUnavailable:
+			mensaje = "El inicio de sesión no está habilitado"
+		default:
+			mensaje = "No autorizado"
+		}
+	}
+	return c.Status(denegado.status).JSON(fiber.Map{
+		"error": mensaje,
+	})
+}
+
+// puedeVerCliente indica si la sesión puede ver los datos del perfil de cliente dado: la del
+// propio cliente o la de un usuario del personal con alguno de los roles
+func puedeVerCliente(sesion *auth.Claims, clienteID int, roles ...string) bool {
+	if sesion == nil {
+		return false
+	}
+	if sesion.Rol == domain.RolCliente {
+		return sesion.Sujeto == strconv.Itoa(clienteID)
+	}
+	return sesionVerificada{sesion}.esPersonal(roles)
+}
+
+// clienteSesion retorna el ID del perfil del cliente de la sesión, o nil si no es la de un cliente
+func clienteSesion(c *fiber.Ctx) *int {
+	sesion := sesionSolicitud(c)
+	if sesion == nil || sesion.Rol != domain.RolCliente {
+		return nil
+	}
+	clienteID, err := strconv.Atoi(sesion.Sujeto)
+	if err != nil {
+		return nil
+	}
+	return &clienteID
+}
+
+// sesionSolicitud retorna la sesión verificada de la solicitud, o nil si no tiene
+func sesionSolicitud(c *fiber.Ctx) *auth.Claims {
+	sesion, _ := c.Locals(claveSesion).(*auth.Claims)
+	return sesion
+}
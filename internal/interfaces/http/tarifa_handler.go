@@ -0,0 +1,187 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TarifaHandler struct {
+	service *application.TarifaService
+}
+
+// NewTarifaHandler crea una nueva instancia del handler de tarifas
+func NewTarifaHandler(service *application.TarifaService) *TarifaHandler {
+	return &TarifaHandler{
+		service: service,
+	}
+}
+
+// PlanTarifaRequest representa la petición para crear o actualizar un plan de tarifa
+type PlanTarifaRequest struct {
+	Nombre           string   `json:"nombre"`
+	Tipo             string   `json:"tipo"` // FinDeSemana, Temporada, Estadia u Ocupacion
+	TipoHabitacionID *int     `json:"tipoHabitacionId"`
+	Porcentaje       float64  `json:"porcentaje"`
+	FechaInicio      string   `json:"fechaInicio"` // Formato: YYYY-MM-DD (opcional)
+	FechaFin         string   `json:"fechaFin"`    // Formato: YYYY-MM-DD (opcional)
+	MinNoches        int      `json:"minNoches"`
+	OcupacionMin     *float64 `json:"ocupacionMin"`
+	OcupacionMax     *float64 `json:"ocupacionMax"`
+	DiasAnticipacion *int     `json:"diasAnticipacion"`
+	Activo           *bool    `json:"activo"`
+}
+
+// toPlanTarifa convierte la petición en un plan de tarifa del dominio
+func (req PlanTarifaRequest) toPlanTarifa() (*domain.PlanTarifa, error) {
+	plan := &domain.PlanTarifa{
+		Nombre:           req.Nombre,
+		Tipo:             domain.TipoPlanTarifa(req.Tipo),
+		TipoHabitacionID: req.TipoHabitacionID,
+		Porcentaje:       req.Porcentaje,
+		MinNoches:        req.MinNoches,
+		OcupacionMin:     req.OcupacionMin,
+		OcupacionMax:     req.OcupacionMax,
+		DiasAnticipacion: req.DiasAnticipacion,
+		Activo:           true,
+	}
+
+	if req.Activo != nil {
+		plan.Activo = *req.Activo
+	}
+
+	if req.FechaInicio != "" {
+		fecha, err := time.Parse("2006-01-02", req.FechaInicio)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaInicio inválido. Use YYYY-MM-DD")
+		}
+		plan.FechaInicio = &fecha
+	}
+
+	if req.FechaFin != "" {
+		fecha, err := time.Parse("2006-01-02", req.FechaFin)
+		if err != nil {
+			return nil, fmt.Errorf("Formato de fechaFin inválido. Use YYYY-MM-DD")
+		}
+		plan.FechaFin = &fecha
+	}
+
+	return plan, nil
+}
+
+// Cotizar calcula el precio de una habitación para un rango de fechas
+func (h *TarifaHandler) Cotizar(c *fiber.Ctx) error {
+	habitacionID, err := strconv.Atoi(c.Query("habitacionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "habitacionId inválido",
+		})
+	}
+
+	fechaEntrada, err := time.Parse("2006-01-02", c.Query("fechaEntrada"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaEntrada inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	fechaSalida, err := time.Parse("2006-01-02", c.Query("fechaSalida"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de fechaSalida inválido. Use YYYY-MM-DD",
+		})
+	}
+
+	cotizacion, err := h.service.CotizarHabitacion(habitacionID, fechaEntrada, fechaSalida)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": cotizacion,
+	})
+}
+
+// GetPlanes obtiene todos los planes de tarifa
+func (h *TarifaHandler) GetPlanes(c *fiber.Ctx) error {
+	planes, err := h.service.GetPlanes()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": planes,
+	})
+}
+
+// CreatePlan crea un nuevo plan de tarifa
+func (h *TarifaHandler) CreatePlan(c *fiber.Ctx) error {
+	var req PlanTarifaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	plan, err := req.toPlanTarifa()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.service.CreatePlan(plan); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Plan de tarifa creado exitosamente",
+		"data":    plan,
+	})
+}
+
+// UpdatePlan actualiza un plan de tarifa existente
+func (h *TarifaHandler) UpdatePlan(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de plan inválido",
+		})
+	}
+
+	var req PlanTarifaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	plan, err := req.toPlanTarifa()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	plan.ID = id
+
+	if err := h.service.UpdatePlan(plan); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Plan de tarifa actualizado exitosamente",
+		"data":    plan,
+	})
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/Maxito7/hotel_backend/internal/application"
+	"github.com/Maxito7/hotel_backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+type UsuarioHandler struct {
+	service *application.UsuarioService
+}
+
+// NewUsuarioHandler crea una nueva instancia del handler de usuarios del personal
+func NewUsuarioHandler(service *application.UsuarioService) *UsuarioHandler {
+	return &UsuarioHandler{
+		service: service,
+	}
+}
+
+// LoginRequest representa la petición de inicio de sesión del personal
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UsuarioRequest representa los datos de un usuario del personal
+type UsuarioRequest struct {
+	Email    string `json:"email"`
+	Nombre   string `json:"nombre"`
+	Rol      string `json:"rol"` // admin, recepcion, revenue o marketing
+	Password string `json:"password"`
+	Activo   *bool  `json:"activo"` // solo al actualizar; omitido conserva el usuario activo
+}
+
+// PasswordRequest representa la petición de cambio de contraseña
+type PasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// Login inicia la sesión de un usuario del personal y retorna su token
+func (h *UsuarioHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	sesion, err := h.service.Login(req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domain.ErrCredencialesInvalidas) {
+			log.Printf("Acceso denegado: inicio de sesión fallido de %q desde %s", req.Email, c.IP())
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, application.ErrAccesoDeshabilitado) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al iniciar sesión",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Sesión iniciada exitosamente",
+		"data":    sesion,
+	})
+}
+
+// GetUsuarios lista los usuarios del personal
+func (h *UsuarioHandler) GetUsuarios(c *fiber.Ctx) error {
+	usuarios, err := h.service.GetUsuarios()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al obtener los usuarios",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": usuarios,
+	})
+}
+
+// CreateUsuario registra un usuario del personal
+func (h *UsuarioHandler) CreateUsuario(c *fiber.Ctx) error {
+	var req UsuarioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	usuario := &domain.Usuario{
+		Email:  req.Email,
+		Nombre: req.Nombre,
+		Rol:    req.Rol,
+	}
+	if err := h.service.CreateUsuario(usuario, req.Password); err != nil {
+		return responderErrorUsuario(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Usuario creado exitosamente",
+		"data":    usuario,
+	})
+}
+
+// UpdateUsuario actualiza el nombre, el rol y si el usuario está activo
+func (h *UsuarioHandler) UpdateUsuario(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de usuario inválido",
+		})
+	}
+
+	var req UsuarioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	usuario := &domain.Usuario{
+		ID:     id,
+		Nombre: req.Nombre,
+		Rol:    req.Rol,
+		Activo: req.Activo == nil || *req.Activo,
+	}
+	if err := h.service.UpdateUsuario(usuario); err != nil {
+		return responderErrorUsuario(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Usuario actualizado exitosamente",
+		"data":    usuario,
+	})
+}
+
+// CambiarPassword reemplaza la contraseña de un usuario del personal
+func (h *UsuarioHandler) CambiarPassword(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de usuario inválido",
+		})
+	}
+
+	var req PasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Formato de solicitud inválido",
+		})
+	}
+
+	if err := h.service.CambiarPassword(id, req.Password); err != nil {
+		return responderErrorUsuario(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Contraseña actualizada exitosamente",
+	})
+}
+
+// responderErrorUsuario traduce los errores de los usuarios a respuestas HTTP
+func responderErrorUsuario(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUsuarioNoEncontrado):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrUsuarioDuplicado):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Tarea es un trabajo en segundo plano. Un error se registra en el log sin detener el job.
+type Tarea func() error
+
+// RunEvery ejecuta la tarea al iniciar y luego cada intervalo hasta que se cancele el contexto.
+// Debe lanzarse en su propia goroutine.
+func RunEvery(ctx context.Context, nombre string, intervalo time.Duration, tarea Tarea) {
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	log.Printf("Job %s iniciado (cada %s)", nombre, intervalo)
+	for {
+		ejecutar(nombre, tarea)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Job %s detenido", nombre)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ejecutar corre la tarea protegiendo al proceso de un panic
+func ejecutar(nombre string, tarea Tarea) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s: panic: %v", nombre, r)
+		}
+	}()
+
+	if err := tarea(); err != nil {
+		log.Printf("Job %s: %v", nombre, err)
+	}
+}
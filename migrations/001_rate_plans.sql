@@ -0,0 +1,18 @@
+-- Planes de tarifa configurables y desglose de precio por noche en cada habitación reservada
+
+CREATE TABLE IF NOT EXISTS rate_plan (
+    rate_plan_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('FinDeSemana', 'Temporada', 'Estadia')),
+    room_type_id INTEGER REFERENCES room_type(room_type_id),
+    percentage NUMERIC(6, 2) NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    min_nights INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_plan_room_type ON rate_plan (room_type_id) WHERE active;
+
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
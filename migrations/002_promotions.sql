@@ -0,0 +1,32 @@
+-- Códigos promocionales y registro de canjes por reserva
+
+CREATE TABLE IF NOT EXISTS promotion (
+    promotion_id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('Porcentaje', 'Monto')),
+    value NUMERIC(10, 2) NOT NULL CHECK (value > 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    room_type_ids INTEGER[] NOT NULL DEFAULT '{}',
+    min_nights INTEGER NOT NULL DEFAULT 0,
+    max_uses INTEGER,
+    max_uses_per_client INTEGER,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotion_code ON promotion (UPPER(code));
+
+CREATE TABLE IF NOT EXISTS promotion_redemption (
+    redemption_id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotion(promotion_id),
+    reservation_id INTEGER NOT NULL UNIQUE REFERENCES reservation(reservation_id),
+    client_id VARCHAR(255) NOT NULL,
+    discount NUMERIC(10, 2) NOT NULL,
+    redeemed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemption_promotion ON promotion_redemption (promotion_id, client_id);
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES promotion(promotion_id);
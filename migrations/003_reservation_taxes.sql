@@ -0,0 +1,10 @@
+-- Desglose de cargo por servicio, IGV y total, y documento del huésped para la exoneración de IGV
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS service_charge NUMERIC(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS igv NUMERIC(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS igv_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS total NUMERIC(10, 2);
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS guest_document_type VARCHAR(20)
+    CHECK (guest_document_type IN ('DNI', 'CE', 'Pasaporte'));
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS guest_document_number VARCHAR(20);
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS guest_nationality VARCHAR(2);
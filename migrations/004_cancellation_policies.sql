@@ -0,0 +1,22 @@
+-- Políticas de cancelación por tipo de habitación o plan de tarifa y penalidad registrada en la reserva
+-- o, si se cancela una sola habitación, en su reservation_room
+
+CREATE TABLE IF NOT EXISTS cancellation_policy (
+    cancellation_policy_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    free_hours INTEGER NOT NULL CHECK (free_hours >= 0),
+    penalty_type VARCHAR(20) NOT NULL CHECK (penalty_type IN ('Porcentaje', 'PrimeraNoche')),
+    percentage NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    room_type_id INTEGER REFERENCES room_type(room_type_id),
+    rate_plan_id INTEGER REFERENCES rate_plan(rate_plan_id),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (room_type_id IS NOT NULL OR rate_plan_id IS NOT NULL)
+);
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS cancellation_penalty NUMERIC(10, 2);
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS refund_amount NUMERIC(10, 2);
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS cancellation_penalty NUMERIC(10, 2);
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS refund_amount NUMERIC(10, 2);
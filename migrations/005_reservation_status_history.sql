@@ -0,0 +1,16 @@
+-- Ciclo de vida de la reserva: nuevos estados, historial de transiciones y restauración de
+-- habitaciones al reactivar una reserva cancelada
+
+CREATE TABLE IF NOT EXISTS reservation_status_history (
+    history_id SERIAL PRIMARY KEY,
+    reservation_id INTEGER NOT NULL REFERENCES reservation(reservation_id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_status_history_reservation
+    ON reservation_status_history (reservation_id, changed_at);
+
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
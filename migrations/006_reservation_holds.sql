@@ -0,0 +1,6 @@
+-- Hold de las reservas pendientes: vencimiento tras el cual se liberan sus habitaciones
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_reservation_pending_expires_at
+    ON reservation (expires_at) WHERE status = 'Pendiente';
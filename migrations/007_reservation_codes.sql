@@ -0,0 +1,30 @@
+-- Código de confirmación no secuencial de las reservas, ej. HIN-7K3Q9P
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS code VARCHAR(20);
+
+-- El índice admite varios NULL, así que se crea antes de asignar los códigos y sirve para
+-- detectar colisiones durante la asignación
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_code ON reservation (code);
+
+-- Asignar un código a las reservas existentes con el mismo formato y alfabeto que
+-- generarCodigoReserva, reintentando si el código ya existe
+DO $$
+DECLARE
+    alfabeto CONSTANT TEXT := '23456789ABCDEFGHJKMNPQRSTUVWXYZ';
+    reserva RECORD;
+    codigo TEXT;
+BEGIN
+    FOR reserva IN SELECT reservation_id FROM reservation WHERE code IS NULL LOOP
+        LOOP
+            codigo := 'HIN-';
+            FOR i IN 1..6 LOOP
+                codigo := codigo || substr(alfabeto, 1 + floor(random() * length(alfabeto))::INTEGER, 1);
+            END LOOP;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM reservation WHERE code = codigo);
+        END LOOP;
+
+        UPDATE reservation SET code = codigo WHERE reservation_id = reserva.reservation_id;
+    END LOOP;
+END $$;
+
+ALTER TABLE reservation ALTER COLUMN code SET NOT NULL;
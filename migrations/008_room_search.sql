@@ -0,0 +1,5 @@
+-- Amenidades por tipo de habitación para la búsqueda de habitaciones (ej. wifi, jacuzzi, vista-mar)
+
+ALTER TABLE room_type ADD COLUMN IF NOT EXISTS amenities TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_room_type_amenities ON room_type USING GIN (amenities);
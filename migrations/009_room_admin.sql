@@ -0,0 +1,7 @@
+-- Administración de habitaciones y tipos de habitación
+
+-- Los tipos de habitación se desactivan en lugar de eliminarse
+ALTER TABLE room_type ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- El número de habitación es único
+CREATE UNIQUE INDEX IF NOT EXISTS idx_room_number ON room (number);
@@ -0,0 +1,14 @@
+-- Bloqueos de habitaciones por mantenimiento: la habitación no se puede reservar en el rango
+-- [start_date, end_date), con el mismo criterio que las estadías
+
+CREATE TABLE IF NOT EXISTS room_block (
+    block_id SERIAL PRIMARY KEY,
+    room_id INTEGER NOT NULL REFERENCES room(room_id),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_block_room_dates ON room_block (room_id, start_date, end_date);
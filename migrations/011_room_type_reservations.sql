@@ -0,0 +1,25 @@
+-- Reservas por tipo de habitación: se reserva una cantidad de habitaciones de un tipo y las
+-- habitaciones concretas se asignan después (al confirmar o al hacer check-in)
+
+CREATE TABLE IF NOT EXISTS reservation_room_type (
+    reservation_room_type_id SERIAL PRIMARY KEY,
+    reservation_id INTEGER NOT NULL REFERENCES reservation(reservation_id),
+    room_type_id INTEGER NOT NULL REFERENCES room_type(room_type_id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price NUMERIC(10, 2) NOT NULL,
+    check_in_date TIMESTAMP NOT NULL,
+    check_out_date TIMESTAMP NOT NULL,
+    status INTEGER NOT NULL DEFAULT 1, -- 1: por asignar, 2: asignada, 0: cancelada
+    price_breakdown JSONB,
+    cancelled_at TIMESTAMP,
+    CHECK (check_out_date > check_in_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_room_type_reservation ON reservation_room_type (reservation_id);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_room_type_pending
+    ON reservation_room_type (room_type_id, check_in_date, check_out_date) WHERE status = 1;
+
+-- Habitación asignada a partir de una reserva por tipo
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS reservation_room_type_id INTEGER
+    REFERENCES reservation_room_type(reservation_room_type_id);
@@ -0,0 +1,18 @@
+-- Restricciones de estadía por fecha y tipo de habitación: estadía mínima y máxima para las
+-- llegadas de la fecha, cerrado a llegadas (CTA) y cerrado a salidas (CTD)
+
+CREATE TABLE IF NOT EXISTS stay_restriction (
+    restriction_id SERIAL PRIMARY KEY,
+    room_type_id INTEGER REFERENCES room_type(room_type_id), -- NULL aplica a todos los tipos
+    restriction_date DATE NOT NULL,
+    min_stay INTEGER NOT NULL DEFAULT 0 CHECK (min_stay >= 0), -- 0: sin mínimo
+    max_stay INTEGER NOT NULL DEFAULT 0 CHECK (max_stay >= 0), -- 0: sin máximo
+    closed_to_arrival BOOLEAN NOT NULL DEFAULT FALSE,
+    closed_to_departure BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT,
+    CHECK (max_stay = 0 OR max_stay >= min_stay)
+);
+
+-- Una restricción por fecha y tipo (o general)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stay_restriction_date_type
+    ON stay_restriction ((COALESCE(room_type_id, 0)), restriction_date);
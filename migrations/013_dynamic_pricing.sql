@@ -0,0 +1,19 @@
+-- Tarifas dinámicas por ocupación: planes que ajustan el precio de la noche según el porcentaje
+-- de habitaciones ocupadas del tipo en esa fecha, y precio mínimo y máximo por tipo de habitación
+
+ALTER TABLE rate_plan DROP CONSTRAINT IF EXISTS rate_plan_kind_check;
+ALTER TABLE rate_plan ADD CONSTRAINT rate_plan_kind_check
+    CHECK (kind IN ('FinDeSemana', 'Temporada', 'Estadia', 'Ocupacion'));
+
+-- Rango de ocupación [min_occupancy, max_occupancy) en porcentaje; NULL no limita ese extremo
+ALTER TABLE rate_plan ADD COLUMN IF NOT EXISTS min_occupancy NUMERIC(5, 2)
+    CHECK (min_occupancy BETWEEN 0 AND 100);
+ALTER TABLE rate_plan ADD COLUMN IF NOT EXISTS max_occupancy NUMERIC(5, 2)
+    CHECK (max_occupancy BETWEEN 0 AND 100);
+-- Solo aplica a las noches que están a lo sumo a estos días de hoy; NULL aplica siempre
+ALTER TABLE rate_plan ADD COLUMN IF NOT EXISTS max_days_ahead INTEGER
+    CHECK (max_days_ahead >= 0);
+
+-- Límites del precio dinámico por noche; NULL sin límite
+ALTER TABLE room_type ADD COLUMN IF NOT EXISTS min_price NUMERIC(10, 2) CHECK (min_price > 0);
+ALTER TABLE room_type ADD COLUMN IF NOT EXISTS max_price NUMERIC(10, 2) CHECK (max_price > 0);
@@ -0,0 +1,31 @@
+-- Operaciones de recepción: check-in con los documentos de los huéspedes, check-out con los
+-- cargos adicionales de la estadía y estado de limpieza de las habitaciones
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS check_in_at TIMESTAMP;  -- llegada real
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS check_out_at TIMESTAMP; -- salida real
+
+CREATE TABLE IF NOT EXISTS reservation_guest (
+    guest_id SERIAL PRIMARY KEY,
+    reservation_id INTEGER NOT NULL REFERENCES reservation(reservation_id),
+    room_id INTEGER REFERENCES room(room_id), -- habitación donde se aloja, si se indicó
+    full_name VARCHAR(150) NOT NULL,
+    document_type VARCHAR(20) NOT NULL,
+    document_number VARCHAR(30) NOT NULL,
+    nationality VARCHAR(2),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_guest_reservation ON reservation_guest (reservation_id);
+
+CREATE TABLE IF NOT EXISTS reservation_charge (
+    charge_id SERIAL PRIMARY KEY,
+    reservation_id INTEGER NOT NULL REFERENCES reservation(reservation_id),
+    description VARCHAR(150) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_charge_reservation ON reservation_charge (reservation_id);
+
+ALTER TABLE room ADD COLUMN IF NOT EXISTS housekeeping_status VARCHAR(20) NOT NULL DEFAULT 'Limpia'
+    CHECK (housekeeping_status IN ('Limpia', 'Ocupada', 'Sucia'));
@@ -0,0 +1,22 @@
+-- Lista de espera para fechas agotadas: cuando se liberan habitaciones se ofrece, en orden de
+-- registro, una reserva pendiente con hold a la primera solicitud que se pueda alojar
+
+CREATE TABLE IF NOT EXISTS waitlist_request (
+    request_id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    check_in_date DATE NOT NULL,
+    check_out_date DATE NOT NULL,
+    adults INTEGER NOT NULL CHECK (adults >= 1),
+    children INTEGER NOT NULL DEFAULT 0 CHECK (children >= 0),
+    -- tipos de habitación preferidos en orden de preferencia; vacío acepta cualquier tipo
+    room_type_ids INTEGER[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'Esperando'
+        CHECK (status IN ('Esperando', 'Ofertada', 'Aceptada', 'Vencida', 'Cancelada')),
+    reservation_id INTEGER REFERENCES reservation(reservation_id),
+    offer_token VARCHAR(64) UNIQUE,
+    offer_expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (check_out_date > check_in_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_request_status ON waitlist_request (status, created_at);
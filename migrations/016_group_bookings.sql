@@ -0,0 +1,19 @@
+-- Reservas de grupo: un cupo de habitaciones por tipo para un rango de fechas con fecha de
+-- liberación. La rooming list asigna habitaciones y huéspedes; al llegar la fecha de liberación
+-- las habitaciones del cupo sin asignar vuelven al inventario.
+
+CREATE TABLE IF NOT EXISTS reservation_group (
+    group_id SERIAL PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    contact_email VARCHAR(255) NOT NULL,
+    reservation_id INTEGER NOT NULL UNIQUE REFERENCES reservation(reservation_id),
+    release_date DATE NOT NULL,
+    released_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_group_release ON reservation_group (release_date)
+    WHERE released_at IS NULL;
+
+-- Huésped de cada habitación según la rooming list
+ALTER TABLE reservation_room ADD COLUMN IF NOT EXISTS guest_name VARCHAR(150);
@@ -0,0 +1,50 @@
+-- Perfil de cliente: un registro por email que vincula sus reservas, sus conversaciones con el
+-- chatbot y sus formularios de contacto. reservation.client_id conserva el email de la reserva.
+
+CREATE TABLE IF NOT EXISTS client_profile (
+    client_profile_id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE, -- siempre en minúsculas
+    full_name VARCHAR(150),
+    phone VARCHAR(20),
+    document_type VARCHAR(20),
+    document_number VARCHAR(30),
+    nationality VARCHAR(2),
+    preferences TEXT,
+    notes TEXT, -- notas internas del hotel, no se muestran al huésped
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE reservation ADD COLUMN IF NOT EXISTS client_profile_id INTEGER REFERENCES client_profile(client_profile_id);
+CREATE INDEX IF NOT EXISTS idx_reservation_client_profile ON reservation (client_profile_id);
+
+ALTER TABLE contact_form ADD COLUMN IF NOT EXISTS client_profile_id INTEGER REFERENCES client_profile(client_profile_id);
+
+-- Las conversaciones nuevas deben referir a un perfil; las anteriores no se validan
+ALTER TABLE conversation_history DROP CONSTRAINT IF EXISTS fk_conversation_history_client_profile;
+ALTER TABLE conversation_history ADD CONSTRAINT fk_conversation_history_client_profile
+    FOREIGN KEY (client_id) REFERENCES client_profile(client_profile_id) NOT VALID;
+
+-- Perfiles de los clientes que ya reservaron o escribieron al hotel
+INSERT INTO client_profile (email, full_name, phone)
+SELECT DISTINCT ON (lower(trim(email))) lower(trim(email)), name, phone
+FROM contact_form
+WHERE email LIKE '%@%'
+ORDER BY lower(trim(email)), sent_date DESC
+ON CONFLICT (email) DO NOTHING;
+
+INSERT INTO client_profile (email)
+SELECT DISTINCT lower(trim(client_id))
+FROM reservation
+WHERE client_id LIKE '%@%'
+ON CONFLICT (email) DO NOTHING;
+
+UPDATE reservation r
+SET client_profile_id = c.client_profile_id
+FROM client_profile c
+WHERE c.email = lower(trim(r.client_id)) AND r.client_profile_id IS NULL;
+
+UPDATE contact_form f
+SET client_profile_id = c.client_profile_id
+FROM client_profile c
+WHERE c.email = lower(trim(f.email)) AND f.client_profile_id IS NULL;
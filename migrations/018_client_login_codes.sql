@@ -0,0 +1,17 @@
+-- Códigos de un solo uso enviados por email para que el cliente inicie sesión sin contraseña.
+-- Solo se guarda el hash del código. El perfil del cliente se crea recién al verificarlo, por lo
+-- que el código se asocia al email; la IP de la solicitud permite limitar los envíos.
+
+CREATE TABLE IF NOT EXISTS client_login_code (
+    login_code_id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    used_at TIMESTAMP,
+    requested_ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_login_code_email ON client_login_code (email, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_client_login_code_ip ON client_login_code (requested_ip, created_at DESC);
@@ -0,0 +1,16 @@
+-- Usuarios del personal del hotel con contraseña y rol. Los permisos de cada rol se aplican por
+-- ruta en el servidor; admin tiene acceso a todas. token_version aumenta al cambiar el rol, el
+-- estado o la contraseña del usuario y revoca los tokens emitidos antes.
+
+CREATE TABLE IF NOT EXISTS staff_user (
+    staff_user_id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE, -- siempre en minúsculas
+    full_name VARCHAR(150) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'recepcion', 'revenue', 'marketing')),
+    password_hash VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    token_version INTEGER NOT NULL DEFAULT 0,
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
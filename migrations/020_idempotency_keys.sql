@@ -0,0 +1,18 @@
+-- Respuestas guardadas por clave de idempotencia (header Idempotency-Key) para repetirlas cuando
+-- el cliente reintenta la misma solicitud. Sin status_code la solicitud original sigue en proceso;
+-- si no termina antes de locked_until (se cayó o venció su timeout) un reintento la reemplaza.
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    idempotency_key VARCHAR(255) NOT NULL,
+    route VARCHAR(255) NOT NULL, -- método y ruta, ej. POST /api/reservas
+    request_hash CHAR(64) NOT NULL, -- SHA-256 del cuerpo de la solicitud
+    status_code INTEGER,
+    content_type VARCHAR(100),
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires ON idempotency_key (expires_at);